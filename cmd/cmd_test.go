@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/executil"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// testEnv guarda as dependências fake usadas por um teste de comando
+type testEnv struct {
+	runner *executil.Fake
+	client *k8s.KubernetesClient
+	// stderr é a saída de erro da última execução
+	stderr string
+	wg     sync.WaitGroup
+}
+
+// newTestEnv substitui as dependências externas dos comandos por fakes e as
+// restaura ao final do teste. Sem client, newKubernetesClient retorna um erro. O
+// HOME aponta para um diretório temporário e a entrada padrão nunca é um
+// terminal.
+func newTestEnv(t *testing.T, client *k8s.KubernetesClient) *testEnv {
+	t.Helper()
+	env := &testEnv{runner: executil.NewFake(), client: client}
+
+	prevRunner, prevClient, prevWait := runner, newKubernetesClient, waitForPodsReady
+	prevLatest, prevDelay, prevTerminal := latestVersion, backendStartupDelay, stdinIsTerminal
+	prevPortForward := setupPortForward
+	prevOpts := k8s.CurrentClientOptions()
+	t.Cleanup(func() {
+		runner, newKubernetesClient, waitForPodsReady = prevRunner, prevClient, prevWait
+		latestVersion, backendStartupDelay, stdinIsTerminal = prevLatest, prevDelay, prevTerminal
+		setupPortForward = prevPortForward
+		k8s.SetClientOptions(prevOpts)
+	})
+
+	runner = env.runner
+	newKubernetesClient = func() (*k8s.KubernetesClient, error) {
+		if env.client == nil {
+			return nil, errors.New("teste sem cliente Kubernetes")
+		}
+		return env.client, nil
+	}
+	waitForPodsReady = func(context.Context, string, time.Duration) error { return nil }
+	latestVersion = func() (string, error) { return "", io.EOF }
+	backendStartupDelay = 0
+	setupPortForward = func(string) error { return nil }
+	// Os testes nunca leem a entrada padrão
+	stdinIsTerminal = func() bool { return false }
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("KUBECONFIG", "")
+	return env
+}
+
+// run executa o girus com os argumentos e retorna a saída padrão. A saída de
+// erro fica em env.stderr.
+func (env *testEnv) run(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	resetFlags(rootCmd)
+
+	stdout, stderr := os.Stdout, os.Stderr
+	var out, errOut bytes.Buffer
+	os.Stdout, os.Stderr = env.capture(t, &out), env.capture(t, &errOut)
+
+	rootCmd.SetArgs(args)
+	err := rootCmd.ExecuteContext(context.Background())
+
+	os.Stdout.Close()
+	os.Stderr.Close()
+	os.Stdout, os.Stderr = stdout, stderr
+	env.wg.Wait()
+	env.stderr = errOut.String()
+	return out.String(), err
+}
+
+// capture retorna um arquivo cujo conteúdo é copiado para buf até ser fechado
+func (env *testEnv) capture(t *testing.T, buf *bytes.Buffer) *os.File {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.wg.Add(1)
+	go func() {
+		defer env.wg.Done()
+		io.Copy(buf, r)
+		r.Close()
+	}()
+	return w
+}
+
+// resetFlags devolve as flags de todos os comandos aos valores padrão, já que
+// o rootCmd é compartilhado entre os testes
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			slice.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	connectAddress      string
+	connectBackendPort  int
+	connectFrontendPort int
+	connectDetach       bool
+	connectBackground   bool
+)
+
+var connectCmd = &cobra.Command{
+	Use:   k8s.ConnectCommand,
+	Short: common.T("Mantém o acesso local ao GIRUS (port-forwards)", "Mantiene el acceso local a GIRUS (port-forwards)"),
+	Long: common.T(`Encaminha as portas do backend (8080) e do frontend (8000) para os serviços do GIRUS
+no cluster, refazendo os encaminhamentos quando os pods são reiniciados.
+
+Por padrão o comando roda em primeiro plano até ser interrompido (Ctrl-C). Com --detach
+ele roda em segundo plano, com PID e log em ~/.girus/, e pode ser encerrado com
+'girus disconnect'.`,
+		`Reenvía los puertos del backend (8080) y del frontend (8000) a los servicios de GIRUS
+en el clúster, rehaciendo los reenvíos cuando los pods se reinician.
+
+Por defecto el comando se ejecuta en primer plano hasta ser interrumpido (Ctrl-C). Con --detach
+se ejecuta en segundo plano, con PID y log en ~/.girus/, y puede detenerse con
+'girus disconnect'.`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bold := color.New(color.Bold).SprintFunc()
+
+		if connectDetach {
+			status, err := k8s.StartConnect([]string{
+				"--namespace", k8s.Namespace(),
+				"--address", connectAddress,
+				"--backend-port", strconv.Itoa(connectBackendPort),
+				"--frontend-port", strconv.Itoa(connectFrontendPort),
+			}, 60*time.Second)
+			if err != nil {
+				return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+			}
+			fmt.Printf("%s %s (PID %d)\n", green(common.T("SUCESSO:", "ÉXITO:")), common.T("girus connect em execução em segundo plano", "girus connect en ejecución en segundo plano"), status.PID)
+			fmt.Println(bold("Backend:") + fmt.Sprintf(" http://localhost:%d", connectBackendPort))
+			fmt.Println(bold("Frontend:") + fmt.Sprintf(" http://localhost:%d", connectFrontendPort))
+			fmt.Println(common.T("Use 'girus disconnect' para encerrar.", "Use 'girus disconnect' para detener."))
+			return nil
+		}
+
+		// Iniciado por --detach, o processo continua após o terminal que o
+		// iniciou ser fechado; em primeiro plano ele termina junto com o terminal
+		if connectBackground {
+			signal.Ignore(syscall.SIGHUP)
+		}
+
+		client, err := newKubernetesClient()
+		if err != nil {
+			return fmt.Errorf("%s %s: %v", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao conectar ao cluster", "Error al conectar al clúster"), err)
+		}
+
+		pf := client.NewPortForwarder(k8s.Namespace(), connectAddress,
+			k8s.PortForward{Service: "girus-backend", LocalPort: connectBackendPort, RemotePort: 8080},
+			k8s.PortForward{Service: "girus-frontend", LocalPort: connectFrontendPort, RemotePort: 80},
+		)
+		pf.Logf = log.Printf
+
+		go func() {
+			select {
+			case <-pf.Ready():
+				fmt.Println(bold("Backend:") + fmt.Sprintf(" http://localhost:%d", connectBackendPort))
+				fmt.Println(bold("Frontend:") + fmt.Sprintf(" http://localhost:%d", connectFrontendPort))
+				fmt.Println(common.T("Pressione Ctrl-C para encerrar.", "Presione Ctrl-C para detener."))
+			case <-cmd.Context().Done():
+			}
+		}()
+
+		if err := k8s.ServeConnect(cmd.Context(), pf); err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+		return nil
+	},
+}
+
+var disconnectCmd = &cobra.Command{
+	Use:   "disconnect",
+	Short: common.T("Encerra o girus connect em execução", "Detiene el girus connect en ejecución"),
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stopped, err := k8s.Disconnect()
+		if err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+		if !stopped {
+			fmt.Println(common.T("Nenhum girus connect em execução.", "Ningún girus connect en ejecución."))
+			return nil
+		}
+		fmt.Printf("%s %s\n", green(common.T("SUCESSO:", "ÉXITO:")), common.T("Port-forwards encerrados.", "Port-forwards detenidos."))
+		return nil
+	},
+}
+
+func init() {
+	connectCmd.Flags().StringVar(&connectAddress, "address", k8s.DefaultPortForwardAddress, common.T("Endereço local em que as portas são abertas", "Dirección local en la que se abren los puertos"))
+	connectCmd.Flags().IntVar(&connectBackendPort, "backend-port", 8080, common.T("Porta local do backend", "Puerto local del backend"))
+	connectCmd.Flags().IntVar(&connectFrontendPort, "frontend-port", 8000, common.T("Porta local do frontend", "Puerto local del frontend"))
+	connectCmd.Flags().BoolVarP(&connectDetach, "detach", "d", false, common.T("Executa em segundo plano (PID e log em ~/.girus/)", "Se ejecuta en segundo plano (PID y log en ~/.girus/)"))
+	connectCmd.Flags().BoolVar(&connectBackground, k8s.ConnectBackgroundFlag, false, "")
+	_ = connectCmd.Flags().MarkHidden(k8s.ConnectBackgroundFlag)
+}
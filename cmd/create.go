@@ -1,16 +1,16 @@
 package cmd
 
 import (
-	"bufio"
-	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
 
+	"github.com/badtuxx/girus-cli/internal/cluster"
 	"github.com/badtuxx/girus-cli/internal/common"
 	"github.com/badtuxx/girus-cli/internal/helpers"
 	"github.com/badtuxx/girus-cli/internal/k8s"
@@ -30,8 +30,29 @@ var (
 	skipPortForward bool
 	skipBrowser     bool
 	repoIndexURL    string
+
+	// Topologia do cluster kind
+	clusterConfigFile     string
+	clusterNodes          int
+	clusterK8sVersion     string
+	clusterHostPort       bool
+	clusterMounts         []string
+	clusterRegistryMirror []string
+	clusterDryRun         bool
 )
 
+// applyManifests aplica os manifestos no cluster com server-side apply. No modo
+// detalhado exibe o resultado de cada objeto, como o kubectl.
+func applyManifests(client *k8s.KubernetesClient, data []byte, verbose bool) error {
+	results, err := client.Apply(context.Background(), data)
+	if verbose {
+		for _, r := range results {
+			fmt.Println("   " + r.String())
+		}
+	}
+	return err
+}
+
 var createCmd = &cobra.Command{
 	Use:   "create [subcommand]",
 	Short: common.T("Comandos para criar recursos", "Comandos para crear recursos"),
@@ -43,9 +64,18 @@ var createCmd = &cobra.Command{
 var createClusterCmd = &cobra.Command{
 	Use:   "cluster",
 	Short: "Cria o cluster Girus",
-	Long: `Cria um cluster Kind com o nome "girus" e implanta todos os componentes necessários.
-Por padrão, o deployment embutido no binário é utilizado.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Cria um cluster com o nome "girus" e implanta todos os componentes necessários.
+Por padrão, o deployment embutido no binário é utilizado.
+
+O cluster é criado pelo provider escolhido em --provider ou na chave provider do
+~/.girus/config.yaml: kind (padrão), k3d ou minikube. Com o provider byo, o GIRUS é
+implantado no cluster do contexto atual do kubeconfig, sem criar um novo cluster.
+
+A topologia do cluster pode ser ajustada com --nodes, --k8s-version e --host-port. No kind
+também com --mount e --registry-mirror, ou substituída por um arquivo do kind com --kind-config;
+use --dry-run para ver a configuração gerada sem criar o cluster.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Criar formatadores de cores
 		green := color.New(color.FgGreen).SprintFunc()
 		red := color.New(color.FgRed).SprintFunc()
@@ -55,6 +85,25 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 		magenta := color.New(color.FgMagenta).SprintFunc()
 		headerColor := color.New(color.FgCyan, color.Bold).SprintFunc()
 
+		provider, err := clusterProvider()
+		if err != nil {
+			return fmt.Errorf("%s %v", red("ERRO:"), err)
+		}
+
+		// Gerar a configuração do cluster kind
+		kindConfig, err := kindClusterConfig(cmd, provider)
+		if err != nil {
+			return fmt.Errorf("%s %v", red("ERRO:"), err)
+		}
+		if clusterDryRun {
+			fmt.Print(string(kindConfig))
+			return nil
+		}
+		// Com as portas do host mapeadas para o nó, os serviços são expostos por NodePort
+		hostPortMode := clusterHostPort && clusterConfigFile == ""
+		// O provider byo usa um cluster existente em vez de criar um novo
+		existingCluster := provider.Name() == cluster.ProviderBYO
+
 		// Exibir cabeçalho
 		fmt.Println(strings.Repeat("─", 80))
 		fmt.Println(headerColor(common.T("GIRUS CREATE", "GIRUS CREAR")))
@@ -65,310 +114,204 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 
 		currentVersion := common.Version
 
-		latestVersion, err := GetLatestGitHubVersion("badtuxx/girus-cli")
+		latest, err := latestVersion()
 
-		if err == nil && IsNewerVersion(latestVersion, currentVersion) {
-			fmt.Printf(common.T("%s versão %s disponível (atual: %s)\n", "%s versión %s disponible (actual: %s)\n"), yellow("AVISO:"), magenta(latestVersion), magenta(currentVersion))
-			fmt.Print(common.T("Deseja atualizar antes de criar o cluster? [S/n]: ", "¿Desea actualizar antes de crear el cluster? [S/n]: "))
+		if err == nil && IsNewerVersion(latest, currentVersion) {
+			fmt.Printf(common.T("%s versão %s disponível (atual: %s)\n", "%s versión %s disponible (actual: %s)\n"), yellow("AVISO:"), magenta(latest), magenta(currentVersion))
 
-			reader := bufio.NewReader(os.Stdin)
-			response, _ := reader.ReadString('\n')
-			response = strings.ToLower(strings.TrimSpace(response))
+			// A atualização é opcional: sem terminal ou com --yes o cluster é
+			// criado com a versão atual
+			update := false
+			if interactive() && !assumeYes {
+				update, _ = confirm(common.T("Deseja atualizar antes de criar o cluster?", "¿Desea actualizar antes de crear el cluster?"), true)
+			} else {
+				fmt.Println(common.T("Use 'girus update' para atualizar. Continuando com a versão atual...", "Use 'girus update' para actualizar. Continuando con la versión actual..."))
+			}
 
-			if response == "" || response == "s" || response == "sim" || response == "y" || response == "yes" {
+			if update {
 				// Criar comando de atualização
-				updateCmd := exec.Command("girus", "update")
-				updateCmd.Stdout = os.Stdout
-				updateCmd.Stderr = os.Stderr
-				updateCmd.Stdin = os.Stdin
-
-				if err := updateCmd.Run(); err != nil {
+				if err := runner.Run(cmd.Context(), os.Stdout, "girus", "update", "--yes"); err != nil {
 					fmt.Fprintf(os.Stderr, "%s erro ao executar atualização: %v\n", red("ERRO:"), err)
 					fmt.Println(common.T("Continuando com a versão atual...", "Continuando con la versión actual..."))
 				} else {
 					fmt.Printf(common.T("%s Atualização concluída. Por favor, execute o comando novamente.\n", "%s Actualización completada. Por favor, ejecute el comando de nuevo.\n"), green("SUCESSO:"))
-					os.Exit(0)
+					return nil
 				}
 			}
 		}
 
-		// Verificar se o containerEngine está instalado e funcionando
-		fmt.Println("\n" + headerColor(common.T("Verificando pré-requisitos...", "Verificando requisitos previos...")))
-		containerEngineCmd := exec.Command(containerEngine, "--version")
-		if err := containerEngineCmd.Run(); err != nil {
-			fmt.Printf(common.T("%s %s não encontrado ou não está em execução\n", "%s %s no encontrado o no está en ejecución\n"), red("ERRO:"), containerEngine)
-			fmt.Println(common.T("\nO "+containerEngine+" é necessário para criar um cluster Kind. Instruções de instalação:", "\n"+containerEngine+" es necesario para crear un cluster Kind. Instrucciones de instalación:"))
-
-			// Detectar o sistema operacional para instruções específicas
-			if runtime.GOOS == "darwin" && containerEngine == "docker" {
-				// macOS docker
-				fmt.Println("\nPara macOS, recomendamos usar Colima (alternativa leve ao Docker Desktop):")
-				fmt.Println("1. Instale o Homebrew caso não tenha:")
-				fmt.Println("   /bin/bash -c \"$(curl -fsSL https://raw.githubusercontent.com/Homebrew/install/HEAD/install.sh)\"")
-				fmt.Println("2. Instale o Colima e o Docker CLI:")
-				fmt.Println("   brew install colima docker")
-				fmt.Println("3. Inicie o Colima:")
-				fmt.Println("   colima start")
-				fmt.Println("\nAlternativamente, você pode instalar o Docker Desktop para macOS de:")
-				fmt.Println("https://www.docker.com/products/docker-desktop")
-			} else if runtime.GOOS == "linux" && containerEngine == "docker" {
-				// Linux docker
-				fmt.Println("\nPara Linux, use o script de instalação oficial:")
-				fmt.Println("   curl -fsSL https://get.docker.com | bash")
-				fmt.Println("\nApós a instalação, adicione seu usuário ao grupo docker para evitar usar sudo:")
-				fmt.Println("   sudo usermod -aG docker $USER")
-				fmt.Println("   newgrp docker")
-				fmt.Println("\nE inicie o serviço:")
-				fmt.Println("   sudo systemctl enable docker")
-				fmt.Println("   sudo systemctl start docker")
-			}
-			if runtime.GOOS == "darwin" && containerEngine == "podman" {
-				// macOS podman
-				fmt.Println("\nPara macOS, recomendamos Podman:")
-				fmt.Println("1. Instale o Homebrew caso não tenha:")
-				fmt.Println("   /bin/bash -c \"$(curl -fsSL https://raw.githubusercontent.com/Homebrew/install/HEAD/install.sh)\"")
-				fmt.Println("2. Instale o Podman")
-				fmt.Println("   brew install podman")
-				fmt.Println("3. Inicie o Podman:")
-				fmt.Println("   podman machine init")
-				fmt.Println("   podman machine start")
-			} else if runtime.GOOS == "linux" && containerEngine == "podman" {
-				// Linux podman
-				fmt.Println("\nPara Linux, use o script de instalação oficial:")
-				fmt.Println("   curl -fsSL https://get.docker.com | bash")
-				fmt.Println("\nE inicie o serviço:")
-				fmt.Println("   sudo systemctl enable podman")
-				fmt.Println("   sudo systemctl start podman")
-				fmt.Println("\nOpcional: Após a instalação, para utilizar podman, rootless evitando sudo:")
-				fmt.Println("   Siga as instruções do site oficial:")
-				fmt.Println("   https://github.com/containers/podman/blob/main/docs/tutorials/rootless_tutorial.md")
-			} else if containerEngine == "podman" {
-				// Windows ou outros sistemas
-				fmt.Println("\nVisite https://github.com/containers/podman/blob/main/docs/tutorials/podman-for-windows.md para instruções de instalação para seu sistema operacional")
-			} else {
-				// Windows ou outros sistemas
-				fmt.Println("\nVisite https://www.docker.com/products/docker-desktop para instruções de instalação para seu sistema operacional")
+		if !existingCluster {
+			// Verificar se o containerEngine está instalado e funcionando
+			fmt.Println("\n" + headerColor(common.T("Verificando pré-requisitos...", "Verificando requisitos previos...")))
+			if err := runner.Run(cmd.Context(), nil, containerEngine, "--version"); err != nil {
+				fmt.Println(common.T("\nO "+containerEngine+" é necessário para criar um cluster Kind. Instruções de instalação:", "\n"+containerEngine+" es necesario para crear un cluster Kind. Instrucciones de instalación:"))
+
+				// Detectar o sistema operacional para instruções específicas
+				if runtime.GOOS == "darwin" && containerEngine == "docker" {
+					// macOS docker
+					fmt.Println("\nPara macOS, recomendamos usar Colima (alternativa leve ao Docker Desktop):")
+					fmt.Println("1. Instale o Homebrew caso não tenha:")
+					fmt.Println("   /bin/bash -c \"$(curl -fsSL https://raw.githubusercontent.com/Homebrew/install/HEAD/install.sh)\"")
+					fmt.Println("2. Instale o Colima e o Docker CLI:")
+					fmt.Println("   brew install colima docker")
+					fmt.Println("3. Inicie o Colima:")
+					fmt.Println("   colima start")
+					fmt.Println("\nAlternativamente, você pode instalar o Docker Desktop para macOS de:")
+					fmt.Println("https://www.docker.com/products/docker-desktop")
+				} else if runtime.GOOS == "linux" && containerEngine == "docker" {
+					// Linux docker
+					fmt.Println("\nPara Linux, use o script de instalação oficial:")
+					fmt.Println("   curl -fsSL https://get.docker.com | bash")
+					fmt.Println("\nApós a instalação, adicione seu usuário ao grupo docker para evitar usar sudo:")
+					fmt.Println("   sudo usermod -aG docker $USER")
+					fmt.Println("   newgrp docker")
+					fmt.Println("\nE inicie o serviço:")
+					fmt.Println("   sudo systemctl enable docker")
+					fmt.Println("   sudo systemctl start docker")
+				}
+				if runtime.GOOS == "darwin" && containerEngine == "podman" {
+					// macOS podman
+					fmt.Println("\nPara macOS, recomendamos Podman:")
+					fmt.Println("1. Instale o Homebrew caso não tenha:")
+					fmt.Println("   /bin/bash -c \"$(curl -fsSL https://raw.githubusercontent.com/Homebrew/install/HEAD/install.sh)\"")
+					fmt.Println("2. Instale o Podman")
+					fmt.Println("   brew install podman")
+					fmt.Println("3. Inicie o Podman:")
+					fmt.Println("   podman machine init")
+					fmt.Println("   podman machine start")
+				} else if runtime.GOOS == "linux" && containerEngine == "podman" {
+					// Linux podman
+					fmt.Println("\nPara Linux, use o script de instalação oficial:")
+					fmt.Println("   curl -fsSL https://get.docker.com | bash")
+					fmt.Println("\nE inicie o serviço:")
+					fmt.Println("   sudo systemctl enable podman")
+					fmt.Println("   sudo systemctl start podman")
+					fmt.Println("\nOpcional: Após a instalação, para utilizar podman, rootless evitando sudo:")
+					fmt.Println("   Siga as instruções do site oficial:")
+					fmt.Println("   https://github.com/containers/podman/blob/main/docs/tutorials/rootless_tutorial.md")
+				} else if containerEngine == "podman" {
+					// Windows ou outros sistemas
+					fmt.Println("\nVisite https://github.com/containers/podman/blob/main/docs/tutorials/podman-for-windows.md para instruções de instalação para seu sistema operacional")
+				} else {
+					// Windows ou outros sistemas
+					fmt.Println("\nVisite https://www.docker.com/products/docker-desktop para instruções de instalação para seu sistema operacional")
+				}
+
+				fmt.Println("\nApós instalar o " + containerEngine + " execute novamente este comando.")
+				return common.WithExitCode(common.ExitDependencyMissing, fmt.Errorf(common.T("%s %s não encontrado ou não está em execução", "%s %s no encontrado o no está en ejecución"), red("ERRO:"), containerEngine))
 			}
 
-			fmt.Println("\nApós instalar o " + containerEngine + " execute novamente este comando.")
-			os.Exit(1)
-		}
+			// Verificar se o serviço containerEngine está rodando
+			if err := runner.Run(cmd.Context(), nil, containerEngine, "info"); err != nil {
+
+				if runtime.GOOS == "darwin" && containerEngine == "docker" {
+					fmt.Println("\nPara macOS com Colima:")
+					fmt.Println("   colima start")
+					fmt.Println("\nPara Docker Desktop:")
+					fmt.Println("   Inicie o aplicativo Docker Desktop")
+				} else if runtime.GOOS == "darwin" && containerEngine == "podman" {
+					fmt.Println("\nPara Podman:")
+					fmt.Println("   Inicie a machine com: podman machine start")
+				} else if runtime.GOOS == "linux" && containerEngine == "docker" {
+					fmt.Println("\nInicie o serviço Docker:")
+					fmt.Println("   sudo systemctl start docker")
+				} else if runtime.GOOS == "linux" && containerEngine == "podman" {
+					fmt.Println("\nInicie o serviço Podman:")
+					fmt.Println("   sudo systemctl start podman")
+				} else {
+					fmt.Println("\nInicie o serviço de containers apropriado para seu sistema.")
+				}
 
-		// Verificar se o serviço containerEngine está rodando
-		containerEngineInfoCmd := exec.Command(containerEngine, "info")
-		if err := containerEngineInfoCmd.Run(); err != nil {
-			fmt.Printf(common.T("%s O serviço %s não está em execução\n", "%s El servicio %s no está en ejecución\n"), red("ERRO:"), containerEngine)
-
-			if runtime.GOOS == "darwin" && containerEngine == "docker" {
-				fmt.Println("\nPara macOS com Colima:")
-				fmt.Println("   colima start")
-				fmt.Println("\nPara Docker Desktop:")
-				fmt.Println("   Inicie o aplicativo Docker Desktop")
-			} else if runtime.GOOS == "darwin" && containerEngine == "podman" {
-				fmt.Println("\nPara Podman:")
-				fmt.Println("   Inicie a machine com: podman machine start")
-			} else if runtime.GOOS == "linux" && containerEngine == "docker" {
-				fmt.Println("\nInicie o serviço Docker:")
-				fmt.Println("   sudo systemctl start docker")
-			} else if runtime.GOOS == "linux" && containerEngine == "podman" {
-				fmt.Println("\nInicie o serviço Podman:")
-				fmt.Println("   sudo systemctl start podman")
-			} else {
-				fmt.Println("\nInicie o serviço de containers apropriado para seu sistema.")
+				fmt.Println("\nApós iniciar o " + containerEngine + ", execute novamente este comando.")
+				return common.WithExitCode(common.ExitDependencyMissing, fmt.Errorf(common.T("%s O serviço %s não está em execução", "%s El servicio %s no está en ejecución"), red("ERRO:"), containerEngine))
 			}
 
-			fmt.Println("\nApós iniciar o " + containerEngine + ", execute novamente este comando.")
-			os.Exit(1)
+			fmt.Printf("%s %s detectado e funcionando\n", green("ATIVO"), magenta(containerEngine))
 		}
 
-		fmt.Printf("%s %s detectado e funcionando\n", green("ATIVO"), magenta(containerEngine))
-
 		// Verificar silenciosamente se o cluster já existe
-		checkCmd := exec.Command("kind", "get", "clusters")
-		output, err := checkCmd.Output()
-
 		// Ignorar erros na checagem, apenas assumimos que não há clusters
-		if err == nil {
-			clusters := strings.Split(strings.TrimSpace(string(output)), "\n")
-
-			// Verificar se o cluster "girus" já existe
-			clusterExists := false
-			for _, cluster := range clusters {
-				if cluster == clusterName {
-					clusterExists = true
-					break
-				}
+		if clusterExists, _ := provider.Exists(cmd.Context(), clusterName); clusterExists && !existingCluster {
+			fmt.Printf("%s %s\n", yellow(common.T("AVISO:", "AVISO:")), common.T("Cluster Girus já existe.", "El cluster Girus ya existe."))
+			ok, err := confirm(common.T("Deseja substituí-lo?", "¿Desea reemplazarlo?"), false)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println(common.T("Operação cancelada.", "Operación cancelada."))
+				return nil
 			}
 
-			if clusterExists {
-				fmt.Printf("%s %s\n", yellow(common.T("AVISO:", "AVISO:")), common.T("Cluster Girus já existe.", "El cluster Girus ya existe."))
-				fmt.Print(common.T("Deseja substituí-lo? [s/N]: ", "¿Desea reemplazarlo? [s/N]: "))
-
-				reader := bufio.NewReader(os.Stdin)
-				response, _ := reader.ReadString('\n')
-				response = strings.ToLower(strings.TrimSpace(response))
-
-				if response != "s" && response != "sim" && response != "y" && response != "yes" {
-					fmt.Println(common.T("Operação cancelada.", "Operación cancelada."))
-					return
-				}
-
-				// Excluir o cluster existente
-				fmt.Println(headerColor("Excluindo cluster Girus existente..."))
-
-				deleteCmd := exec.Command("kind", "delete", "cluster", "--name", clusterName)
-				if verboseMode {
-					deleteCmd.Stdout = os.Stdout
-					deleteCmd.Stderr = os.Stderr
-					if err := deleteCmd.Run(); err != nil {
-						fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao excluir o cluster existente: %v\n", err)
-						fmt.Println("   Por favor, exclua manualmente com 'kind delete cluster --name girus' e tente novamente.")
-						os.Exit(1)
-					}
-				} else {
-					// Usar barra de progresso
-					barConfig := helpers.ProgressBarConfig{
-						Total:            100,
-						Description:      "Excluindo cluster existente...",
-						Width:            80,
-						Throttle:         65,
-						SpinnerType:      15,
-						RenderBlankState: true,
-						ShowBytes:        false,
-						SetPredictTime:   false,
-					}
-					bar := helpers.CreateProgressBar(barConfig)
-
-					var stderr bytes.Buffer
-					deleteCmd.Stderr = &stderr
-
-					// Iniciar o comando
-					err := deleteCmd.Start()
-					if err != nil {
-						fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao iniciar exclusão: %v\n", err)
-						os.Exit(1)
-					}
-
-					// Atualizar a barra de progresso
-					done := make(chan struct{})
-					go func() {
-						for {
-							select {
-							case <-done:
-								return
-							default:
-								bar.Add(1)
-								time.Sleep(100 * time.Millisecond)
-							}
-						}
-					}()
-
-					// Aguardar o final do comando
-					err = deleteCmd.Wait()
-					close(done)
-					bar.Finish()
+			// Excluir o cluster existente
+			fmt.Println(headerColor("Excluindo cluster Girus existente..."))
 
-					if err != nil {
-						fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao excluir o cluster existente: %v\n", err)
-						fmt.Println("   Detalhes técnicos:", stderr.String())
-						fmt.Println("   Por favor, exclua manualmente com 'kind delete cluster --name girus' e tente novamente.")
-						os.Exit(1)
-					}
+			details, err := runWithProgress(verboseMode, "Excluindo cluster existente...", func(out io.Writer) error {
+				return provider.Delete(cmd.Context(), clusterName, out)
+			})
+			if err != nil {
+				if details != "" {
+					fmt.Println("   Detalhes técnicos:", details)
 				}
-
-				fmt.Println("\n" + green(common.T("SUCESSO:", "ÉXITO:")) + " " + common.T("Cluster existente excluído com sucesso.", "Cluster existente eliminado con éxito."))
+				fmt.Println("   Por favor, exclua manualmente com 'girus delete cluster' e tente novamente.")
+				return fmt.Errorf("%s Erro ao excluir o cluster existente: %v", red("ERRO:"), err)
 			}
+
+			fmt.Println("\n" + green(common.T("SUCESSO:", "ÉXITO:")) + " " + common.T("Cluster existente excluído com sucesso.", "Cluster existente eliminado con éxito."))
 		}
 
-		// Criar o cluster Kind
-		fmt.Println("\n" + headerColor(common.T("Criando cluster Girus...", "Creando cluster Girus...")))
-
-		if verboseMode {
-			// Executar normalmente mostrando o output
-			createClusterCmd := exec.Command("kind", "create", "cluster", "--name", clusterName)
-			createClusterCmd.Stdout = os.Stdout
-			createClusterCmd.Stderr = os.Stderr
-
-			if err := createClusterCmd.Run(); err != nil {
-				fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao criar o cluster Girus: %v\n", err)
-				fmt.Println("   Possíveis causas:")
-				fmt.Println("   • " + bold(containerEngine) + " não está em execução")
-				fmt.Println("   • Permissões insuficientes")
-				fmt.Println("   • Conflito com cluster existente")
-				os.Exit(1)
-			}
+		// Criar o cluster (ou verificar o cluster existente no provider byo)
+		if existingCluster {
+			fmt.Println("\n" + headerColor(common.T("Verificando o cluster existente...", "Verificando el cluster existente...")))
 		} else {
-			// Usar barra de progresso (padrão)
-			barConfig := helpers.ProgressBarConfig{
-				Total:            100,
-				Description:      common.T("Criando cluster...", "Creando cluster..."),
-				Width:            80,
-				Throttle:         65,
-				SpinnerType:      14,
-				RenderBlankState: true,
-				ShowBytes:        false,
-				SetPredictTime:   false,
-			}
-			bar := helpers.CreateProgressBar(barConfig)
-
-			// Executar comando sem mostrar saída
-			createClusterCmd := exec.Command("kind", "create", "cluster", "--name", clusterName)
-			var stderr bytes.Buffer
-			createClusterCmd.Stderr = &stderr
+			fmt.Println("\n" + headerColor(common.T("Criando cluster Girus...", "Creando cluster Girus...")))
+		}
 
-			// Iniciar o comando
-			err := createClusterCmd.Start()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao iniciar o comando: %v\n", err)
-				os.Exit(1)
+		details, err := runWithProgress(verboseMode, common.T("Criando cluster...", "Creando cluster..."), func(out io.Writer) error {
+			return provider.Create(cmd.Context(), cluster.CreateOptions{
+				Name:       clusterName,
+				KindConfig: kindConfig,
+				Nodes:      clusterNodes,
+				K8sVersion: clusterK8sVersion,
+				HostPort:   clusterHostPort,
+				Out:        out,
+			})
+		})
+		if err != nil {
+			// Traduzir mensagens de erro comuns
+			if strings.Contains(details, "node(s) already exist for a cluster with the name") {
+				fmt.Println("   Erro: Já existe um cluster com o nome 'girus' no sistema.")
+				fmt.Println("   Por favor, exclua-o primeiro com 'girus delete cluster'")
+			} else if strings.Contains(details, "permission denied") {
+				fmt.Println("   Erro: Permissão negada. Verifique as permissões do " + containerEngine + ".")
+			} else if strings.Contains(details, "Cannot connect to the Docker daemon") {
+				fmt.Println("   Erro: Não foi possível conectar ao serviço Docker.")
+				fmt.Println("   Verifique se o Docker está em execução com 'systemctl status docker'")
+			} else if details != "" {
+				fmt.Println("   Detalhes técnicos:", details)
 			}
+			fmt.Println(common.T("   Execute 'girus doctor' para diagnosticar o ambiente.", "   Ejecute 'girus doctor' para diagnosticar el entorno."))
 
-			// Atualizar a barra de progresso enquanto o comando está em execução
-			done := make(chan struct{})
-			go func() {
-				for {
-					select {
-					case <-done:
-						return
-					default:
-						bar.Add(1)
-						time.Sleep(200 * time.Millisecond)
-					}
-				}
-			}()
-
-			// Aguardar o final do comando
-			err = createClusterCmd.Wait()
-			close(done)
-			bar.Finish()
+			return fmt.Errorf("%s Erro ao criar o cluster Girus: %w", red("ERRO:"), err)
+		}
 
-			if err != nil {
-				fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao criar o cluster Girus: %v\n", err)
-
-				// Traduzir mensagens de erro comuns
-				errMsg := stderr.String()
-
-				if strings.Contains(errMsg, "node(s) already exist for a cluster with the name") {
-					fmt.Println("   Erro: Já existe um cluster com o nome 'girus' no sistema.")
-					fmt.Println("   Por favor, exclua-o primeiro com 'kind delete cluster --name girus'")
-				} else if strings.Contains(errMsg, "permission denied") {
-					fmt.Println("   Erro: Permissão negada. Verifique as permissões do " + containerEngine + ".")
-				} else if strings.Contains(errMsg, "Cannot connect to the Docker daemon") {
-					fmt.Println("   Erro: Não foi possível conectar ao serviço Docker.")
-					fmt.Println("   Verifique se o Docker está em execução com 'systemctl status docker'")
-				} else {
-					fmt.Println("   Detalhes técnicos:", errMsg)
-				}
+		if !existingCluster {
+			fmt.Println("\n" + green("SUCESSO:") + " Cluster Girus criado com sucesso!")
 
-				os.Exit(1)
-			}
+			// Acessar o cluster criado pelo seu contexto, sem depender do
+			// contexto atual do kubeconfig
+			opts := k8s.CurrentClientOptions()
+			opts.Context = provider.KubeContext(clusterName)
+			k8s.SetClientOptions(opts)
 		}
 
-		fmt.Println("\n" + green("SUCESSO:") + " Cluster Girus criado com sucesso!")
-
 		// Aplicar o manifesto de deployment do Girus
 		fmt.Println("\n" + headerColor("Implantando o Girus no cluster..."))
 
+		client, err := newKubernetesClient()
+		if err != nil {
+			return fmt.Errorf("%s Erro ao conectar ao cluster: %v", red("ERRO:"), err)
+		}
+
 		// Verificar se existe o arquivo girus-kind-deploy.yaml
 		deployYamlPath := "girus-kind-deploy.yaml"
 		foundDeployFile := false
@@ -391,16 +334,16 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 		if foundDeployFile {
 			fmt.Printf("%s Usando arquivo de deployment: %s\n", cyan("INFO:"), magenta(deployFile))
 
+			deployData, err := os.ReadFile(deployFile)
+			if err != nil {
+				return fmt.Errorf("%s Erro ao ler o arquivo de deployment: %v", red("ERRO:"), err)
+			}
+
 			// Aplicar arquivo de deployment completo (já contém o template do lab)
 			if verboseMode {
-				// Executar normalmente mostrando o output
-				applyCmd := exec.Command("kubectl", "apply", "-f", deployFile)
-				applyCmd.Stdout = os.Stdout
-				applyCmd.Stderr = os.Stderr
-
-				if err := applyCmd.Run(); err != nil {
-					fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao aplicar o manifesto do Girus: %v\n", err)
-					os.Exit(1)
+				// Aplicar mostrando o resultado de cada objeto
+				if err := applyManifests(client, deployData, true); err != nil {
+					return fmt.Errorf("%s Erro ao aplicar o manifesto do Girus: %v", red("ERRO:"), err)
 				}
 			} else {
 				// Usar barra de progresso
@@ -416,19 +359,7 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 				}
 				bar := helpers.CreateProgressBar(barConfig)
 
-				// Executar comando sem mostrar saída
-				applyCmd := exec.Command("kubectl", "apply", "-f", deployFile)
-				var stderr bytes.Buffer
-				applyCmd.Stderr = &stderr
-
-				// Iniciar o comando
-				err := applyCmd.Start()
-				if err != nil {
-					fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao iniciar o comando: %v\n", err)
-					os.Exit(1)
-				}
-
-				// Atualizar a barra de progresso enquanto o comando está em execução
+				// Atualizar a barra de progresso enquanto os manifestos são aplicados
 				done := make(chan struct{})
 				go func() {
 					for {
@@ -442,15 +373,13 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 					}
 				}()
 
-				// Aguardar o final do comando
-				err = applyCmd.Wait()
+				// Aplicar sem mostrar saída
+				err := applyManifests(client, deployData, false)
 				close(done)
 				bar.Finish()
 
 				if err != nil {
-					fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao aplicar o manifesto do Girus: %v\n", err)
-					fmt.Println("   Detalhes técnicos:", stderr.String())
-					os.Exit(1)
+					return fmt.Errorf("%s Erro ao aplicar o manifesto do Girus: %v", red("ERRO:"), err)
 				}
 			}
 
@@ -459,37 +388,17 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 			// Usar o deployment embutido como fallback
 			// fmt.Println("⚠️  Arquivo girus-kind-deploy.yaml não encontrado, usando deployment embutido.")
 
-			// Criar um arquivo temporário para o deployment principal
-			tempFile, err := os.CreateTemp("", "girus-deploy-*.yaml")
-			if err != nil {
-				fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao criar arquivo temporário: %v\n", err)
-				os.Exit(1)
-			}
-			defer os.Remove(tempFile.Name()) // Limpar o arquivo temporário ao finalizar
-
+			// O deployment embutido é aplicado direto da memória
 			defaultDeployment, err := templates.GetManifest("defaultDeployment.yaml")
 			if err != nil {
-				fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao carregar o template: %v\n", err)
-				return
+				return fmt.Errorf("%s Erro ao carregar o template: %v", red("ERRO:"), err)
 			}
 
-			// Escrever o conteúdo no arquivo temporário
-			if _, err := tempFile.WriteString(string(defaultDeployment)); err != nil {
-				fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao escrever no arquivo temporário: %v\n", err)
-				os.Exit(1)
-			}
-			tempFile.Close()
-
 			// Aplicar o deployment principal
 			if verboseMode {
-				// Executar normalmente mostrando o output
-				applyCmd := exec.Command("kubectl", "apply", "-f", tempFile.Name())
-				applyCmd.Stdout = os.Stdout
-				applyCmd.Stderr = os.Stderr
-
-				if err := applyCmd.Run(); err != nil {
-					fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao aplicar o manifesto do Girus: %v\n", err)
-					os.Exit(1)
+				// Aplicar mostrando o resultado de cada objeto
+				if err := applyManifests(client, defaultDeployment, true); err != nil {
+					return fmt.Errorf("%s Erro ao aplicar o manifesto do Girus: %v", red("ERRO:"), err)
 				}
 			} else {
 				// Usar barra de progresso para o deploy (padrão)
@@ -505,19 +414,7 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 				}
 				bar := helpers.CreateProgressBar(barConfig)
 
-				// Executar comando sem mostrar saída
-				applyCmd := exec.Command("kubectl", "apply", "-f", tempFile.Name())
-				var stderr bytes.Buffer
-				applyCmd.Stderr = &stderr
-
-				// Iniciar o comando
-				err := applyCmd.Start()
-				if err != nil {
-					fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao iniciar o comando: %v\n", err)
-					os.Exit(1)
-				}
-
-				// Atualizar a barra de progresso enquanto o comando está em execução
+				// Atualizar a barra de progresso enquanto os manifestos são aplicados
 				done := make(chan struct{})
 				go func() {
 					for {
@@ -531,15 +428,13 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 					}
 				}()
 
-				// Aguardar o final do comando
-				err = applyCmd.Wait()
+				// Aplicar sem mostrar saída
+				err := applyManifests(client, defaultDeployment, false)
 				close(done)
 				bar.Finish()
 
 				if err != nil {
-					fmt.Fprintf(os.Stderr, red("ERRO:")+" Erro ao aplicar o manifesto do Girus: %v\n", err)
-					fmt.Println("   Detalhes técnicos:", stderr.String())
-					os.Exit(1)
+					return fmt.Errorf("%s Erro ao aplicar o manifesto do Girus: %v", red("ERRO:"), err)
 				}
 			}
 
@@ -571,36 +466,20 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 							continue
 						}
 
-						// Criar arquivo temporário
-						tempLabFile, err := os.CreateTemp("", "girus-template-*.yaml")
-						if err != nil {
-							fmt.Fprintf(os.Stderr, "     %s Erro ao criar arquivo temporário para %s: %v\n", red("ERRO:"), manifestName, err)
+						// Validar o manifesto antes de aplicá-lo
+						if _, err := lab.Parse(manifestContent, manifestName); err != nil {
+							fmt.Fprintf(os.Stderr, "     %s Template %s inválido: %v\n", red("ERRO:"), manifestName, err)
 							allTemplatesApplied = false
 							continue
 						}
-						tempPath := tempLabFile.Name() // Guardar o path antes de fechar
 
-						// Escrever e fechar arquivo temporário
-						if _, err := tempLabFile.Write(manifestContent); err != nil {
-							fmt.Fprintf(os.Stderr, "     %s Erro ao escrever template %s no arquivo temporário: %v\n", red("ERRO:"), manifestName, err)
-							tempLabFile.Close() // Fechar mesmo em caso de erro
-							os.Remove(tempPath) // Remover o temporário
-							allTemplatesApplied = false
-							continue
-						}
-						tempLabFile.Close()
-
-						// Aplicar com kubectl
-						applyCmd := exec.Command("kubectl", "apply", "-f", tempPath)
-						applyCmd.Stdout = os.Stdout
-						applyCmd.Stderr = os.Stderr
-						if err := applyCmd.Run(); err != nil {
+						// Aplicar direto da memória
+						if err := applyManifests(client, manifestContent, true); err != nil {
 							fmt.Fprintf(os.Stderr, "     %s Erro ao aplicar o template %s: %v\n", red("ERRO:"), manifestName, err)
 							allTemplatesApplied = false
 						} else {
 							fmt.Printf("     %s Template %s aplicado com sucesso!\n", green("SUCESSO:"), manifestName)
 						}
-						os.Remove(tempPath) // Remover o temporário após o uso
 					}
 
 					if allTemplatesApplied {
@@ -633,37 +512,21 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 							continue
 						}
 
-						// Criar arquivo temporário
-						tempLabFile, err := os.CreateTemp("", "girus-template-*.yaml")
-						if err != nil {
+						// Validar o manifesto antes de aplicá-lo
+						if _, err := lab.Parse(manifestContent, manifestName); err != nil {
+							fmt.Fprintf(os.Stderr, "\n   %s %s: %v\n", yellow(common.T("AVISO:", "AVISO:")), fmt.Sprintf(common.T("Template %s inválido, ignorado", "Plantilla %s inválida, ignorada"), manifestName), err)
 							bar.Add(1) // Incrementar a barra mesmo com erro
 							allSuccess = false
 							continue
 						}
-						tempPath := tempLabFile.Name()
 
-						// Escrever e fechar arquivo temporário
-						if _, err := tempLabFile.Write(manifestContent); err != nil {
-							tempLabFile.Close()
-							os.Remove(tempPath)
-							bar.Add(1) // Incrementar a barra mesmo com erro
-							allSuccess = false
-							continue
-						}
-						tempLabFile.Close()
-
-						// Aplicar com kubectl
-						applyCmd := exec.Command("kubectl", "apply", "-f", tempPath)
-						var stderr bytes.Buffer
-						applyCmd.Stderr = &stderr
-						if err := applyCmd.Run(); err != nil {
-							os.Remove(tempPath)
+						// Aplicar direto da memória
+						if err := applyManifests(client, manifestContent, false); err != nil {
 							bar.Add(1) // Incrementar a barra mesmo com erro
 							allSuccess = false
 							continue
 						}
 
-						os.Remove(tempPath)
 						bar.Add(1) // Incrementar a barra após sucesso
 					}
 					bar.Finish()
@@ -676,13 +539,9 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 
 					// Verificação de diagnóstico para confirmar que os templates estão visíveis
 					fmt.Println("\n" + headerColor(common.T("Verificando templates de laboratório instalados:", "Verificando plantillas de laboratorio instaladas:")))
-					listLabsCmd := exec.Command("kubectl", "get", "configmap", "-n", "girus", "-l", "app=girus-lab-template", "-o", "custom-columns=NAME:.metadata.name")
-					var labsOutput bytes.Buffer
-					listLabsCmd.Stdout = &labsOutput
-					listLabsCmd.Stderr = &labsOutput
-
-					if err := listLabsCmd.Run(); err == nil {
-						labs := strings.Split(strings.TrimSpace(labsOutput.String()), "\n")
+					labsOutput, err := runner.Output(cmd.Context(), "kubectl", k8s.KubectlArgs("get", "configmap", "-n", k8s.Namespace(), "-l", "app=girus-lab-template", "-o", "custom-columns=NAME:.metadata.name")...)
+					if err == nil {
+						labs := strings.Split(strings.TrimSpace(string(labsOutput)), "\n")
 						if len(labs) > 1 { // Primeira linha é o cabeçalho "NAME"
 							fmt.Println(common.T("   Templates encontrados:", "   Plantillas encontradas:"))
 							for i, lab := range labs {
@@ -700,16 +559,7 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 
 				// Reiniciar o backend para carregar os templates
 				fmt.Println("\n" + headerColor(common.T("Reiniciando o backend para carregar os templates...", "Reiniciando el backend para cargar las plantillas...")))
-				restartCmd := exec.Command("kubectl", "rollout", "restart", "deployment/girus-backend", "-n", "girus")
-				restartCmd.Run()
-
-				// Aguardar o reinício completar
 				fmt.Println(common.T("   Aguardando o reinício do backend completar...", "   Esperando a que el backend reinicie..."))
-				waitCmd := exec.Command("kubectl", "rollout", "status", "deployment/girus-backend", "-n", "girus", "--timeout=60s")
-				// Redirecionar saída para não exibir detalhes do rollout
-				var waitOutput bytes.Buffer
-				waitCmd.Stdout = &waitOutput
-				waitCmd.Stderr = &waitOutput
 
 				// Iniciar indicador de progresso simples
 				spinChars := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
@@ -728,19 +578,34 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 					}
 				}()
 
-				// Executar e aguardar
-				waitCmd.Run()
+				// Reiniciar e aguardar o rollout
+				err := client.RestartBackend(cmd.Context())
 				close(done)
-				fmt.Printf("\r   %s %s            \n", green(common.T("SUCESSO:", "ÉXITO:")), common.T("Backend reiniciado com sucesso!", "¡Backend reiniciado con éxito!"))
+				if err != nil {
+					fmt.Printf("\r   %s %v\n", yellow(common.T("AVISO:", "AVISO:")), err)
+				} else {
+					fmt.Printf("\r   %s %s            \n", green(common.T("SUCESSO:", "ÉXITO:")), common.T("Backend reiniciado com sucesso!", "¡Backend reiniciado con éxito!"))
+				}
 
 				// Aguardar mais alguns segundos para o backend inicializar completamente
 				fmt.Println(common.T("   Aguardando inicialização completa...", "   Esperando a que la inicialización complete..."))
-				time.Sleep(5 * time.Second)
+				time.Sleep(backendStartupDelay)
+			}
+		}
+
+		// Expor os serviços nas portas do nó mapeadas para o host
+		if hostPortMode {
+			services, err := templates.GetClusterTemplate("nodeport-services.yaml")
+			if err == nil {
+				err = applyManifests(client, services, verboseMode)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s %s: %v\n", yellow("AVISO:"), common.T("Erro ao expor os serviços nas portas do host", "Error al exponer los servicios en los puertos del host"), err)
 			}
 		}
 
 		// Aguardar os pods do Girus ficarem prontos
-		if err := k8s.WaitForPodsReady("girus", 5*time.Minute); err != nil {
+		if err := waitForPodsReady(cmd.Context(), k8s.Namespace(), 5*time.Minute); err != nil {
 			fmt.Fprintf(os.Stderr, "%s %v\n", yellow("AVISO:"), err)
 			fmt.Println("Recomenda-se verificar o estado dos pods com 'kubectl get pods -n girus'")
 		} else {
@@ -750,10 +615,23 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 		fmt.Printf("%s Girus implantado com sucesso no cluster!\n", green("SUCESSO:"))
 
 		// Configurar port-forward automaticamente (a menos que --skip-port-forward tenha sido especificado)
-		if !skipPortForward {
+		if hostPortMode {
+			fmt.Println("\n" + headerColor(common.T("Acesso aos serviços do Girus", "Acceso a los servicios de Girus")))
+			fmt.Println(common.T("As portas 8000 e 8080 do host estão mapeadas para o cluster, o port-forward não é necessário.", "Los puertos 8000 y 8080 del host están mapeados al clúster, el port-forward no es necesario."))
+			fmt.Println(bold("Backend:") + " http://localhost:8080")
+			fmt.Println(bold("Frontend:") + " http://localhost:8000")
+
+			if !skipBrowser {
+				fmt.Println("\n" + headerColor("Abrindo navegador com o Girus..."))
+				if err := helpers.OpenBrowser("http://localhost:8000"); err != nil {
+					fmt.Printf("%s Não foi possível abrir o navegador: %v\n", yellow("AVISO:"), err)
+					fmt.Println("   Acesse manualmente: http://localhost:8000")
+				}
+			}
+		} else if !skipPortForward {
 			fmt.Print("\n" + headerColor(common.T("Configurando acesso aos serviços do Girus...", "Configurando el acceso a los servicios de Girus...")) + " ")
 
-			if err := k8s.SetupPortForward("girus"); err != nil {
+			if err := setupPortForward(k8s.Namespace()); err != nil {
 				fmt.Printf("%s\n", yellow(common.T("AVISO:", "AVISO:")))
 				fmt.Printf(common.T("%s Não foi possível configurar o acesso automático: %v\n", "%s No fue posible configurar el acceso automático: %v\n"), yellow(common.T("AVISO:", "AVISO:")), err)
 				fmt.Println(common.T("\nVocê pode tentar configurar manualmente com os comandos:", "\nPuede intentar configurar manualmente con los comandos:"))
@@ -777,8 +655,7 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 		} else {
 			fmt.Println("\n" + yellow(common.T("AVISO:", "AVISO:")) + " " + common.T("Port-forward ignorado conforme solicitado", "Port-forward ignorado según lo solicitado"))
 			fmt.Println(common.T("\nPara acessar o Girus posteriormente, execute:", "\nPara acceder a Girus más tarde, ejecute:"))
-			fmt.Println("kubectl port-forward -n girus svc/girus-backend 8080:8080 --address 0.0.0.0")
-			fmt.Println("kubectl port-forward -n girus svc/girus-frontend 8000:80 --address 0.0.0.0")
+			fmt.Println("girus connect --detach")
 		}
 
 		// Exibir mensagem de conclusão
@@ -799,67 +676,97 @@ Por padrão, o deployment embutido no binário é utilizado.`,
 		fmt.Println("    girus list labs")
 
 		fmt.Println(strings.Repeat("─", 60))
+		return nil
 	},
 }
 
 var createLabCmd = &cobra.Command{
-	Use:   "lab [lab-id] ou -f [arquivo]",
-	Short: "Cria um novo laboratório no Girus",
-	Long:  "Adiciona um novo laboratório ao Girus a partir de um arquivo de manifesto ConfigMap, ou cria um ambiente de laboratório a partir de um ID de template existente.\nOs templates de laboratório são armazenados no diretório /labs na raiz do projeto.",
-	Run: func(cmd *cobra.Command, args []string) {
-		// Criar formatadores de cores
-		red := color.New(color.FgRed).SprintFunc()
-
+	Use:          "lab [lab-id[@versão]] ou -f [arquivo]",
+	Short:        "Cria um novo laboratório no Girus",
+	Long:         "Adiciona um novo laboratório ao Girus a partir de um arquivo de manifesto ConfigMap, ou cria um ambiente de laboratório a partir de um ID de template existente.\nOs templates de laboratório são armazenados no diretório /labs na raiz do projeto.",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Verificar qual modo estamos
 		if labFile != "" {
 			// Modo de adicionar template a partir de arquivo
-			lab.AddLabFromFile(labFile, verboseMode)
+			if err := addLabFromFile(cmd.Context(), labFile, nil, verboseMode); err != nil {
+				return fmt.Errorf("%s %w", red("ERRO:"), err)
+			}
+			return nil
 		} else if len(args) > 0 {
 			// Modo de adicionar template a partir do repositório remoto
 			labID := args[0]
-			createLabFromRepo(labID, repoIndexURL, verboseMode)
-		} else {
-			fmt.Fprintf(os.Stderr, "%s %s\n", red("ERRO:"), common.T("Você deve especificar um ID de laboratório ou um arquivo com a flag -f", "Debe especificar un ID de laboratorio o un archivo con la opción -f"))
-			fmt.Println(common.T("\nExemplos:", "\nEjemplos:"))
-			fmt.Println(common.T("  girus create lab linux-monitoramento-sistema  # Instala um laboratório do repositório remoto", "  girus create lab linux-monitoramento-sistema  # Instala un laboratorio del repositorio remoto"))
-			fmt.Println(common.T("  girus create lab -f meulaboratorio.yaml       # Adiciona um novo template a partir do arquivo", "  girus create lab -f mi-lab.yaml             # Añade una nueva plantilla desde el archivo"))
-			os.Exit(1)
+			return createLabFromRepo(cmd.Context(), labID, repoIndexURL, verboseMode)
 		}
+
+		fmt.Println(common.T("\nExemplos:", "\nEjemplos:"))
+		fmt.Println(common.T("  girus create lab linux-monitoramento-sistema  # Instala um laboratório do repositório remoto", "  girus create lab linux-monitoramento-sistema  # Instala un laboratorio del repositorio remoto"))
+		fmt.Println(common.T("  girus create lab -f meulaboratorio.yaml       # Adiciona um novo template a partir do arquivo", "  girus create lab -f mi-lab.yaml             # Añade una nueva plantilla desde el archivo"))
+		return common.WithExitCode(common.ExitUsage, fmt.Errorf("%s %s", red("ERRO:"), common.T("Você deve especificar um ID de laboratório ou um arquivo com a flag -f", "Debe especificar un ID de laboratorio o un archivo con la opción -f")))
 	},
 }
 
-// createLabFromRepo baixa e aplica um laboratório do repositório remoto pelo ID
-func createLabFromRepo(labID string, indexURL string, verboseMode bool) {
+// addLabFromFile aplica o laboratório do arquivo no cluster com as dependências
+// dos comandos (cliente Kubernetes, runner e port-forward)
+func addLabFromFile(ctx context.Context, labFile string, provenance *k8s.LabProvenance, verbose bool) error {
+	client, err := newKubernetesClient()
+	if err != nil {
+		return common.WithExitCode(common.ExitClusterNotFound, fmt.Errorf("%s: %w", common.T("erro ao conectar ao cluster", "error al conectar al clúster"), err))
+	}
+	return lab.AddLabFromFile(ctx, labFile, lab.AddLabOptions{
+		Client:       client,
+		Runner:       runner,
+		Provenance:   provenance,
+		Verbose:      verbose,
+		Confirm:      confirmLabInstall,
+		StartupDelay: backendStartupDelay,
+		PortForward:  setupPortForward,
+	})
+}
+
+// confirmLabInstall pergunta se a instalação do laboratório deve continuar
+// apesar das dependências ausentes
+func confirmLabInstall(question string) (bool, error) {
+	return confirm(question, false)
+}
+
+// createLabFromRepo procura o laboratório pelo ID nos repositórios
+// configurados (ou apenas no repositório de indexURL), baixa e aplica
+func createLabFromRepo(ctx context.Context, labID string, indexURL string, verboseMode bool) error {
 	// Criar formatadores de cores
-	red := color.New(color.FgRed).SprintFunc()
 	cyan := color.New(color.FgCyan).SprintFunc()
-	magenta := color.New(color.FgMagenta).SprintFunc()
 	headerColor := color.New(color.FgCyan, color.Bold).SprintFunc()
 
 	fmt.Printf(common.T("%s Buscando laboratório '%s'...\n", "%s Buscando laboratorio '%s'...\n"), cyan("INFO:"), magenta(labID))
 
-	// Buscar o laboratório no index.yaml
-	labInfo, err := repo.FindLabByID(labID, indexURL)
+	client, err := repo.NewClient(indexURL)
+	if err != nil {
+		return fmt.Errorf("%s %v", red("ERRO:"), err)
+	}
+
+	// Buscar o laboratório nos índices dos repositórios, aceitando lab@versão
+	labID, constraint := repo.ParseLabRef(labID)
+	labInfo, err := client.FindLab(ctx, "", labID, constraint)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%s %v\n", red("ERRO:"), err)
 		fmt.Println(common.T("\nPara ver os laboratórios disponíveis, use:", "\nPara ver los laboratorios disponibles, use:"))
 		fmt.Println("  girus list repo-labs")
-		os.Exit(1)
+		return withLabExitCode(fmt.Errorf("%s %w", red("ERRO:"), err))
 	}
 
-	fmt.Printf(common.T("%s Baixando o template de '%s'...\n", "%s Descargando la plantilla de '%s'...\n"), cyan("INFO:"), magenta(labInfo.Title))
+	fmt.Printf(common.T("%s Baixando o template de '%s' %s do repositório %s...\n", "%s Descargando la plantilla de '%s' %s del repositorio %s...\n"), cyan("INFO:"), magenta(labInfo.Title), labInfo.Version, magenta(labInfo.Repository.Name))
 
-	// Fazer o download do lab.yaml
-	tempFile, err := repo.DownloadLabYAML(labInfo.URL)
+	// Fazer o download do lab.yaml para o cache
+	labPath, provenance, err := downloadRepositoryLab(ctx, client, labInfo)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%s %v\n", red("ERRO:"), err)
-		os.Exit(1)
+		return err
 	}
-	defer os.Remove(tempFile) // Garantir que o arquivo temporário seja removido ao final
 
 	// Aplicar o laboratório
 	fmt.Println(headerColor(common.T("Aplicando laboratório no cluster GIRUS...", "Aplicando laboratorio en el cluster GIRUS...")))
-	lab.AddLabFromFile(tempFile, verboseMode)
+	if err := addLabFromFile(ctx, labPath, provenance, verboseMode); err != nil {
+		return fmt.Errorf("%s %w", red("ERRO:"), err)
+	}
+	return nil
 }
 
 func init() {
@@ -873,12 +780,51 @@ func init() {
 	createClusterCmd.Flags().BoolVarP(&skipBrowser, "skip-browser", "", false, "Não abrir o navegador automaticamente")
 
 	createClusterCmd.Flags().StringVarP(&containerEngine, "container-engine", "e", "docker", "Engine de container (docker ou podman)")
+	createClusterCmd.Flags().StringVar(&clusterConfigFile, "kind-config", "", "Arquivo de configuração do kind (Cluster kind.x-k8s.io/v1alpha4) usado no lugar da configuração gerada")
+	createClusterCmd.Flags().IntVar(&clusterNodes, "nodes", 1, "Número de nós do cluster (um control-plane e os demais workers)")
+	createClusterCmd.Flags().StringVar(&clusterK8sVersion, "k8s-version", "", "Versão do Kubernetes dos nós (ex.: v1.33.1)")
+	createClusterCmd.Flags().BoolVar(&clusterHostPort, "host-port", false, "Mapeia as portas 8000 e 8080 do host para o cluster, dispensando o port-forward")
+	createClusterCmd.Flags().StringArrayVar(&clusterMounts, "mount", nil, "Monta um diretório do host nos nós (caminho-do-host:/caminho/no/nó)")
+	createClusterCmd.Flags().StringArrayVar(&clusterRegistryMirror, "registry-mirror", nil, "Espelho de registro do containerd (registro=http://endpoint)")
+	createClusterCmd.Flags().BoolVar(&clusterDryRun, "dry-run", false, "Exibe a configuração do kind gerada sem criar o cluster")
 
 	// Flags para createLabCmd
-	createLabCmd.Flags().StringVarP(&labFile, "file", "f", "", "Arquivo de manifesto do laboratório (ConfigMap)")
+	createLabCmd.Flags().StringVarP(&labFile, "file", "f", "", "Arquivo de manifesto do laboratório (ConfigMap ou kind: Lab)")
 	createLabCmd.Flags().BoolVarP(&verboseMode, "verbose", "v", false, "Modo detalhado com output completo em vez da barra de progresso")
-	createLabCmd.Flags().StringVarP(&repoIndexURL, "url", "u", "", "URL do arquivo index.yaml (opcional)")
+	createLabCmd.Flags().StringVarP(&repoIndexURL, "url", "u", "", "URL do repositório ou do index.yaml, usada no lugar dos repositórios configurados (opcional)")
 
 	// definir o nome do cluster como "girus" sempre
 	clusterName = "girus"
 }
+
+// kindClusterConfig retorna a configuração do kind: o arquivo informado em
+// --kind-config ou a configuração gerada a partir das flags de topologia. Para os
+// demais providers retorna nil e rejeita as flags exclusivas do kind.
+func kindClusterConfig(cmd *cobra.Command, provider cluster.ClusterProvider) ([]byte, error) {
+	if provider.Name() != cluster.ProviderKind {
+		for _, flag := range []string{"kind-config", "mount", "registry-mirror", "dry-run"} {
+			if cmd.Flags().Changed(flag) {
+				return nil, fmt.Errorf("--%s é suportada apenas pelo provider kind (provider atual: %s)", flag, provider.Name())
+			}
+		}
+		return nil, nil
+	}
+
+	if clusterConfigFile != "" {
+		for _, flag := range []string{"nodes", "k8s-version", "host-port", "mount", "registry-mirror"} {
+			if cmd.Flags().Changed(flag) {
+				return nil, fmt.Errorf("--kind-config não pode ser combinado com --%s", flag)
+			}
+		}
+		return os.ReadFile(clusterConfigFile)
+	}
+
+	return cluster.RenderKindConfig(cluster.KindOptions{
+		Name:            clusterName,
+		Nodes:           clusterNodes,
+		K8sVersion:      clusterK8sVersion,
+		HostPort:        clusterHostPort,
+		Mounts:          clusterMounts,
+		RegistryMirrors: clusterRegistryMirror,
+	})
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/executil"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/k8s/k8sfake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var createArgs = []string{"create", "cluster", "--provider", "kind", "--skip-port-forward", "--skip-browser"}
+
+func TestCreateCluster(t *testing.T) {
+	client, _, dynamicClient := k8sfake.NewClient()
+	env := newTestEnv(t, client)
+	env.runner.On("kubectl config current-context", executil.Response{Output: "minha-conta\n"})
+
+	out, err := env.run(t, createArgs...)
+	if err != nil {
+		t.Fatalf("create cluster: %v\n%s", err, out)
+	}
+
+	for _, command := range []string{
+		"docker --version",
+		"docker info",
+		"kind create cluster --name girus",
+		"kubectl config use-context minha-conta",
+		"kubectl --context kind-girus get configmap -n girus",
+	} {
+		if !env.runner.Ran(command) {
+			t.Errorf("%q não executado; comandos: %q", command, env.runner.Commands())
+		}
+	}
+
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	if _, err := dynamicClient.Resource(deployments).Namespace("girus").Get(context.Background(), "girus-backend", metav1.GetOptions{}); err != nil {
+		t.Errorf("backend não implantado: %v", err)
+	}
+	if !strings.Contains(out, "girus connect --detach") {
+		t.Errorf("saída sem instruções de acesso:\n%s", out)
+	}
+}
+
+func TestCreateClusterMissingBinary(t *testing.T) {
+	for _, binary := range []string{"docker", "kind"} {
+		t.Run(binary, func(t *testing.T) {
+			client, _, _ := k8sfake.NewClient()
+			env := newTestEnv(t, client)
+			env.runner.Missing(binary)
+
+			out, err := env.run(t, createArgs...)
+			if err == nil {
+				t.Fatalf("esperado erro com %s ausente\n%s", binary, out)
+			}
+			if binary == "docker" && env.runner.Ran("kind create cluster") {
+				t.Error("cluster criado sem o docker")
+			}
+		})
+	}
+}
+
+func TestCreateClusterFailure(t *testing.T) {
+	client, _, dynamicClient := k8sfake.NewClient()
+	env := newTestEnv(t, client)
+	env.runner.On("kind create cluster", executil.Response{
+		Output:   "ERROR: failed to create cluster: node(s) already exist for a cluster with the name \"girus\"",
+		ExitCode: 1,
+	})
+
+	out, err := env.run(t, createArgs...)
+	if err == nil || !strings.Contains(err.Error(), "Erro ao criar o cluster Girus") {
+		t.Fatalf("erro = %v, esperado falha ao criar o cluster", err)
+	}
+	if !strings.Contains(out, "Já existe um cluster") {
+		t.Errorf("saída sem o diagnóstico do kind:\n%s", out)
+	}
+	if len(dynamicClient.Actions()) != 0 {
+		t.Errorf("manifestos aplicados após a falha: %v", dynamicClient.Actions())
+	}
+}
+
+func TestCreateClusterKindConfig(t *testing.T) {
+	env := newTestEnv(t, nil)
+	dir := t.TempDir()
+	kindConfig := filepath.Join(dir, "kind.yaml")
+	girusConfig := filepath.Join(dir, "config.yaml")
+	content := "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\n"
+	if err := os.WriteFile(kindConfig, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(girusConfig, []byte("provider: kind\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// --kind-config não colide com a flag global --config
+	out, err := env.run(t, "create", "cluster", "--config", girusConfig, "--kind-config", kindConfig, "--dry-run")
+	if err != nil {
+		t.Fatalf("create cluster --kind-config: %v\n%s", err, out)
+	}
+	if out != content {
+		t.Errorf("saída = %q, esperada a configuração do arquivo", out)
+	}
+
+	if _, err := env.run(t, "create", "cluster", "--provider", "k3d", "--kind-config", kindConfig); err == nil || !strings.Contains(err.Error(), "--kind-config") {
+		t.Errorf("esperado erro de --kind-config com o provider k3d, obtido %v", err)
+	}
+}
+
+func TestCreateLabFromFile(t *testing.T) {
+	client, clientset, dynamicClient := k8sfake.NewClient(readyBackend())
+	env := newTestEnv(t, client)
+	env.runner.On("kubectl --context kind-girus get namespace girus", executil.Response{Output: "girus   Active   1d\n"})
+	env.runner.On("kubectl --context kind-girus get pods -n girus -l app=girus-backend", executil.Response{Output: "Running"})
+
+	out, err := env.run(t, "create", "lab", "-f", filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+	if err != nil {
+		t.Fatalf("create lab -f: %v\n%s", err, out)
+	}
+
+	configmaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	if _, err := dynamicClient.Resource(configmaps).Namespace("girus").Get(context.Background(), "docker-fundamentos-lab", metav1.GetOptions{}); err != nil {
+		t.Errorf("laboratório não aplicado: %v", err)
+	}
+	backend, err := clientset.AppsV1().Deployments("girus").Get(context.Background(), "girus-backend", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := backend.Spec.Template.Annotations[k8s.RestartedAtAnnotation]; !ok {
+		t.Error("backend não reiniciado")
+	}
+}
+
+func TestCreateLabFromFileWithoutCluster(t *testing.T) {
+	client, _, dynamicClient := k8sfake.NewClient(readyBackend())
+	env := newTestEnv(t, client)
+
+	_, err := env.run(t, "create", "lab", "-f", filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+	if common.ExitCode(err) != common.ExitClusterNotFound {
+		t.Fatalf("código = %d (%v), esperado %d", common.ExitCode(err), err, common.ExitClusterNotFound)
+	}
+	if !env.runner.Ran("kubectl --context kind-girus get namespace girus") {
+		t.Errorf("cluster não verificado; comandos: %q", env.runner.Commands())
+	}
+	if len(dynamicClient.Actions()) != 0 {
+		t.Errorf("manifestos aplicados: %v", dynamicClient.Actions())
+	}
+}
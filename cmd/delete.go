@@ -1,16 +1,11 @@
 package cmd
 
 import (
-	"bufio"
-	"bytes"
 	"fmt"
-	"os"
-	"os/exec"
-	"strings"
-	"time"
+	"io"
 
+	"github.com/badtuxx/girus-cli/internal/cluster"
 	"github.com/badtuxx/girus-cli/internal/common"
-	"github.com/badtuxx/girus-cli/internal/helpers"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -27,10 +22,11 @@ var deleteCmd = &cobra.Command{
 }
 
 var deleteClusterCmd = &cobra.Command{
-	Use:   "cluster",
-	Short: common.T("Exclui o cluster Girus", "Elimina el cluster Girus"),
-	Long:  common.T("Exclui o cluster Girus do sistema, incluindo todos os recursos do Girus.", "Elimina el cluster Girus del sistema, incluyendo todos los recursos de Girus."),
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:          "cluster",
+	Short:        common.T("Exclui o cluster Girus", "Elimina el cluster Girus"),
+	Long:         common.T("Exclui o cluster Girus do sistema, incluindo todos os recursos do Girus.", "Elimina el cluster Girus del sistema, incluyendo todos los recursos de Girus."),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Criar formatadores de cores
 		red := color.New(color.FgRed).SprintFunc()
 		green := color.New(color.FgGreen).SprintFunc()
@@ -40,26 +36,22 @@ var deleteClusterCmd = &cobra.Command{
 
 		clusterName := "girus"
 
-		// Verificar se o cluster existe
-		checkCmd := exec.Command("kind", "get", "clusters")
-		output, err := checkCmd.Output()
+		provider, err := clusterProvider()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("ERRO:"), common.T("Erro ao obter lista de clusters", "Error al obtener la lista de clusters"), err)
-			os.Exit(1)
+			return fmt.Errorf("%s %v", red("ERRO:"), err)
+		}
+		if provider.Name() == cluster.ProviderBYO {
+			return fmt.Errorf("%s %s", red("ERRO:"), common.T("O provider byo usa um cluster existente, que não é excluído pelo Girus", "El proveedor byo usa un cluster existente, que Girus no elimina"))
 		}
 
-		clusters := strings.Split(strings.TrimSpace(string(output)), "\n")
-		clusterExists := false
-		for _, cluster := range clusters {
-			if cluster == clusterName {
-				clusterExists = true
-				break
-			}
+		// Verificar se o cluster existe
+		clusterExists, err := provider.Exists(cmd.Context(), clusterName)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", red("ERRO:"), common.T("Erro ao obter lista de clusters", "Error al obtener la lista de clusters"), err)
 		}
 
 		if !clusterExists {
-			fmt.Fprintf(os.Stderr, "%s %s %s %s\n", red("ERRO:"), common.T("Cluster", "Cluster"), magenta("girus"), common.T("não encontrado", "no encontrado"))
-			os.Exit(1)
+			return common.WithExitCode(common.ExitClusterNotFound, fmt.Errorf("%s %s %s %s", red("ERRO:"), common.T("Cluster", "Cluster"), magenta("girus"), common.T("não encontrado", "no encontrado")))
 		}
 
 		// Confirmar a exclusão se -f/--force não estiver definido
@@ -67,82 +59,27 @@ var deleteClusterCmd = &cobra.Command{
 			fmt.Printf(common.T("%s Você está prestes a excluir o cluster %s. Esta ação é irreversível.\n",
 				"%s Está a punto de eliminar el cluster %s. Esta acción es irreversible.\n"),
 				yellow("AVISO:"), magenta(clusterName))
-			fmt.Print(common.T("Deseja continuar? [s/N]: ", "¿Desea continuar? [s/N]: "))
-
-			reader := bufio.NewReader(os.Stdin)
-			confirmStr, _ := reader.ReadString('\n')
-			confirm := strings.TrimSpace(strings.ToLower(confirmStr))
-
-			if confirm != "s" && confirm != "sim" && confirm != "y" && confirm != "yes" {
+			ok, err := confirm(common.T("Deseja continuar?", "¿Desea continuar?"), false)
+			if err != nil {
+				return err
+			}
+			if !ok {
 				fmt.Println(common.T("Operação cancelada pelo usuário.", "Operación cancelada por el usuario."))
-				return
+				return nil
 			}
 		}
 
 		fmt.Println(headerColor(common.T("Excluindo o cluster Girus...", "Eliminando el cluster Girus...")))
 
-		if verboseDelete {
-			// Excluir o cluster mostrando o output normal
-			deleteCmd := exec.Command("kind", "delete", "cluster", "--name", clusterName)
-			deleteCmd.Stdout = os.Stdout
-			deleteCmd.Stderr = os.Stderr
-
-			if err := deleteCmd.Run(); err != nil {
-				fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("ERRO:"), common.T("Erro ao excluir o cluster Girus", "Error al eliminar el cluster Girus"), err)
-				os.Exit(1)
-			}
-		} else {
-			// Usando barra de progresso (padrão)
-			barConfig := helpers.ProgressBarConfig{
-				Total:            100,
-				Description:      common.T("Excluindo cluster...", "Eliminando cluster..."),
-				Width:            80,
-				Throttle:         65,
-				SpinnerType:      14,
-				RenderBlankState: true,
-				ShowBytes:        false,
-				SetPredictTime:   false,
-			}
-			bar := helpers.CreateProgressBar(barConfig)
-
-			// Executar comando sem mostrar saída
-			deleteCmd := exec.Command("kind", "delete", "cluster", "--name", clusterName)
-			var stderr bytes.Buffer
-			deleteCmd.Stderr = &stderr
-
-			// Iniciar o comando
-			err := deleteCmd.Start()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("ERRO:"), common.T("Erro ao iniciar o comando", "Error al iniciar el comando"), err)
-				os.Exit(1)
-			}
-
-			// Atualizar a barra de progresso enquanto o comando está em execução
-			done := make(chan struct{})
-			go func() {
-				for {
-					select {
-					case <-done:
-						return
-					default:
-						bar.Add(1)
-						time.Sleep(150 * time.Millisecond)
-					}
-				}
-			}()
-
-			// Aguardar o final do comando
-			err = deleteCmd.Wait()
-			close(done)
-			bar.Finish()
-
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "%s %s: %v\n%s\n", red("ERRO:"), common.T("Erro ao excluir o cluster Girus", "Error al eliminar el cluster Girus"), err, stderr.String())
-				os.Exit(1)
-			}
+		details, err := runWithProgress(verboseDelete, common.T("Excluindo cluster...", "Eliminando cluster..."), func(out io.Writer) error {
+			return provider.Delete(cmd.Context(), clusterName, out)
+		})
+		if err != nil {
+			return fmt.Errorf("%s %s: %w\n%s", red("ERRO:"), common.T("Erro ao excluir o cluster Girus", "Error al eliminar el cluster Girus"), err, details)
 		}
 
 		fmt.Println("\n" + green(common.T("SUCESSO:", "ÉXITO:")) + " " + common.T("Cluster", "Cluster") + " " + magenta("Girus") + " " + common.T("excluído com sucesso!", "eliminado con éxito!"))
+		return nil
 	},
 }
 
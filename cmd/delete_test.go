@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/executil"
+)
+
+func TestDeleteCluster(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.On("kind get clusters", executil.Response{Output: "girus\noutro\n"})
+
+	if out, err := env.run(t, "delete", "cluster", "--provider", "kind", "--force"); err != nil {
+		t.Fatalf("delete cluster: %v\n%s", err, out)
+	}
+	if !env.runner.Ran("kind delete cluster --name girus") {
+		t.Errorf("cluster não excluído; comandos: %q", env.runner.Commands())
+	}
+}
+
+func TestDeleteClusterNotFound(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.On("kind get clusters", executil.Response{Output: "outro\n"})
+
+	_, err := env.run(t, "delete", "cluster", "--provider", "kind", "--force")
+	if err == nil || !strings.Contains(err.Error(), "não encontrado") {
+		t.Fatalf("erro = %v, esperado cluster não encontrado", err)
+	}
+	if env.runner.Ran("kind delete") {
+		t.Error("exclusão executada sem o cluster")
+	}
+}
+
+func TestDeleteClusterMissingBinary(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.Missing("kind")
+
+	_, err := env.run(t, "delete", "cluster", "--provider", "kind", "--force")
+	if err == nil || !strings.Contains(err.Error(), "kind") {
+		t.Fatalf("erro = %v, esperado kind ausente", err)
+	}
+}
+
+func TestDeleteClusterFailure(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.On("kind get clusters", executil.Response{Output: "girus\n"})
+	env.runner.On("kind delete cluster", executil.Response{Output: "ERROR: failed to delete cluster", ExitCode: 1})
+
+	if _, err := env.run(t, "delete", "cluster", "--provider", "kind", "--force"); err == nil {
+		t.Fatal("esperado erro na exclusão")
+	}
+}
+
+func TestDeleteClusterBYO(t *testing.T) {
+	env := newTestEnv(t, nil)
+
+	if _, err := env.run(t, "delete", "cluster", "--provider", "byo", "--force"); err == nil {
+		t.Fatal("esperado erro no provider byo")
+	}
+	if len(env.runner.Commands()) != 0 {
+		t.Errorf("comandos executados: %q", env.runner.Commands())
+	}
+}
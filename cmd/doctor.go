@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/doctor"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Flags do girus doctor
+var (
+	doctorEngine       string
+	doctorFrontendPort int
+	doctorBackendPort  int
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: common.T("Diagnostica o ambiente do GIRUS", "Diagnostica el entorno de GIRUS"),
+	Long: common.T(`Verifica o ambiente em que o GIRUS é executado: engine de containers, kind/k3d/minikube,
+kubectl, portas locais 8000 e 8080, limites do inotify, versão do cgroup, espaço em disco,
+acesso ao cluster e saúde do backend. Cada verificação mostra OK, AVISO ou FALHA com uma
+dica de correção. Use -o json para anexar o relatório a um pedido de suporte.`,
+		`Verifica el entorno en el que se ejecuta GIRUS: motor de contenedores, kind/k3d/minikube,
+kubectl, puertos locales 8000 y 8080, límites de inotify, versión de cgroup, espacio en disco,
+acceso al cluster y salud del backend. Cada verificación muestra OK, AVISO o FALLO con una
+sugerencia de corrección. Use -o json para adjuntar el informe a una solicitud de soporte.`),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		provider, err := clusterProvider()
+		if err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+
+		report := doctor.Run(cmd.Context(), doctor.Options{
+			Runner:          runner,
+			Provider:        provider.Name(),
+			ContainerEngine: doctorEngine,
+			FrontendPort:    doctorFrontendPort,
+			BackendPort:     doctorBackendPort,
+			NewClient:       newKubernetesClient,
+		})
+
+		if machineOutput() {
+			doc := DoctorDocument{
+				Document:   newDocument("DoctorReport"),
+				CLIVersion: common.Version,
+				OS:         runtime.GOOS,
+				Arch:       runtime.GOARCH,
+				Provider:   provider.Name(),
+				Summary: DoctorSummary{
+					Pass: report.Count(doctor.StatusPass),
+					Warn: report.Count(doctor.StatusWarn),
+					Fail: report.Count(doctor.StatusFail),
+				},
+				Checks: report.Checks,
+			}
+			if err := printDocument(os.Stdout, doc); err != nil {
+				return err
+			}
+		} else {
+			printDoctorReport(report)
+		}
+
+		if report.Failed() {
+			return fmt.Errorf("%s %s", red(common.T("ERRO:", "ERROR:")), fmt.Sprintf(common.T("o diagnóstico encontrou %d falha(s)", "el diagnóstico encontró %d fallo(s)"), report.Count(doctor.StatusFail)))
+		}
+		return nil
+	},
+}
+
+// printDoctorReport mostra o resultado das verificações com as dicas de correção
+func printDoctorReport(report doctor.Report) {
+	headerColor := color.New(color.FgCyan, color.Bold).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+
+	fmt.Println(headerColor(common.T("DIAGNÓSTICO DO GIRUS", "DIAGNÓSTICO DE GIRUS")))
+	fmt.Println(strings.Repeat("─", 80))
+
+	for _, check := range report.Checks {
+		var label string
+		switch check.Status {
+		case doctor.StatusPass:
+			label = green(fmt.Sprintf("%-6s", "OK"))
+		case doctor.StatusWarn:
+			label = yellow(fmt.Sprintf("%-6s", common.T("AVISO", "AVISO")))
+		default:
+			label = red(fmt.Sprintf("%-6s", common.T("FALHA", "FALLO")))
+		}
+		fmt.Printf("%s %-17s %s\n", label, check.Name, check.Message)
+		if check.Hint != "" {
+			fmt.Printf("       %s %s\n", magenta("→"), check.Hint)
+		}
+	}
+
+	fmt.Println(strings.Repeat("─", 80))
+	fmt.Printf(common.T("%d OK, %d aviso(s), %d falha(s)\n", "%d OK, %d aviso(s), %d fallo(s)\n"),
+		report.Count(doctor.StatusPass), report.Count(doctor.StatusWarn), report.Count(doctor.StatusFail))
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().StringVarP(&doctorEngine, "container-engine", "e", "docker", common.T("Engine de container (docker ou podman)", "Motor de contenedores (docker o podman)"))
+	doctorCmd.Flags().IntVar(&doctorFrontendPort, "frontend-port", 8000, common.T("Porta local do frontend", "Puerto local del frontend"))
+	doctorCmd.Flags().IntVar(&doctorBackendPort, "backend-port", 8080, common.T("Porta local do backend", "Puerto local del backend"))
+}
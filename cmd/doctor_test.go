@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/executil"
+	"github.com/badtuxx/girus-cli/internal/k8s/k8sfake"
+)
+
+// freePort retorna uma porta TCP local livre
+func freePort(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+}
+
+func TestDoctor(t *testing.T) {
+	client, clientset, _ := k8sfake.NewClient()
+	k8sfake.BackendHealth(clientset, nil)
+	env := newTestEnv(t, client)
+	scriptGirusCluster(env.runner)
+	env.runner.On("kind version", executil.Response{Output: "kind v0.27.0 go1.23.4 linux/amd64\n"})
+
+	out, err := env.run(t, "doctor", "--provider", "kind", "--frontend-port", freePort(t), "--backend-port", freePort(t))
+	if err != nil {
+		t.Fatalf("doctor: %v\n%s", err, out)
+	}
+	for _, want := range []string{"DIAGNÓSTICO DO GIRUS", "kind v0.27.0", "backend respondendo em /api/v1/health"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("saída sem %q:\n%s", want, out)
+		}
+	}
+	if !env.runner.Ran("kubectl --context kind-girus get --raw /readyz") {
+		t.Errorf("contexto do GIRUS não verificado; comandos: %q", env.runner.Commands())
+	}
+}
+
+func TestDoctorJSON(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.Missing("kind")
+
+	out, err := env.run(t, "doctor", "--provider", "kind", "-o", "json", "--frontend-port", freePort(t), "--backend-port", freePort(t))
+	if code := common.ExitCode(err); code != common.ExitFailure {
+		t.Errorf("código = %d (%v), esperado %d", code, err, common.ExitFailure)
+	}
+
+	var doc DoctorDocument
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("saída não é JSON: %v\n%s", err, out)
+	}
+	if doc.Kind != "DoctorReport" || doc.Provider != "kind" || doc.Summary.Fail != 1 {
+		t.Errorf("documento = %+v", doc)
+	}
+	for _, c := range doc.Checks {
+		if c.Name == "kind" && (c.Status != "fail" || c.Hint == "") {
+			t.Errorf("verificação do kind = %+v, esperado fail com dica", c)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/executil"
+	"github.com/badtuxx/girus-cli/internal/k8s/k8sfake"
+)
+
+func TestDeleteClusterRequiresConfirmation(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.On("kind get clusters", executil.Response{Output: "girus\n"})
+
+	_, err := env.run(t, "delete", "cluster", "--provider", "kind")
+	if code := common.ExitCode(err); code != common.ExitInteractionRequired {
+		t.Fatalf("código = %d (%v), esperado %d", code, err, common.ExitInteractionRequired)
+	}
+	if env.runner.Ran("kind delete") {
+		t.Error("cluster excluído sem confirmação")
+	}
+}
+
+func TestDeleteClusterYes(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.On("kind get clusters", executil.Response{Output: "girus\n"})
+
+	if out, err := env.run(t, "delete", "cluster", "--provider", "kind", "--yes"); err != nil {
+		t.Fatalf("delete cluster --yes: %v\n%s", err, out)
+	}
+	if !env.runner.Ran("kind delete cluster --name girus") {
+		t.Errorf("cluster não excluído; comandos: %q", env.runner.Commands())
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		script func(env *testEnv)
+		args   []string
+		want   int
+	}{
+		{
+			name:   "cluster ausente",
+			script: func(env *testEnv) { env.runner.On("kind get clusters", executil.Response{Output: "outro\n"}) },
+			args:   []string{"status", "--provider", "kind"},
+			want:   common.ExitClusterNotFound,
+		},
+		{
+			name:   "exclusão de cluster ausente",
+			script: func(env *testEnv) { env.runner.On("kind get clusters", executil.Response{Output: "outro\n"}) },
+			args:   []string{"delete", "cluster", "--provider", "kind", "--yes"},
+			want:   common.ExitClusterNotFound,
+		},
+		{
+			name:   "binário ausente",
+			script: func(env *testEnv) { env.runner.Missing("kind") },
+			args:   []string{"status", "--provider", "kind"},
+			want:   common.ExitDependencyMissing,
+		},
+		{
+			name: "backend parado",
+			script: func(env *testEnv) {
+				scriptGirusCluster(env.runner)
+				env.runner.On("kubectl --context kind-girus get pods -n girus -l app=girus-backend -o jsonpath={.items[0].status.phase}", executil.Response{Output: "Pending"})
+			},
+			args: []string{"list", "labs", "--provider", "kind"},
+			want: common.ExitBackendUnhealthy,
+		},
+		{
+			name: "laboratório inexistente",
+			script: func(env *testEnv) {
+				serveLabRepository(t, filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+			},
+			args: []string{"lab", "install", "teste", "inexistente"},
+			want: common.ExitLabNotFound,
+		},
+		{
+			name: "versão inexistente",
+			script: func(env *testEnv) {
+				serveLabRepository(t, filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+			},
+			args: []string{"lab", "install", "teste", "docker-fundamentos@^2"},
+			want: common.ExitLabNotFound,
+		},
+		{
+			name: "restrição de versão inválida",
+			script: func(env *testEnv) {
+				serveLabRepository(t, filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+			},
+			args: []string{"lab", "install", "teste", "docker-fundamentos@abc"},
+			want: common.ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _, _ := k8sfake.NewClient(readyBackend())
+			env := newTestEnv(t, client)
+			tt.script(env)
+
+			_, err := env.run(t, tt.args...)
+			if code := common.ExitCode(err); code != tt.want {
+				t.Errorf("código = %d (%v), esperado %d", code, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteUsageError(t *testing.T) {
+	newTestEnv(t, nil)
+	resetFlags(rootCmd)
+	commandStarted = false
+	rootCmd.SetErr(io.Discard)
+	t.Cleanup(func() { rootCmd.SetErr(nil) })
+
+	rootCmd.SetArgs([]string{"status", "--flag-inexistente"})
+	if code := common.ExitCode(Execute()); code != common.ExitUsage {
+		t.Errorf("código = %d, esperado %d", code, common.ExitUsage)
+	}
+}
+
+func TestErrorsPrintedOnce(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.On("kind get clusters", executil.Response{Output: "outro\n"})
+
+	if _, err := env.run(t, "status", "--provider", "kind"); err == nil {
+		t.Fatal("esperado erro sem o cluster")
+	}
+	// O erro é exibido apenas pelo main
+	if strings.Contains(env.stderr, "Error:") {
+		t.Errorf("o cobra exibiu o erro:\n%s", env.stderr)
+	}
+}
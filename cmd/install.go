@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/lab"
+	"github.com/badtuxx/girus-cli/internal/templates"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	installSkipLabs bool
+	installTimeout  time.Duration
+	uninstallForce  bool
+)
+
+var installCmd = &cobra.Command{
+	Use:   "install",
+	Short: common.T("Instala o GIRUS em um cluster existente", "Instala GIRUS en un clúster existente"),
+	Long: common.T(`Instala o GIRUS em um cluster Kubernetes existente, no contexto do kubeconfig e no
+namespace informados, sem criar um cluster local. Antes de aplicar os manifestos embutidos,
+as permissões necessárias são verificadas com SelfSubjectAccessReviews.
+
+Todos os objetos criados recebem as labels app.kubernetes.io/managed-by=girus-cli e
+girus.linuxtips.io/instance=<namespace>, usadas pelo 'girus uninstall' para remover
+exatamente o que foi instalado.`,
+		`Instala GIRUS en un clúster Kubernetes existente, en el contexto del kubeconfig y en el
+namespace indicados, sin crear un clúster local. Antes de aplicar los manifiestos embebidos,
+los permisos necesarios se verifican con SelfSubjectAccessReviews.
+
+Todos los objetos creados reciben las etiquetas app.kubernetes.io/managed-by=girus-cli y
+girus.linuxtips.io/instance=<namespace>, usadas por 'girus uninstall' para eliminar
+exactamente lo que se instaló.`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yellow := color.New(color.FgYellow).SprintFunc()
+		magenta := color.New(color.FgMagenta).SprintFunc()
+		installNamespace := k8s.Namespace()
+
+		manifests, err := installManifests(!installSkipLabs)
+		if err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+
+		client, err := newKubernetesClient()
+		if err != nil {
+			return fmt.Errorf("%s %s: %v", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao conectar ao cluster", "Error al conectar al clúster"), err)
+		}
+
+		fmt.Println(headerColor(common.T("Instalando o GIRUS no namespace", "Instalando GIRUS en el namespace")) + " " + magenta(installNamespace) + "...")
+		results, err := client.Install(cmd.Context(), installNamespace, manifests...)
+		for _, r := range results {
+			fmt.Println("   " + r.String())
+		}
+		var permErr *k8s.PermissionError
+		if errors.As(err, &permErr) {
+			return fmt.Errorf("%s %v\n%s", red(common.T("ERRO:", "ERROR:")), err,
+				common.T("Peça ao administrador do cluster as permissões acima ou use outro contexto com --context.", "Solicite al administrador del clúster los permisos anteriores o use otro contexto con --context."))
+		}
+		if err != nil {
+			return fmt.Errorf("%s %s: %v", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao aplicar os manifestos do GIRUS", "Error al aplicar los manifiestos de GIRUS"), err)
+		}
+		fmt.Printf("%s %s\n", green(common.T("SUCESSO:", "ÉXITO:")), common.T("Manifestos do GIRUS aplicados.", "Manifiestos de GIRUS aplicados."))
+
+		if installTimeout > 0 {
+			fmt.Println("\n" + headerColor(common.T("Aguardando os componentes do GIRUS...", "Esperando los componentes de GIRUS...")))
+			ctx, cancel := context.WithTimeout(cmd.Context(), installTimeout)
+			defer cancel()
+			if err := client.WaitForComponentsReady(ctx, installNamespace, k8s.GirusComponents...); err != nil {
+				fmt.Fprintf(os.Stderr, "%s %v\n", yellow(common.T("AVISO:", "AVISO:")), err)
+				fmt.Printf(common.T("Verifique o estado dos pods com 'kubectl get pods -n %s'\n", "Verifique el estado de los pods con 'kubectl get pods -n %s'\n"), installNamespace)
+			} else {
+				fmt.Printf("%s %s\n", green(common.T("SUCESSO:", "ÉXITO:")), common.T("Todos os componentes do GIRUS estão prontos!", "¡Todos los componentes de GIRUS están listos!"))
+			}
+		}
+
+		fmt.Println("\n" + common.T("Para acessar o GIRUS, use:", "Para acceder a GIRUS, use:"))
+		fmt.Println("  " + magenta("girus connect --namespace "+installNamespace))
+		return nil
+	},
+}
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: common.T("Remove o GIRUS instalado com 'girus install'", "Elimina GIRUS instalado con 'girus install'"),
+	Long: common.T(`Remove os objetos criados pelo 'girus install' no contexto e no namespace informados.
+Apenas objetos com as labels de rastreamento da instalação são removidos; um namespace que
+já existia antes da instalação é mantido.`,
+		`Elimina los objetos creados por 'girus install' en el contexto y el namespace indicados.
+Solo se eliminan los objetos con las etiquetas de seguimiento de la instalación; un namespace
+que ya existía antes de la instalación se mantiene.`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yellow := color.New(color.FgYellow).SprintFunc()
+		magenta := color.New(color.FgMagenta).SprintFunc()
+		installNamespace := k8s.Namespace()
+
+		manifests, err := installManifests(true)
+		if err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+
+		client, err := newKubernetesClient()
+		if err != nil {
+			return fmt.Errorf("%s %s: %v", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao conectar ao cluster", "Error al conectar al clúster"), err)
+		}
+
+		// Confirmar a remoção se -f/--force não estiver definido
+		if !uninstallForce {
+			fmt.Printf(common.T("%s Você está prestes a remover o GIRUS do namespace %s.\n", "%s Está a punto de eliminar GIRUS del namespace %s.\n"), yellow("AVISO:"), magenta(installNamespace))
+			ok, err := confirm(common.T("Deseja continuar?", "¿Desea continuar?"), false)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println(common.T("Operação cancelada pelo usuário.", "Operación cancelada por el usuario."))
+				return nil
+			}
+		}
+
+		results, err := client.Uninstall(cmd.Context(), installNamespace, manifests...)
+		for _, r := range results {
+			fmt.Println("   " + r.String())
+		}
+		if err != nil {
+			return fmt.Errorf("%s %s: %v", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao remover o GIRUS", "Error al eliminar GIRUS"), err)
+		}
+		if len(results) == 0 {
+			fmt.Printf(common.T("%s Nenhum objeto do GIRUS encontrado no namespace %s.\n", "%s Ningún objeto de GIRUS encontrado en el namespace %s.\n"), yellow("AVISO:"), magenta(installNamespace))
+			return nil
+		}
+		fmt.Printf("%s %s\n", green(common.T("SUCESSO:", "ÉXITO:")), common.T("GIRUS removido com sucesso!", "¡GIRUS eliminado con éxito!"))
+		return nil
+	},
+}
+
+// installManifests retorna o deployment embutido do GIRUS seguido, se
+// solicitado, dos templates de laboratório embutidos válidos
+func installManifests(withLabs bool) ([][]byte, error) {
+	deployment, err := templates.GetManifest("defaultDeployment.yaml")
+	if err != nil {
+		return nil, fmt.Errorf(common.T("erro ao carregar o deployment embutido: %v", "error al cargar el deployment embebido: %v"), err)
+	}
+	manifests := [][]byte{deployment}
+	if !withLabs {
+		return manifests, nil
+	}
+
+	names, err := templates.ListManifests()
+	if err != nil {
+		return nil, fmt.Errorf(common.T("erro ao listar templates embutidos: %v", "error al listar las plantillas embebidas: %v"), err)
+	}
+	for _, name := range names {
+		if name == "defaultDeployment.yaml" {
+			continue
+		}
+		content, err := templates.GetManifest(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := lab.Parse(content, name); err != nil {
+			return nil, fmt.Errorf(common.T("template %s inválido: %v", "plantilla %s inválida: %v"), name, err)
+		}
+		manifests = append(manifests, content)
+	}
+	return manifests, nil
+}
+
+func init() {
+	installCmd.Flags().BoolVar(&installSkipLabs, "skip-labs", false, common.T("Não instala os templates de laboratório embutidos", "No instala las plantillas de laboratorio embebidas"))
+	installCmd.Flags().DurationVar(&installTimeout, "timeout", 5*time.Minute, common.T("Tempo de espera pelos componentes (0 não espera)", "Tiempo de espera de los componentes (0 no espera)"))
+	uninstallCmd.Flags().BoolVarP(&uninstallForce, "force", "f", false, common.T("Remove sem confirmação", "Elimina sin confirmación"))
+}
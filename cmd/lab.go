@@ -1,16 +1,25 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/lab"
 	"github.com/badtuxx/girus-cli/internal/repo"
+	"github.com/badtuxx/girus-cli/internal/templates"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var labCmd = &cobra.Command{
@@ -30,39 +39,37 @@ var labListCmd = &cobra.Command{
 		magenta := color.New(color.FgMagenta).SprintFunc()
 		headerColor := color.New(color.FgCyan, color.Bold).SprintFunc()
 
-		rm, err := repo.NewRepositoryManager()
+		client, err := repo.NewClient("")
 		if err != nil {
 			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
 		}
 
-		lm, err := repo.NewLabManager(rm)
+		labs, err := listRepositoryLabs(cmd.Context(), client)
 		if err != nil {
-			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+			return withLabExitCode(fmt.Errorf("%s %w", red(common.T("ERRO:", "ERROR:")), err))
 		}
 
-		labs, err := lm.ListLabs()
-		if err != nil {
-			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		entries := repositoryLabs(labs, nil)
+		if machineOutput() {
+			return printDocument(os.Stdout, RepositoryLabListDocument{Document: newDocument("RepositoryLabList"), Items: entries})
 		}
 
 		fmt.Println(headerColor(common.T("LABORATÓRIOS DISPONÍVEIS", "LABORATORIOS DISPONIBLES")))
 		fmt.Println(strings.Repeat("─", 80))
 
-		if len(labs) == 0 {
+		if len(entries) == 0 {
 			fmt.Println(common.T("Nenhum laboratório disponível.", "Ningún laboratorio disponible."))
 			return nil
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 		fmt.Fprintln(w, cyan("NOME")+"\t"+cyan("VERSÃO")+"\t"+cyan("REPOSITÓRIO")+"\t"+cyan("DESCRIÇÃO"))
-		for repoName, entries := range labs {
-			for _, entry := range entries {
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
-					magenta(entry.ID),
-					entry.Version,
-					repoName,
-					entry.Description)
-			}
+		for _, entry := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				magenta(entry.ID),
+				entry.Version,
+				entry.Repository,
+				entry.Description)
 		}
 		w.Flush()
 
@@ -71,10 +78,16 @@ var labListCmd = &cobra.Command{
 }
 
 var labInstallCmd = &cobra.Command{
-	Use:   "install [repositório] [laboratório]",
+	Use:   "install [repositório] [laboratório[@versão]]",
 	Short: common.T("Instala um laboratório", "Instala un laboratorio"),
-	Long:  common.T(`Instala um laboratório específico de um repositório.`, `Instala un laboratorio específico de un repositorio.`),
-	Args:  cobra.ExactArgs(2),
+	Long: common.T(`Instala um laboratório específico de um repositório.
+A versão pode ser fixada ou restrita com a sintaxe de versionamento semântico
+(laboratório@1.2.0, laboratório@^1.2, laboratório@~1.2.3). Sem versão é
+instalada a versão estável mais alta.`, `Instala un laboratorio específico de un repositorio.
+La versión puede fijarse o restringirse con la sintaxis de versionado semántico
+(laboratorio@1.2.0, laboratorio@^1.2, laboratorio@~1.2.3). Sin versión se
+instala la versión estable más alta.`),
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Criar formatadores de cores
 		green := color.New(color.FgGreen).SprintFunc()
@@ -83,15 +96,15 @@ var labInstallCmd = &cobra.Command{
 		headerColor := color.New(color.FgCyan, color.Bold).SprintFunc()
 
 		repoName := args[0]
-		labName := args[1]
-		version, _ := cmd.Flags().GetString("version")
-
-		rm, err := repo.NewRepositoryManager()
-		if err != nil {
-			return fmt.Errorf("%s %v", red("ERRO:"), err)
+		labName, version := repo.ParseLabRef(args[1])
+		if flagVersion, _ := cmd.Flags().GetString("version"); flagVersion != "" {
+			if version != "" && version != flagVersion {
+				return common.WithExitCode(common.ExitUsage, fmt.Errorf("%s %s", red("ERRO:"), common.T("use laboratório@versão ou --version, não os dois", "use laboratorio@versión o --version, no ambos")))
+			}
+			version = flagVersion
 		}
 
-		lm, err := repo.NewLabManager(rm)
+		repos, err := repo.NewClient("")
 		if err != nil {
 			return fmt.Errorf("%s %v", red("ERRO:"), err)
 		}
@@ -100,31 +113,29 @@ var labInstallCmd = &cobra.Command{
 		fmt.Println(strings.Repeat("─", 80))
 		fmt.Printf(common.T("Instalando laboratório %s do repositório %s...\n", "Instalando el laboratorio %s del repositorio %s...\n"), magenta(labName), magenta(repoName))
 
-		if err := lm.DownloadLab(repoName, labName, version); err != nil {
-			return fmt.Errorf("%s %v", red("ERRO:"), err)
+		entry, err := repos.FindLab(cmd.Context(), repoName, labName, version)
+		if err != nil {
+			return withLabExitCode(fmt.Errorf("%s %w", red("ERRO:"), err))
 		}
-
-		fmt.Printf("%s %s %s %s\n", green(common.T("SUCESSO:", "ÉXITO:")), common.T("Laboratório", "Laboratorio"), magenta(labName), common.T("instalado com sucesso.", "instalado con éxito."))
-
-		// Reinicia o backend
-		fmt.Println("\n" + headerColor(common.T("REINICIANDO BACKEND", "REINICIANDO BACKEND")))
-		fmt.Println(strings.Repeat("─", 80))
-		fmt.Println(common.T("Reiniciando o backend para aplicar as mudanças...", "Reiniciando el backend para aplicar los cambios..."))
-
-		restartCmd := exec.Command("kubectl", "rollout", "restart", "deployment/girus-backend", "-n", "girus")
-		if err := restartCmd.Run(); err != nil {
-			return fmt.Errorf("%s %s: %v", red("ERRO:"), common.T("Erro ao reiniciar o backend", "Error al reiniciar el backend"), err)
+		if entry.Version != "" {
+			fmt.Printf(common.T("Versão selecionada: %s\n", "Versión seleccionada: %s\n"), magenta(entry.Version))
+		}
+		data, provenance, err := prepareRepositoryLab(cmd.Context(), repos, entry)
+		if err != nil {
+			return err
 		}
 
-		// Aguarda o reinício completar
-		fmt.Println(common.T("Aguardando o reinício do backend completar...", "Esperando a que el backend reinicie por completo..."))
-		waitCmd := exec.Command("kubectl", "rollout", "status", "deployment/girus-backend", "-n", "girus", "--timeout=60s")
-		if err := waitCmd.Run(); err != nil {
-			return fmt.Errorf("%s %s: %v", red("ERRO:"), common.T("Erro ao aguardar reinício do backend", "Error al esperar el reinicio del backend"), err)
+		client, err := newKubernetesClient()
+		if err != nil {
+			return fmt.Errorf("%s %s: %v", red("ERRO:"), common.T("Erro ao conectar ao cluster", "Error al conectar al clúster"), err)
+		}
+		if _, err := applyRepositoryLab(cmd.Context(), client, data, provenance); err != nil {
+			return err
 		}
-		fmt.Printf("%s Backend %s\n", green(common.T("SUCESSO:", "ÉXITO:")), common.T("reiniciado com sucesso.", "reiniciado con éxito."))
 
-		return nil
+		fmt.Printf("%s %s %s %s\n", green(common.T("SUCESSO:", "ÉXITO:")), common.T("Laboratório", "Laboratorio"), magenta(labName), common.T("instalado com sucesso.", "instalado con éxito."))
+
+		return restartBackend(cmd.Context(), client)
 	},
 }
 
@@ -142,19 +153,24 @@ var labSearchCmd = &cobra.Command{
 
 		term := strings.ToLower(args[0])
 
-		rm, err := repo.NewRepositoryManager()
+		client, err := repo.NewClient("")
 		if err != nil {
-			return fmt.Errorf("%s %s: %v", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao criar gerenciador de repositórios", "Error al crear el gestor de repositorios"), err)
+			return fmt.Errorf("%s %s: %v", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao criar o cliente de repositórios", "Error al crear el cliente de repositorios"), err)
 		}
 
-		lm, err := repo.NewLabManager(rm)
+		labs, err := listRepositoryLabs(cmd.Context(), client)
 		if err != nil {
-			return fmt.Errorf("%s %s: %v", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao criar gerenciador de laboratórios", "Error al crear el gestor de laboratorios"), err)
+			return withLabExitCode(fmt.Errorf("%s %s: %w", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao listar laboratórios", "Error al listar laboratorios"), err))
 		}
 
-		labs, err := lm.ListLabs()
-		if err != nil {
-			return fmt.Errorf("%s %s: %v", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao listar laboratórios", "Error al listar laboratorios"), err)
+		// Verifica se o termo está no título, descrição ou tags
+		entries := repositoryLabs(labs, func(entry repo.LabEntry) bool {
+			return containsCaseInsensitive(entry.Title, term) ||
+				containsCaseInsensitive(entry.Description, term) ||
+				containsCaseInsensitive(entry.Tags, term)
+		})
+		if machineOutput() {
+			return printDocument(os.Stdout, RepositoryLabListDocument{Document: newDocument("RepositoryLabList"), Items: entries})
 		}
 
 		fmt.Println(headerColor(common.T("BUSCA DE LABORATÓRIOS", "BÚSQUEDA DE LABORATORIOS")))
@@ -163,40 +179,513 @@ var labSearchCmd = &cobra.Command{
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 		fmt.Fprintln(w, cyan("NOME")+"\t"+cyan("VERSÃO")+"\t"+cyan("REPOSITÓRIO")+"\t"+cyan("DESCRIÇÃO"))
+		for _, entry := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				magenta(entry.ID),
+				entry.Version,
+				entry.Repository,
+				entry.Description)
+		}
+		w.Flush()
+
+		if len(entries) == 0 {
+			fmt.Printf("\n%s %s '%s'\n",
+				red(common.T("AVISO:", "AVISO:")), common.T("Nenhum laboratório encontrado para o termo", "Ningún laboratorio encontrado para el término"), magenta(term))
+		}
+
+		return nil
+	},
+}
+
+var labVersionsCmd = &cobra.Command{
+	Use:   "versions [laboratório]",
+	Short: common.T("Lista as versões publicadas de um laboratório", "Lista las versiones publicadas de un laboratorio"),
+	Long:  common.T(`Lista as versões de um laboratório publicadas nos repositórios configurados, da mais alta para a mais baixa, indicando a versão instalada por padrão.`, `Lista las versiones de un laboratorio publicadas en los repositorios configurados, de la más alta a la más baja, indicando la versión instalada por defecto.`),
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Criar formatadores de cores
+		red := color.New(color.FgRed).SprintFunc()
+		green := color.New(color.FgGreen).SprintFunc()
+		cyan := color.New(color.FgCyan).SprintFunc()
+		headerColor := color.New(color.FgCyan, color.Bold).SprintFunc()
+
+		client, err := repo.NewClient("")
+		if err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+		labName, _ := repo.ParseLabRef(args[0])
+		labs, err := client.Versions(cmd.Context(), labName)
+		if err != nil {
+			return withLabExitCode(fmt.Errorf("%s %w", red(common.T("ERRO:", "ERROR:")), err))
+		}
+		latest, _ := client.FindLab(cmd.Context(), "", labName, "")
+
+		versions := []LabVersion{}
+		for _, l := range labs {
+			versions = append(versions, LabVersion{
+				RepositoryLab: RepositoryLab{Repository: l.Repository.Name, LabEntry: l.LabEntry},
+				Default:       latest != nil && l.Repository.Name == latest.Repository.Name && l.Version == latest.Version,
+			})
+		}
+		if machineOutput() {
+			return printDocument(os.Stdout, LabVersionListDocument{Document: newDocument("LabVersionList"), Lab: labName, Items: versions})
+		}
+
+		fmt.Println(headerColor(common.T("VERSÕES DO LABORATÓRIO", "VERSIONES DEL LABORATORIO")) + " " + magenta(labName))
+		fmt.Println(strings.Repeat("─", 80))
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, cyan(common.T("VERSÃO", "VERSIÓN"))+"\t"+cyan(common.T("REPOSITÓRIO", "REPOSITORIO"))+"\t"+cyan(common.T("DESCRIÇÃO", "DESCRIPCIÓN")))
+		for _, v := range versions {
+			version := v.Version
+			if v.Default {
+				version = green(version + " " + common.T("(padrão)", "(por defecto)"))
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", version, v.Repository, v.Description)
+		}
+		w.Flush()
+
+		fmt.Println("\n" + common.T("Para instalar uma versão específica, use:", "Para instalar una versión específica, use:"))
+		fmt.Printf("  girus lab install <%s> %s@<%s>\n", common.T("repositório", "repositorio"), labName, common.T("versão", "versión"))
+		return nil
+	},
+}
+
+var labLintCmd = &cobra.Command{
+	Use:   "lint [arquivo|diretório]...",
+	Short: common.T("Valida manifestos de laboratório", "Valida manifiestos de laboratorio"),
+	Long: common.T(`Valida estaticamente manifestos de laboratório sem precisar de um cluster.
+Verifica o ConfigMap (namespace, label e chave lab.yaml), os campos obrigatórios,
+o formato das durações, os tipos das dicas, as validações e nomes repetidos.`,
+		`Valida estáticamente manifiestos de laboratorio sin necesitar un cluster.
+Verifica el ConfigMap (namespace, label y clave lab.yaml), los campos obligatorios,
+el formato de las duraciones, los tipos de las sugerencias, las validaciones y nombres repetidos.`),
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		strict, _ := cmd.Flags().GetBool("strict")
+
+		report, err := lab.LintPaths(args...)
+		if err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+
+		switch format {
+		case "json":
+			err = report.WriteJSON(os.Stdout)
+		case "sarif":
+			err = report.WriteSARIF(os.Stdout)
+		case "human", "":
+			printLintReport(report)
+		default:
+			return fmt.Errorf("%s %s: %s", red(common.T("ERRO:", "ERROR:")), common.T("formato desconhecido", "formato desconocido"), format)
+		}
+		if err != nil {
+			return err
+		}
 
-		found := false
-		for repoName, entries := range labs {
-			for _, entry := range entries {
-				// Verifica se o termo está no título, descrição ou tags
-				if containsCaseInsensitive(entry.Title, term) ||
-					containsCaseInsensitive(entry.Description, term) ||
-					containsCaseInsensitive(entry.Tags, term) {
-					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
-						magenta(entry.ID),
-						entry.Version,
-						repoName,
-						entry.Description)
-					found = true
+		if report.Errors() > 0 || (strict && report.Warnings() > 0) {
+			return fmt.Errorf(common.T("lint encontrou %d erro(s) e %d aviso(s)", "lint encontró %d error(es) y %d advertencia(s)"), report.Errors(), report.Warnings())
+		}
+		return nil
+	},
+}
+
+var labTestCmd = &cobra.Command{
+	Use:   "test [arquivo|laboratório]",
+	Short: common.T("Testa as validações de um laboratório em um container local", "Prueba las validaciones de un laboratorio en un contenedor local"),
+	Long: common.T(`Inicia a imagem do laboratório na engine de container local (docker ou podman),
+executa os comandos dos passos (blocos entre crases) como um script de solução e
+em seguida roda as validações de cada tarefa, informando o resultado por tarefa.
+
+O laboratório pode ser um arquivo de manifesto ou o nome de um template embutido.`,
+		`Inicia la imagen del laboratorio en el motor de contenedores local (docker o podman),
+ejecuta los comandos de los pasos (bloques entre comillas invertidas) como un script
+de solución y luego ejecuta las validaciones de cada tarea, informando el resultado por tarea.
+
+El laboratorio puede ser un archivo de manifiesto o el nombre de una plantilla embebida.`),
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		skipSolution, _ := cmd.Flags().GetBool("skip-solution")
+		keep, _ := cmd.Flags().GetBool("keep")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		labs, err := loadLabsForTest(args[0])
+		if err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+
+		failed := 0
+		for _, l := range labs {
+			fmt.Println(headerColor(common.T("TESTANDO LABORATÓRIO", "PROBANDO LABORATORIO")) + " " + magenta(l.Name))
+			fmt.Println(strings.Repeat("─", 80))
+			fmt.Printf(common.T("Iniciando a imagem %s com %s...\n", "Iniciando la imagen %s con %s...\n"), cyan(l.Image), labTestEngine)
+
+			results, err := runLabTest(cmd.Context(), l, keep, lab.TestOptions{
+				SkipSolution: skipSolution,
+				Timeout:      timeout,
+				OnCommand: func(task string, r lab.CommandResult) {
+					if r.Err == nil && !verbose {
+						return
+					}
+					status := green("OK")
+					if r.Err != nil {
+						status = yellow(common.T("FALHOU", "FALLÓ"))
+					}
+					fmt.Printf("   %s $ %s\n", status, r.Command)
+					if out := strings.TrimSpace(r.Output); out != "" && (verbose || r.Err != nil) {
+						fmt.Println(indent(out, "       "))
+					}
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+			}
+
+			for _, r := range results {
+				if len(r.Validations) == 0 {
+					fmt.Printf("%s %s %s\n", yellow("[-]"), r.Task, common.T("(sem validações)", "(sin validaciones)"))
+					continue
+				}
+				if r.Passed() {
+					fmt.Printf("%s %s\n", green("[✓]"), r.Task)
+					continue
+				}
+				failed++
+				fmt.Printf("%s %s\n", red("[✗]"), r.Task)
+				for _, v := range r.Validations {
+					if v.Passed {
+						continue
+					}
+					fmt.Printf("    $ %s\n", v.Validation.Command)
+					fmt.Printf("    %s\n", red(v.Reason))
+					if v.Validation.ErrorMessage != "" {
+						fmt.Printf("    %s\n", v.Validation.ErrorMessage)
+					}
 				}
 			}
+			fmt.Println()
 		}
 
-		w.Flush()
+		if failed > 0 {
+			return fmt.Errorf(common.T("%d tarefa(s) com validações falhando", "%d tarea(s) con validaciones fallando"), failed)
+		}
+		fmt.Println(green(common.T("Todas as validações passaram.", "Todas las validaciones pasaron.")))
+		return nil
+	},
+}
 
-		if !found {
-			fmt.Printf("\n%s %s '%s'\n",
-				red(common.T("AVISO:", "AVISO:")), common.T("Nenhum laboratório encontrado para o termo", "Ningún laboratorio encontrado para el término"), magenta(term))
+// labTestEngine é a engine de container usada pelo girus lab test
+var labTestEngine string
+
+// runLabTest inicia o container do laboratório e executa os testes. O container
+// é removido ao final, inclusive em caso de erro ou interrupção, exceto com
+// --keep.
+func runLabTest(ctx context.Context, l *lab.Lab, keep bool, opts lab.TestOptions) ([]lab.TaskResult, error) {
+	container, err := lab.StartContainer(ctx, runner, labTestEngine, l)
+	if err != nil {
+		return nil, err
+	}
+	if keep {
+		defer fmt.Printf(common.T("Container mantido: %s\n", "Contenedor mantenido: %s\n"), magenta(container.Name))
+	} else {
+		defer func() {
+			if err := container.Close(); err != nil {
+				fmt.Printf("%s %v\n", yellow(common.T("AVISO:", "AVISO:")), err)
+			}
+		}()
+	}
+
+	results := lab.RunTests(ctx, l, container, opts)
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(common.T("teste interrompido: %w", "prueba interrumpida: %w"), err)
+	}
+	return results, nil
+}
+
+var labConvertCmd = &cobra.Command{
+	Use:   "convert [arquivo]",
+	Short: common.T("Converte laboratórios entre ConfigMap e o formato kind: Lab", "Convierte laboratorios entre ConfigMap y el formato kind: Lab"),
+	Long: common.T(`Converte um laboratório entre o ConfigMap consumido pelo backend e o formato
+nativo (apiVersion: girus.linuxtips.io/v1, kind: Lab). Por padrão o formato de destino
+é o oposto do formato do arquivo; use --to para escolher.`,
+		`Convierte un laboratorio entre el ConfigMap consumido por el backend y el formato
+nativo (apiVersion: girus.linuxtips.io/v1, kind: Lab). Por defecto el formato de destino
+es el opuesto al formato del archivo; use --to para elegir.`),
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		output, _ := cmd.Flags().GetString("output-file")
+
+		manifests, err := lab.ParseFile(args[0])
+		if err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+		templates := lab.FindLabTemplates(manifests)
+		if len(templates) == 0 {
+			return fmt.Errorf("%s %s", red(common.T("ERRO:", "ERROR:")), common.T("o arquivo não contém um template de laboratório", "el archivo no contiene una plantilla de laboratorio"))
+		}
+
+		if to == "" {
+			to = "lab"
+			if lab.HasNativeLabs(templates) {
+				to = "configmap"
+			}
+		}
+
+		var buf bytes.Buffer
+		for i, m := range templates {
+			var data []byte
+			switch to {
+			case "configmap":
+				data, err = m.MarshalConfigMap()
+			case "lab":
+				var d *lab.LabDocument
+				if d, err = lab.NewLabDocument(m); err == nil {
+					data, err = d.Marshal()
+				}
+			default:
+				return fmt.Errorf("%s %s: %s", red(common.T("ERRO:", "ERROR:")), common.T("formato de destino desconhecido (use configmap ou lab)", "formato de destino desconocido (use configmap o lab)"), to)
+			}
+			if err != nil {
+				return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+			}
+			if i > 0 {
+				buf.WriteString("---\n")
+			}
+			buf.Write(data)
+		}
+
+		if output == "" {
+			_, err = os.Stdout.Write(buf.Bytes())
+			return err
+		}
+		if err := os.WriteFile(output, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+		fmt.Printf("%s %s %s\n", green(common.T("SUCESSO:", "ÉXITO:")), common.T("Laboratório convertido em", "Laboratorio convertido en"), magenta(output))
+		return nil
+	},
+}
+
+var labNewCmd = &cobra.Command{
+	Use:   "new [id]",
+	Short: common.T("Cria a estrutura de um novo laboratório", "Crea la estructura de un nuevo laboratorio"),
+	Long: common.T(`Gera o ConfigMap de um novo laboratório com uma tarefa de exemplo (passos, dicas e
+validação) em labs/<categoria>_<id>, um arquivo por idioma, e exibe a entrada
+correspondente para o index.yaml do repositório.
+
+Use --interactive para informar título, descrição e duração por prompts.`,
+		`Genera el ConfigMap de un nuevo laboratorio con una tarea de ejemplo (pasos, consejos y
+validación) en labs/<categoría>_<id>, un archivo por idioma, y muestra la entrada
+correspondiente para el index.yaml del repositorio.
+
+Use --interactive para informar título, descripción y duración mediante prompts.`),
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		category, _ := cmd.Flags().GetString("category")
+		langs, _ := cmd.Flags().GetStringSlice("lang")
+		dir, _ := cmd.Flags().GetString("dir")
+		baseURL, _ := cmd.Flags().GetString("base-url")
+		force, _ := cmd.Flags().GetBool("force")
+		askFields, _ := cmd.Flags().GetBool("interactive")
+
+		opts := lab.ScaffoldOptions{ID: args[0], Category: category}
+		opts.Title, _ = cmd.Flags().GetString("title")
+		opts.Description, _ = cmd.Flags().GetString("description")
+		opts.Duration, _ = cmd.Flags().GetString("duration")
+
+		if askFields {
+			if !interactive() {
+				return errInteractionRequired(common.T("dados do laboratório (--interactive)", "datos del laboratorio (--interactive)"))
+			}
+			reader := bufio.NewReader(os.Stdin)
+			prompt := func(label, current string) string {
+				fmt.Printf("%s [%s]: ", label, current)
+				answer, _ := reader.ReadString('\n')
+				if answer = strings.TrimSpace(answer); answer != "" {
+					return answer
+				}
+				return current
+			}
+			opts.Category = prompt(common.T("Categoria", "Categoría"), opts.Category)
+			opts.Title = prompt(common.T("Título", "Título"), opts.Title)
+			opts.Description = prompt(common.T("Descrição", "Descripción"), opts.Description)
+			opts.Duration = prompt(common.T("Duração", "Duración"), opts.Duration)
+		}
+
+		labDir := filepath.Join(dir, opts.DirName())
+		var files []*lab.ScaffoldFile
+		for _, lang := range langs {
+			opts.Lang = strings.TrimSpace(lang)
+			file, err := lab.Scaffold(opts)
+			if err != nil {
+				return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+			}
+			if _, err := os.Stat(filepath.Join(labDir, file.Name)); err == nil && !force {
+				return fmt.Errorf("%s %s: %s", red(common.T("ERRO:", "ERROR:")), common.T("o arquivo já existe (use --force para sobrescrever)", "el archivo ya existe (use --force para sobrescribir)"), filepath.Join(labDir, file.Name))
+			}
+			files = append(files, file)
+		}
+
+		if err := os.MkdirAll(labDir, 0755); err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+
+		fmt.Println(headerColor(common.T("NOVO LABORATÓRIO", "NUEVO LABORATORIO")))
+		fmt.Println(strings.Repeat("─", 80))
+
+		var entries []repo.LabEntry
+		for _, file := range files {
+			path := filepath.Join(labDir, file.Name)
+			if err := os.WriteFile(path, file.Content, 0644); err != nil {
+				return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+			}
+			fmt.Printf("%s %s\n", green(common.T("CRIADO", "CREADO")), magenta(path))
+
+			l := file.Lab
+			entries = append(entries, repo.LabEntry{
+				ID:          l.Name,
+				Title:       l.Title,
+				Description: l.Description,
+				Version:     "1.0.0",
+				Duration:    l.Duration,
+				Tags:        []string{opts.Category},
+				URL:         strings.TrimSuffix(baseURL, "/") + "/" + opts.DirName() + "/" + file.Name,
+			})
+		}
+
+		fmt.Println("\n" + headerColor(common.T("ENTRADA PARA O INDEX.YAML", "ENTRADA PARA EL INDEX.YAML")))
+		fmt.Println(strings.Repeat("─", 80))
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(map[string][]repo.LabEntry{"labs": entries}); err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
 		}
+		enc.Close()
 
+		fmt.Println("\n" + common.T("Próximos passos:", "Próximos pasos:"))
+		fmt.Printf("  girus lab lint %s\n", labDir)
+		fmt.Printf("  girus lab test %s\n", filepath.Join(labDir, files[0].Name))
 		return nil
 	},
 }
 
+// loadLabsForTest carrega os laboratórios de um arquivo de manifesto ou, se o
+// argumento não for um arquivo, do template embutido com o mesmo nome
+func loadLabsForTest(arg string) ([]*lab.Lab, error) {
+	var manifests []*lab.Manifest
+	if _, err := os.Stat(arg); err == nil {
+		manifests, err = lab.ParseFile(arg)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		names, err := templates.ListManifests()
+		if err != nil {
+			return nil, fmt.Errorf(common.T("erro ao listar templates: %w", "error al listar plantillas: %w"), err)
+		}
+		for _, name := range names {
+			data, err := templates.GetManifest(name)
+			if err != nil {
+				continue
+			}
+			parsed, err := lab.Parse(data, name)
+			if err != nil {
+				continue
+			}
+			for _, m := range lab.FindLabTemplates(parsed) {
+				if m.Lab.Name == arg {
+					manifests = append(manifests, m)
+				}
+			}
+		}
+		if len(manifests) == 0 {
+			return nil, fmt.Errorf(common.T("laboratório '%s' não encontrado", "laboratorio '%s' no encontrado"), arg)
+		}
+	}
+
+	var labs []*lab.Lab
+	for _, m := range lab.FindLabTemplates(manifests) {
+		labs = append(labs, m.Lab)
+	}
+	if len(labs) == 0 {
+		return nil, fmt.Errorf(common.T("'%s' não contém um template de laboratório", "'%s' no contiene una plantilla de laboratorio"), arg)
+	}
+	return labs, nil
+}
+
+// indent adiciona um prefixo a cada linha do texto
+func indent(text, prefix string) string {
+	return prefix + strings.ReplaceAll(text, "\n", "\n"+prefix)
+}
+
+// printLintReport exibe o resultado do lint agrupado por arquivo
+func printLintReport(report *lab.LintReport) {
+	fmt.Println(headerColor(common.T("LINT DE LABORATÓRIOS", "LINT DE LABORATORIOS")))
+	fmt.Println(strings.Repeat("─", 80))
+
+	currentFile := ""
+	for _, f := range report.Findings {
+		if f.File != currentFile {
+			currentFile = f.File
+			fmt.Println("\n" + magenta(currentFile))
+		}
+
+		level := yellow(common.T("AVISO", "AVISO"))
+		if f.Severity == lab.SeverityError {
+			level = red(common.T("ERRO", "ERROR"))
+		}
+
+		position := ""
+		if f.Line > 0 {
+			position = fmt.Sprintf("%d: ", f.Line)
+		}
+		fmt.Printf("   %s%s %s %s\n", position, level, f.Message, cyan("["+f.Rule+"]"))
+	}
+
+	if len(report.Findings) > 0 {
+		fmt.Println()
+	}
+	fmt.Println(strings.Repeat("─", 80))
+
+	summary := fmt.Sprintf(common.T("%d arquivo(s) verificado(s): %d erro(s), %d aviso(s)", "%d archivo(s) verificado(s): %d error(es), %d advertencia(s)"),
+		len(report.Files), report.Errors(), report.Warnings())
+	if report.Errors() > 0 {
+		fmt.Println(red(summary))
+	} else {
+		fmt.Println(green(summary))
+	}
+}
+
 func init() {
-	labCmd.AddCommand(labListCmd, labInstallCmd, labSearchCmd)
+	labCmd.AddCommand(labListCmd, labInstallCmd, labSearchCmd, labVersionsCmd, labLintCmd, labTestCmd, labConvertCmd, labNewCmd)
 
 	// Flags para os comandos
-	labInstallCmd.Flags().String("version", "", common.T("Versão específica do laboratório", "Versión específica del laboratorio"))
+	labInstallCmd.Flags().String("version", "", common.T("Versão ou restrição de versão do laboratório (ex.: 1.2.0, ^1.2)", "Versión o restricción de versión del laboratorio (ej.: 1.2.0, ^1.2)"))
+	labLintCmd.Flags().String("format", "human", common.T("Formato da saída (human, json ou sarif)", "Formato de la salida (human, json o sarif)"))
+	labTestCmd.Flags().StringVarP(&labTestEngine, "container-engine", "e", "docker", common.T("Engine de container (docker ou podman)", "Motor de contenedores (docker o podman)"))
+	labTestCmd.Flags().Bool("skip-solution", false, common.T("Executa apenas as validações, sem os comandos dos passos", "Ejecuta solo las validaciones, sin los comandos de los pasos"))
+	labTestCmd.Flags().Bool("keep", false, common.T("Mantém o container após o teste", "Mantiene el contenedor después de la prueba"))
+	labTestCmd.Flags().Duration("timeout", 2*time.Minute, common.T("Tempo limite de cada comando", "Tiempo límite de cada comando"))
+	labTestCmd.Flags().BoolP("verbose", "v", false, common.T("Exibe todos os comandos executados e suas saídas", "Muestra todos los comandos ejecutados y sus salidas"))
+	labConvertCmd.Flags().String("to", "", common.T("Formato de destino: configmap ou lab (padrão: o oposto do arquivo)", "Formato de destino: configmap o lab (por defecto: el opuesto al archivo)"))
+	labConvertCmd.Flags().StringP("output-file", "f", "", common.T("Arquivo de saída (padrão: saída padrão)", "Archivo de salida (por defecto: salida estándar)"))
+	labNewCmd.Flags().String("category", "linux", common.T("Categoria do laboratório (linux, docker, kubernetes, terraform ou aws)", "Categoría del laboratorio (linux, docker, kubernetes, terraform o aws)"))
+	labNewCmd.Flags().StringSlice("lang", []string{"pt", "es"}, common.T("Idiomas a gerar (pt, es)", "Idiomas a generar (pt, es)"))
+	labNewCmd.Flags().String("dir", "labs", common.T("Diretório onde o laboratório será criado", "Directorio donde se creará el laboratorio"))
+	labNewCmd.Flags().String("title", "", common.T("Título do laboratório", "Título del laboratorio"))
+	labNewCmd.Flags().String("description", "", common.T("Descrição do laboratório", "Descripción del laboratorio"))
+	labNewCmd.Flags().String("duration", "30m", common.T("Duração do laboratório", "Duración del laboratorio"))
+	labNewCmd.Flags().String("base-url", "https://raw.githubusercontent.com/badtuxx/girus-cli/main/labs", common.T("URL base usada na entrada do índice", "URL base usada en la entrada del índice"))
+	labNewCmd.Flags().Bool("force", false, common.T("Sobrescreve arquivos existentes", "Sobrescribe archivos existentes"))
+	labNewCmd.Flags().BoolP("interactive", "i", false, common.T("Pergunta os dados do laboratório interativamente", "Pregunta los datos del laboratorio de forma interactiva"))
+	labLintCmd.Flags().Bool("strict", false, common.T("Trata avisos como erros", "Trata las advertencias como errores"))
 }
 
 // containsCaseInsensitive verifica se uma string está contida em outra, ignorando maiúsculas/minúsculas
@@ -213,3 +702,53 @@ func containsCaseInsensitive(s interface{}, term string) bool {
 	}
 	return false
 }
+
+// listRepositoryLabs lista os laboratórios dos repositórios configurados. Os
+// repositórios que não responderam são informados na saída de erro e só há
+// falha quando nenhum deles respondeu.
+func listRepositoryLabs(ctx context.Context, client *repo.Client) (map[string][]repo.LabEntry, error) {
+	labs, err := client.ListLabs(ctx)
+	if err != nil && len(labs) == 0 {
+		return nil, err
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", yellow("AVISO:"), err)
+	}
+	return labs, nil
+}
+
+// repositoryLabs retorna os laboratórios dos repositórios aceitos por match
+// (todos, se match for nil), ordenados por repositório e ID
+func repositoryLabs(labs map[string][]repo.LabEntry, match func(repo.LabEntry) bool) []RepositoryLab {
+	entries := []RepositoryLab{}
+	for repoName, repoLabs := range labs {
+		for _, entry := range repoLabs {
+			if match == nil || match(entry) {
+				entries = append(entries, RepositoryLab{Repository: repoName, LabEntry: entry})
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Repository != entries[j].Repository {
+			return entries[i].Repository < entries[j].Repository
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+// withLabExitCode associa common.ExitVerificationFailed às falhas de
+// integridade, common.ExitLabNotFound aos erros de laboratório ou repositório
+// inexistente e common.ExitUsage às restrições de versão inválidas
+func withLabExitCode(err error) error {
+	if errors.Is(err, repo.ErrVerification) {
+		return common.WithExitCode(common.ExitVerificationFailed, err)
+	}
+	if errors.Is(err, repo.ErrLabNotFound) || errors.Is(err, repo.ErrRepositoryNotFound) {
+		return common.WithExitCode(common.ExitLabNotFound, err)
+	}
+	if errors.Is(err, repo.ErrInvalidConstraint) {
+		return common.WithExitCode(common.ExitUsage, err)
+	}
+	return err
+}
@@ -0,0 +1,418 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/lab"
+	"github.com/badtuxx/girus-cli/internal/repo"
+	"github.com/spf13/cobra"
+)
+
+var labInstalledCmd = &cobra.Command{
+	Use:   "installed",
+	Short: common.T("Lista os laboratórios instalados no cluster", "Lista los laboratorios instalados en el clúster"),
+	Long: common.T(`Lista os laboratórios instalados no namespace do GIRUS com a procedência registrada
+na instalação: repositório, versão, digest do manifesto e data. Quando o cluster não
+está acessível, exibe o estado local salvo em ~/.girus/installed-labs.json.`,
+		`Lista los laboratorios instalados en el namespace de GIRUS con la procedencia registrada
+en la instalación: repositorio, versión, digest del manifiesto y fecha. Cuando el clúster no
+está accesible, muestra el estado local guardado en ~/.girus/installed-labs.json.`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		doc := InstalledLabListDocument{
+			Document:  newDocument("InstalledLabList"),
+			Cluster:   k8s.CurrentClientOptions().ContextName(),
+			Namespace: k8s.Namespace(),
+			Items:     []InstalledLab{},
+		}
+
+		state, err := lab.LoadInstalledState()
+		if err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+
+		labs, err := clusterLabs(cmd.Context())
+		if err != nil {
+			// Sem cluster, o estado local mostra o que foi instalado pela CLI
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", yellow("AVISO:"), common.T("Cluster inacessível, exibindo o estado local", "Clúster inaccesible, mostrando el estado local"), err)
+			doc.Offline = true
+			for _, r := range state.Find(doc.Cluster, doc.Namespace) {
+				provenance := r.LabProvenance
+				doc.Items = append(doc.Items, InstalledLab{ConfigMap: r.ConfigMap, Name: r.LabID, Provenance: &provenance})
+			}
+		} else {
+			state.Sync(doc.Cluster, doc.Namespace, labs)
+			if err := state.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "%s %v\n", yellow("AVISO:"), err)
+			}
+			for _, l := range labs {
+				doc.Items = append(doc.Items, InstalledLab{ConfigMap: l.ConfigMap, Name: l.Name, Title: l.Title, Provenance: l.Provenance})
+			}
+		}
+
+		if machineOutput() {
+			return printDocument(os.Stdout, doc)
+		}
+
+		fmt.Println(headerColor(common.T("LABORATÓRIOS INSTALADOS", "LABORATORIOS INSTALADOS")))
+		fmt.Println(strings.Repeat("─", 80))
+
+		if len(doc.Items) == 0 {
+			fmt.Println(common.T("Nenhum laboratório instalado.", "Ningún laboratorio instalado."))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, cyan("NOME")+"\t"+cyan(common.T("VERSÃO", "VERSIÓN"))+"\t"+cyan(common.T("REPOSITÓRIO", "REPOSITORIO"))+"\t"+cyan(common.T("INSTALADO EM", "INSTALADO EN"))+"\t"+cyan("CONFIGMAP"))
+		for _, item := range doc.Items {
+			name := item.Name
+			if name == "" {
+				name = item.ConfigMap
+			}
+			version, repository, installedAt := "-", common.T("(local)", "(local)"), "-"
+			if p := item.Provenance; p != nil {
+				version, repository = p.Version, p.Repository
+				if !p.InstalledAt.IsZero() {
+					installedAt = p.InstalledAt.Local().Format("2006-01-02 15:04")
+				}
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", magenta(name), version, repository, installedAt, item.ConfigMap)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var labUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [laboratório[@versão]]",
+	Short: common.T("Atualiza laboratórios instalados dos repositórios", "Actualiza laboratorios instalados desde los repositorios"),
+	Long: common.T(`Compara a versão dos laboratórios instalados com o índice do repositório de origem
+e reinstala os que têm uma versão mais nova, reiniciando o backend ao final.
+Informe um laboratório (opcionalmente com uma restrição, como laboratório@^1.2)
+ou use --all para verificar todos os laboratórios instalados de qualquer
+repositório. Com --all, uma falha não interrompe os demais laboratórios e o
+backend é reiniciado se algum deles foi atualizado.`,
+		`Compara la versión de los laboratorios instalados con el índice del repositorio de origen
+y reinstala los que tienen una versión más nueva, reiniciando el backend al final.
+Informe un laboratorio (opcionalmente con una restricción, como laboratorio@^1.2)
+o use --all para verificar todos los laboratorios instalados desde cualquier
+repositorio. Con --all, un fallo no interrumpe los demás laboratorios y el
+backend se reinicia si alguno de ellos fue actualizado.`),
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		if all == (len(args) == 1) {
+			return common.WithExitCode(common.ExitUsage, fmt.Errorf("%s %s", red("ERRO:"), common.T("informe um laboratório ou use --all", "informe un laboratorio o use --all")))
+		}
+		var labName, constraint string
+		if len(args) == 1 {
+			labName, constraint = repo.ParseLabRef(args[0])
+		}
+
+		client, err := newKubernetesClient()
+		if err != nil {
+			return fmt.Errorf("%s %s: %v", red("ERRO:"), common.T("Erro ao conectar ao cluster", "Error al conectar al clúster"), err)
+		}
+		installed, err := client.InstalledLabs(cmd.Context(), k8s.Namespace())
+		if err != nil {
+			return fmt.Errorf("%s %v", red("ERRO:"), err)
+		}
+		targets := installed
+		if labName != "" {
+			if targets = matchInstalledLabs(installed, labName); len(targets) == 0 {
+				return common.WithExitCode(common.ExitLabNotFound, fmt.Errorf("%s %s: '%s'", red("ERRO:"), common.T("laboratório não instalado", "laboratorio no instalado"), labName))
+			}
+		}
+
+		repos, err := repo.NewClient("")
+		if err != nil {
+			return fmt.Errorf("%s %v", red("ERRO:"), err)
+		}
+
+		fmt.Println(headerColor(common.T("ATUALIZANDO LABORATÓRIOS", "ACTUALIZANDO LABORATORIOS")))
+		fmt.Println(strings.Repeat("─", 80))
+
+		upgraded := 0
+		var errs []error
+		seen := map[string]bool{}
+		for _, l := range targets {
+			p := l.Provenance
+			if p == nil {
+				if labName != "" {
+					return fmt.Errorf("%s %s %s", red("ERRO:"), magenta(l.ConfigMap), common.T("não foi instalado de um repositório; use girus lab install", "no fue instalado desde un repositorio; use girus lab install"))
+				}
+				continue
+			}
+			key := p.Repository + "/" + p.LabID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			entry, err := repos.FindLab(cmd.Context(), p.Repository, p.LabID, constraint)
+			if err != nil {
+				if labName != "" {
+					return withLabExitCode(fmt.Errorf("%s %w", red("ERRO:"), err))
+				}
+				fmt.Fprintf(os.Stderr, "%s %s %s: %v\n", yellow("AVISO:"), common.T("Não foi possível verificar", "No fue posible verificar"), magenta(p.LabID), err)
+				continue
+			}
+			// Sem restrição apenas versões mais novas são instaladas; com ela a
+			// versão selecionada é instalada mesmo que seja anterior
+			if entry.Version == p.Version || (constraint == "" && !IsNewerVersion(entry.Version, p.Version)) {
+				fmt.Printf(common.T("%s já está na versão %s\n", "%s ya está en la versión %s\n"), magenta(p.LabID), p.Version)
+				continue
+			}
+
+			fmt.Printf(common.T("Atualizando %s de %s para %s...\n", "Actualizando %s de %s a %s...\n"), magenta(p.LabID), p.Version, green(entry.Version))
+			data, provenance, err := prepareRepositoryLab(cmd.Context(), repos, entry)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			results, err := applyRepositoryLab(cmd.Context(), client, data, provenance)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			// A nova versão já foi aplicada, então o backend precisa ser
+			// reiniciado mesmo que a remoção dos ConfigMaps antigos falhe
+			upgraded++
+			if err := removeReplacedLabs(cmd.Context(), client, installed, p, results); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		if upgraded == 0 {
+			if len(errs) == 0 {
+				fmt.Println(common.T("Nenhum laboratório para atualizar.", "Ningún laboratorio para actualizar."))
+			}
+			return errors.Join(errs...)
+		}
+		fmt.Printf("%s %d %s\n", green(common.T("SUCESSO:", "ÉXITO:")), upgraded, common.T("laboratório(s) atualizado(s).", "laboratorio(s) actualizado(s)."))
+
+		// Os laboratórios que falharam não impedem o reinício, para que o
+		// backend passe a oferecer os que foram atualizados
+		errs = append(errs, restartBackend(cmd.Context(), client))
+		return errors.Join(errs...)
+	},
+}
+
+var labUninstallCmd = &cobra.Command{
+	Use:   "uninstall [laboratório]",
+	Short: common.T("Remove um laboratório instalado", "Elimina un laboratorio instalado"),
+	Long: common.T(`Remove do cluster o ConfigMap de um laboratório instalado e reinicia o backend
+para que ele deixe de ser oferecido. O laboratório pode ser informado pelo ID do
+repositório, pelo nome do template ou pelo nome do ConfigMap.`,
+		`Elimina del clúster el ConfigMap de un laboratorio instalado y reinicia el backend
+para que deje de ofrecerse. El laboratorio puede indicarse por el ID del
+repositorio, por el nombre de la plantilla o por el nombre del ConfigMap.`),
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newKubernetesClient()
+		if err != nil {
+			return fmt.Errorf("%s %s: %v", red("ERRO:"), common.T("Erro ao conectar ao cluster", "Error al conectar al clúster"), err)
+		}
+		installed, err := client.InstalledLabs(cmd.Context(), k8s.Namespace())
+		if err != nil {
+			return fmt.Errorf("%s %v", red("ERRO:"), err)
+		}
+		matches := matchInstalledLabs(installed, args[0])
+		if len(matches) == 0 {
+			return common.WithExitCode(common.ExitLabNotFound, fmt.Errorf("%s %s: '%s'", red("ERRO:"), common.T("laboratório não instalado", "laboratorio no instalado"), args[0]))
+		}
+
+		var configMaps []string
+		for _, m := range matches {
+			configMaps = append(configMaps, m.ConfigMap)
+		}
+
+		fmt.Println(headerColor(common.T("REMOVENDO LABORATÓRIO", "ELIMINANDO LABORATORIO")))
+		fmt.Println(strings.Repeat("─", 80))
+
+		ok, err := confirm(fmt.Sprintf(common.T("Remover o laboratório %s (%s)?", "¿Eliminar el laboratorio %s (%s)?"), args[0], strings.Join(configMaps, ", ")), false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println(common.T("Operação cancelada pelo usuário.", "Operación cancelada por el usuario."))
+			return nil
+		}
+
+		for _, name := range configMaps {
+			if err := client.DeleteLab(cmd.Context(), k8s.Namespace(), name); err != nil {
+				return fmt.Errorf("%s %v", red("ERRO:"), err)
+			}
+		}
+		forgetInstalledLabs(configMaps)
+
+		fmt.Printf("%s %s %s %s\n", green(common.T("SUCESSO:", "ÉXITO:")), common.T("Laboratório", "Laboratorio"), magenta(args[0]), common.T("removido com sucesso.", "eliminado con éxito."))
+
+		return restartBackend(cmd.Context(), client)
+	},
+}
+
+// clusterLabs lista os laboratórios instalados no namespace do GIRUS
+func clusterLabs(ctx context.Context) ([]k8s.InstalledLab, error) {
+	client, err := newKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.InstalledLabs(ctx, k8s.Namespace())
+}
+
+// matchInstalledLabs retorna os laboratórios instalados cujo ID de
+// repositório, nome do template ou nome do ConfigMap é igual a ref
+func matchInstalledLabs(labs []k8s.InstalledLab, ref string) []k8s.InstalledLab {
+	var matches []k8s.InstalledLab
+	for _, l := range labs {
+		if l.ConfigMap == ref || l.Name == ref || (l.Provenance != nil && l.Provenance.LabID == ref) {
+			matches = append(matches, l)
+		}
+	}
+	return matches
+}
+
+// downloadRepositoryLab baixa o manifesto do laboratório para o cache e
+// retorna o caminho do arquivo com a procedência a ser gravada no cluster
+func downloadRepositoryLab(ctx context.Context, repos *repo.Client, entry *repo.ResolvedLab) (string, *k8s.LabProvenance, error) {
+	labPath, err := repos.Download(ctx, entry)
+	if err != nil {
+		return "", nil, withLabExitCode(fmt.Errorf("%s %w", red("ERRO:"), err))
+	}
+	data, err := os.ReadFile(labPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s %v", red("ERRO:"), err)
+	}
+	return labPath, &k8s.LabProvenance{
+		Repository:  entry.Repository.Name,
+		LabID:       entry.ID,
+		Version:     entry.Version,
+		Digest:      repo.Digest(data),
+		Source:      entry.URL,
+		InstalledAt: time.Now().UTC(),
+	}, nil
+}
+
+// prepareRepositoryLab baixa e valida o manifesto do laboratório, retornando
+// os manifestos prontos para aplicar e a procedência da instalação
+func prepareRepositoryLab(ctx context.Context, repos *repo.Client, entry *repo.ResolvedLab) ([]byte, *k8s.LabProvenance, error) {
+	labPath, provenance, err := downloadRepositoryLab(ctx, repos, entry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Validar o manifesto baixado antes de aplicá-lo no cluster
+	manifests, err := lab.ParseFile(labPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s %s: %v", red("ERRO:"), common.T("Manifesto do laboratório inválido", "Manifiesto del laboratorio inválido"), err)
+	}
+	if len(lab.FindLabTemplates(manifests)) == 0 {
+		return nil, nil, fmt.Errorf("%s %s", red("ERRO:"), common.T("O arquivo baixado não contém um template de laboratório", "El archivo descargado no contiene una plantilla de laboratorio"))
+	}
+
+	// Laboratórios no formato nativo (kind: Lab) são aplicados como ConfigMap
+	var data []byte
+	if lab.HasNativeLabs(manifests) {
+		data, err = lab.RenderConfigMaps(manifests)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s %s: %v", red("ERRO:"), common.T("Erro ao converter o laboratório", "Error al convertir el laboratorio"), err)
+		}
+	} else if data, err = os.ReadFile(labPath); err != nil {
+		return nil, nil, fmt.Errorf("%s %v", red("ERRO:"), err)
+	}
+	return data, provenance, nil
+}
+
+// applyRepositoryLab aplica o laboratório com as anotações de procedência e
+// registra a instalação no estado local
+func applyRepositoryLab(ctx context.Context, client *k8s.KubernetesClient, data []byte, provenance *k8s.LabProvenance) ([]k8s.ApplyResult, error) {
+	results, err := client.ApplyLab(ctx, data, provenance)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %v", red("ERRO:"), common.T("Erro ao aplicar o laboratório", "Error al aplicar el laboratorio"), err)
+	}
+	// O estado local apenas espelha as anotações, então uma falha ao gravá-lo
+	// não desfaz a instalação
+	if err := lab.RecordInstalled(provenance, results); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", yellow("AVISO:"), err)
+	}
+	return results, nil
+}
+
+// removeReplacedLabs remove os ConfigMaps da versão anterior do laboratório
+// que não foram reaplicados pela nova versão
+func removeReplacedLabs(ctx context.Context, client *k8s.KubernetesClient, installed []k8s.InstalledLab, previous *k8s.LabProvenance, results []k8s.ApplyResult) error {
+	applied := map[string]bool{}
+	for _, r := range results {
+		if r.Kind == "ConfigMap" {
+			applied[r.Name] = true
+		}
+	}
+
+	var replaced []string
+	for _, l := range installed {
+		if l.Provenance == nil || applied[l.ConfigMap] || l.Provenance.Repository != previous.Repository || l.Provenance.LabID != previous.LabID {
+			continue
+		}
+		if err := client.DeleteLab(ctx, k8s.Namespace(), l.ConfigMap); err != nil {
+			return fmt.Errorf("%s %v", red("ERRO:"), err)
+		}
+		replaced = append(replaced, l.ConfigMap)
+	}
+	forgetInstalledLabs(replaced)
+	return nil
+}
+
+// forgetInstalledLabs remove do estado local os ConfigMaps apagados do cluster
+func forgetInstalledLabs(configMaps []string) {
+	if len(configMaps) == 0 {
+		return
+	}
+	state, err := lab.LoadInstalledState()
+	if err == nil {
+		cluster := k8s.CurrentClientOptions().ContextName()
+		for _, name := range configMaps {
+			state.Remove(cluster, k8s.Namespace(), name)
+		}
+		err = state.Save()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", yellow("AVISO:"), err)
+	}
+}
+
+// restartBackend reinicia o backend para que ele carregue os templates de
+// laboratório e aguarda o rollout terminar
+func restartBackend(ctx context.Context, client *k8s.KubernetesClient) error {
+	fmt.Println("\n" + headerColor(common.T("REINICIANDO BACKEND", "REINICIANDO BACKEND")))
+	fmt.Println(strings.Repeat("─", 80))
+	fmt.Println(common.T("Reiniciando o backend para aplicar as mudanças...", "Reiniciando el backend para aplicar los cambios..."))
+
+	if err := client.RestartDeployment(ctx, k8s.Namespace(), "girus-backend"); err != nil {
+		return common.WithExitCode(common.ExitBackendUnhealthy, fmt.Errorf("%s %s: %w", red("ERRO:"), common.T("Erro ao reiniciar o backend", "Error al reiniciar el backend"), err))
+	}
+
+	// Aguarda o reinício completar
+	fmt.Println(common.T("Aguardando o reinício do backend completar...", "Esperando a que el backend reinicie por completo..."))
+	ctx, cancel := context.WithTimeout(ctx, k8s.BackendRolloutTimeout)
+	defer cancel()
+	if err := client.WaitForRollout(ctx, k8s.Namespace(), "girus-backend"); err != nil {
+		return common.WithExitCode(common.ExitBackendUnhealthy, fmt.Errorf("%s %s: %w", red("ERRO:"), common.T("Erro ao aguardar reinício do backend", "Error al esperar el reinicio del backend"), err))
+	}
+	fmt.Printf("%s Backend %s\n", green(common.T("SUCESSO:", "ÉXITO:")), common.T("reiniciado com sucesso.", "reiniciado con éxito."))
+	return nil
+}
+
+func init() {
+	labCmd.AddCommand(labInstalledCmd, labUpgradeCmd, labUninstallCmd)
+
+	labUpgradeCmd.Flags().Bool("all", false, common.T("Verifica todos os laboratórios instalados de qualquer repositório", "Verifica todos los laboratorios instalados desde cualquier repositorio"))
+}
@@ -0,0 +1,500 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/executil"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/k8s/k8sfake"
+	"github.com/badtuxx/girus-cli/internal/lab"
+	"github.com/badtuxx/girus-cli/internal/repo"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+)
+
+// readyBackend retorna o deployment do backend com o rollout concluído
+func readyBackend() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "girus-backend", Namespace: "girus", Generation: 1},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(1))},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			Replicas:           1,
+			UpdatedReplicas:    1,
+			AvailableReplicas:  1,
+		},
+	}
+}
+
+// serveLabRepository publica um repositório com o laboratório
+// docker-fundamentos nas versões informadas (1.0.0 por padrão) e o registra no
+// HOME do teste. O índice traz o digest do arquivo no momento da chamada, mas o
+// manifesto é relido a cada requisição.
+func serveLabRepository(t *testing.T, labFile string, versions ...string) {
+	t.Helper()
+	if len(versions) == 0 {
+		versions = []string{"1.0.0"}
+	}
+	lab, err := os.ReadFile(labFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := repo.Digest(lab)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			fmt.Fprint(w, "apiVersion: v1\nlabs:\n")
+			for _, v := range versions {
+				fmt.Fprintf(w, "- id: docker-fundamentos\n  title: Introdução ao Docker\n  version: %s\n  url: %s/lab.yaml\n  digest: %s\n", v, server.URL, digest)
+			}
+		case "/lab.yaml":
+			http.ServeFile(w, r, labFile)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	repos, err := json.Marshal(map[string]repo.Repository{
+		"teste": {Name: "teste", URL: server.URL, Description: "Repositório de teste", Version: "v1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(os.Getenv("HOME"), ".girus")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "repositories.json"), repos, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLabInstall(t *testing.T) {
+	client, clientset, dynamicClient := k8sfake.NewClient(readyBackend())
+	env := newTestEnv(t, client)
+	serveLabRepository(t, filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+
+	out, err := env.run(t, "lab", "install", "teste", "docker-fundamentos")
+	if err != nil {
+		t.Fatalf("lab install: %v\n%s", err, out)
+	}
+
+	configmaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	if _, err := dynamicClient.Resource(configmaps).Namespace("girus").Get(context.Background(), "docker-fundamentos-lab", metav1.GetOptions{}); err != nil {
+		t.Errorf("laboratório não aplicado: %v", err)
+	}
+	backend, err := clientset.AppsV1().Deployments("girus").Get(context.Background(), "girus-backend", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := backend.Spec.Template.Annotations[k8s.RestartedAtAnnotation]; !ok {
+		t.Error("backend não reiniciado")
+	}
+}
+
+func TestLabInstallNotFound(t *testing.T) {
+	client, _, dynamicClient := k8sfake.NewClient(readyBackend())
+	env := newTestEnv(t, client)
+	serveLabRepository(t, filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+
+	if _, err := env.run(t, "lab", "install", "teste", "inexistente"); err == nil {
+		t.Fatal("esperado erro para laboratório inexistente")
+	}
+	if len(dynamicClient.Actions()) != 0 {
+		t.Errorf("manifestos aplicados: %v", dynamicClient.Actions())
+	}
+}
+
+func TestLabInstallInvalidManifest(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "lab.yaml")
+	if err := os.WriteFile(invalid, []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: sem-template\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	client, _, dynamicClient := k8sfake.NewClient(readyBackend())
+	env := newTestEnv(t, client)
+	serveLabRepository(t, invalid)
+
+	_, err := env.run(t, "lab", "install", "teste", "docker-fundamentos")
+	if err == nil || !strings.Contains(err.Error(), "template de laboratório") {
+		t.Fatalf("erro = %v, esperado manifesto sem template", err)
+	}
+	if len(dynamicClient.Actions()) != 0 {
+		t.Errorf("manifestos aplicados: %v", dynamicClient.Actions())
+	}
+}
+
+func TestLabInstallDigestMismatch(t *testing.T) {
+	labFile := filepath.Join(t.TempDir(), "lab.yaml")
+	original, err := os.ReadFile(filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(labFile, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+	client, _, dynamicClient := k8sfake.NewClient(readyBackend())
+	env := newTestEnv(t, client)
+	serveLabRepository(t, labFile)
+
+	// O manifesto publicado muda depois do índice
+	if err := os.WriteFile(labFile, append(original, []byte("# alterado\n")...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = env.run(t, "lab", "install", "teste", "docker-fundamentos")
+	if common.ExitCode(err) != common.ExitVerificationFailed {
+		t.Fatalf("código = %d (%v), esperado %d", common.ExitCode(err), err, common.ExitVerificationFailed)
+	}
+	if len(dynamicClient.Actions()) != 0 {
+		t.Errorf("manifestos aplicados: %v", dynamicClient.Actions())
+	}
+
+	if out, err := env.run(t, "lab", "install", "teste", "docker-fundamentos", "--insecure-skip-verify"); err != nil {
+		t.Fatalf("lab install --insecure-skip-verify: %v\n%s", err, out)
+	}
+	if !strings.Contains(env.stderr, "--insecure-skip-verify") {
+		t.Errorf("aviso da verificação desativada ausente: %q", env.stderr)
+	}
+}
+
+func TestLabInstallBackendMissing(t *testing.T) {
+	client, _, _ := k8sfake.NewClient()
+	env := newTestEnv(t, client)
+	serveLabRepository(t, filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+
+	_, err := env.run(t, "lab", "install", "teste", "docker-fundamentos")
+	if err == nil || !strings.Contains(err.Error(), "Erro ao reiniciar o backend") {
+		t.Fatalf("erro = %v, esperado falha ao reiniciar o backend", err)
+	}
+}
+
+func TestLabInstallClusterUnreachable(t *testing.T) {
+	env := newTestEnv(t, nil)
+	newKubernetesClient = func() (*k8s.KubernetesClient, error) {
+		return nil, fmt.Errorf("dial tcp 127.0.0.1:6443: connection refused")
+	}
+	serveLabRepository(t, filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+
+	_, err := env.run(t, "lab", "install", "teste", "docker-fundamentos")
+	if err == nil || !strings.Contains(err.Error(), "Erro ao conectar ao cluster") {
+		t.Fatalf("erro = %v, esperado cluster inacessível", err)
+	}
+}
+
+func TestLabSourcesShareRepositories(t *testing.T) {
+	env := newTestEnv(t, nil)
+	serveLabRepository(t, filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+
+	for _, args := range [][]string{{"list", "repo-labs"}, {"lab", "list"}} {
+		out, err := env.run(t, args...)
+		if err != nil {
+			t.Fatalf("%v: %v\n%s", args, err, out)
+		}
+		if !strings.Contains(out, "docker-fundamentos") || !strings.Contains(out, "teste") {
+			t.Errorf("%v: laboratório do repositório configurado ausente:\n%s", args, out)
+		}
+	}
+}
+
+func TestLabListUnreachableRepository(t *testing.T) {
+	env := newTestEnv(t, nil)
+	serveLabRepository(t, filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+
+	// Acrescenta um repositório que não responde ao lado do "teste"
+	config := filepath.Join(os.Getenv("HOME"), ".girus", "repositories.json")
+	data, err := os.ReadFile(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos := map[string]repo.Repository{}
+	if err := json.Unmarshal(data, &repos); err != nil {
+		t.Fatal(err)
+	}
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+	repos["fora"] = repo.Repository{Name: "fora", URL: dead.URL, Version: "v1"}
+	if data, err = json.Marshal(repos); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"list", "repo-labs"}, {"lab", "list"}, {"lab", "search", "docker"}} {
+		out, err := env.run(t, args...)
+		if err != nil {
+			t.Fatalf("%v: %v\n%s", args, err, out)
+		}
+		if !strings.Contains(out, "docker-fundamentos") {
+			t.Errorf("%v: laboratório do repositório acessível ausente:\n%s", args, out)
+		}
+		if !strings.Contains(env.stderr, "AVISO:") || !strings.Contains(env.stderr, "fora") {
+			t.Errorf("%v: sem aviso sobre o repositório inacessível: %q", args, env.stderr)
+		}
+	}
+
+	// Sem nenhum repositório acessível a listagem falha
+	delete(repos, "teste")
+	if data, err = json.Marshal(repos); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.run(t, "lab", "list"); err == nil {
+		t.Error("esperado erro sem repositórios acessíveis")
+	}
+}
+
+func TestLabVersions(t *testing.T) {
+	env := newTestEnv(t, nil)
+	serveLabRepository(t, filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+
+	out, err := env.run(t, "lab", "versions", "docker-fundamentos", "-o", "json")
+	if err != nil {
+		t.Fatalf("lab versions: %v\n%s", err, out)
+	}
+	var doc LabVersionListDocument
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("saída inválida: %v\n%s", err, out)
+	}
+	if doc.Lab != "docker-fundamentos" || len(doc.Items) != 1 || !doc.Items[0].Default || doc.Items[0].Repository != "teste" || doc.Items[0].Version != "1.0.0" {
+		t.Errorf("documento = %+v", doc)
+	}
+}
+
+func TestLabInstalledUpgradeUninstall(t *testing.T) {
+	client, _, _ := k8sfake.NewClient(readyBackend())
+	env := newTestEnv(t, client)
+	labFile := filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml")
+	serveLabRepository(t, labFile)
+
+	if out, err := env.run(t, "lab", "install", "teste", "docker-fundamentos"); err != nil {
+		t.Fatalf("lab install: %v\n%s", err, out)
+	}
+
+	installed := func() InstalledLabListDocument {
+		t.Helper()
+		out, err := env.run(t, "lab", "installed", "-o", "json")
+		if err != nil {
+			t.Fatalf("lab installed: %v\n%s", err, out)
+		}
+		var doc InstalledLabListDocument
+		if err := json.Unmarshal([]byte(out), &doc); err != nil {
+			t.Fatalf("saída inválida: %v\n%s", err, out)
+		}
+		return doc
+	}
+
+	doc := installed()
+	if len(doc.Items) != 1 || doc.Items[0].ConfigMap != "docker-fundamentos-lab" || doc.Items[0].Provenance == nil {
+		t.Fatalf("documento = %+v", doc)
+	}
+	p := doc.Items[0].Provenance
+	if p.Repository != "teste" || p.LabID != "docker-fundamentos" || p.Version != "1.0.0" || !strings.HasPrefix(p.Digest, "sha256:") || p.InstalledAt.IsZero() {
+		t.Errorf("procedência = %+v", p)
+	}
+
+	// Sem cluster, o estado local gravado na instalação é exibido
+	env.client = nil
+	if doc := installed(); !doc.Offline || len(doc.Items) != 1 || doc.Items[0].Provenance.Version != "1.0.0" {
+		t.Errorf("documento offline = %+v", doc)
+	}
+	env.client = client
+
+	if _, err := env.run(t, "lab", "upgrade"); common.ExitCode(err) != common.ExitUsage {
+		t.Errorf("upgrade sem laboratório: código %d, esperado %d", common.ExitCode(err), common.ExitUsage)
+	}
+	out, err := env.run(t, "lab", "upgrade", "--all")
+	if err != nil || !strings.Contains(out, "já está na versão 1.0.0") {
+		t.Fatalf("upgrade sem versão nova: %v\n%s", err, out)
+	}
+
+	serveLabRepository(t, labFile, "1.0.0", "1.1.0")
+	if out, err := env.run(t, "lab", "upgrade", "docker-fundamentos"); err != nil {
+		t.Fatalf("lab upgrade: %v\n%s", err, out)
+	}
+	if doc := installed(); len(doc.Items) != 1 || doc.Items[0].Provenance.Version != "1.1.0" {
+		t.Errorf("documento após upgrade = %+v", doc)
+	}
+
+	if _, err := env.run(t, "lab", "uninstall", "inexistente", "--yes"); common.ExitCode(err) != common.ExitLabNotFound {
+		t.Errorf("uninstall inexistente: código %d, esperado %d", common.ExitCode(err), common.ExitLabNotFound)
+	}
+	if out, err := env.run(t, "lab", "uninstall", "docker-fundamentos", "--yes"); err != nil {
+		t.Fatalf("lab uninstall: %v\n%s", err, out)
+	}
+	if doc := installed(); len(doc.Items) != 0 {
+		t.Errorf("documento após uninstall = %+v", doc)
+	}
+	state, err := lab.LoadInstalledState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Labs) != 0 {
+		t.Errorf("estado local após uninstall = %+v", state.Labs)
+	}
+}
+
+func TestLabConvertOutputFile(t *testing.T) {
+	env := newTestEnv(t, nil)
+	out := filepath.Join(t.TempDir(), "lab.yaml")
+
+	if _, err := env.run(t, "lab", "convert", "../labs/docker_fundamentos/lab.yaml", "-f", out); err != nil {
+		t.Fatalf("lab convert -f: %v", err)
+	}
+	manifests, err := lab.ParseFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if templates := lab.FindLabTemplates(manifests); !lab.HasNativeLabs(templates) {
+		t.Error("esperado o laboratório no formato nativo")
+	}
+
+	// -o é o formato da saída; um caminho de arquivo indica a flag certa
+	_, err = env.run(t, "lab", "convert", "../labs/docker_fundamentos/lab.yaml", "-o", "out.yaml")
+	if err == nil || !strings.Contains(err.Error(), "--output-file/-f") {
+		t.Errorf("erro = %v, esperado a indicação de --output-file/-f", err)
+	}
+}
+
+func TestLabTest(t *testing.T) {
+	env := newTestEnv(t, nil)
+	file := filepath.Join(t.TempDir(), "lab.yaml")
+	manifest := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: teste-lab
+  labels:
+    app: girus-lab-template
+data:
+  lab.yaml: |
+    name: teste
+    title: Teste
+    description: Laboratório de teste
+    duration: 5m
+    image: alpine:3.20
+    tasks:
+      - name: Criar arquivo
+        description: Crie o arquivo
+        steps:
+          - "` + "`touch a.txt`" + `"
+        validation:
+          - command: test -f a.txt && echo ok
+            expectedOutput: ok
+            errorMessage: arquivo não criado
+`
+	if err := os.WriteFile(file, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// O nome do container é aleatório, então a saída vale para todo exec
+	env.runner.On("podman exec", executil.Response{Output: "ok\n"})
+
+	out, err := env.run(t, "lab", "test", file, "-e", "podman")
+	if err != nil || !strings.Contains(out, "Todas as validações passaram") {
+		t.Fatalf("lab test: %v\n%s", err, out)
+	}
+	commands := strings.Join(env.runner.Commands(), "\n")
+	for _, want := range []string{"podman run -d --name girus-test-teste-", "bash -c touch a.txt", "bash -c test -f a.txt && echo ok", "podman rm -f girus-test-teste-"} {
+		if !strings.Contains(commands, want) {
+			t.Errorf("comando %q não executado:\n%s", want, commands)
+		}
+	}
+
+	env.runner.On("podman exec", executil.Response{Output: "erro\n"})
+	if out, err := env.run(t, "lab", "test", file, "-e", "podman"); err == nil {
+		t.Errorf("esperado falha com a validação não satisfeita:\n%s", out)
+	}
+
+	env.runner.On("podman run", executil.Response{Output: "imagem não encontrada", ExitCode: 125})
+	if _, err := env.run(t, "lab", "test", file, "-e", "podman"); err == nil || !strings.Contains(err.Error(), "imagem não encontrada") {
+		t.Errorf("erro = %v, esperado a falha ao iniciar a imagem", err)
+	}
+}
+
+func TestLabUpgradeAllContinuesAfterFailure(t *testing.T) {
+	client, clientset, _ := k8sfake.NewClient(readyBackend())
+	env := newTestEnv(t, client)
+	docker, err := os.ReadFile(filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	linux, err := os.ReadFile(filepath.Join("..", "internal", "repo", "example", "linux-basics", "lab.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// O linux-basics 1.1.0 publicado está corrompido
+	version := "1.0.0"
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			fmt.Fprintf(w, "apiVersion: v1\nlabs:\n- id: docker-fundamentos\n  title: Introdução ao Docker\n  version: %s\n  url: %s/docker.yaml\n", version, server.URL)
+			fmt.Fprintf(w, "- id: linux-basics\n  title: Linux Básico\n  version: %s\n  url: %s/linux-%s.yaml\n", version, server.URL, version)
+		case "/docker.yaml":
+			w.Write(docker)
+		case "/linux-1.0.0.yaml":
+			w.Write(linux)
+		default:
+			fmt.Fprint(w, "kind: [")
+		}
+	}))
+	t.Cleanup(server.Close)
+	repos, err := json.Marshal(map[string]repo.Repository{"teste": {Name: "teste", URL: server.URL, Version: "v1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(os.Getenv("HOME"), ".girus")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "repositories.json"), repos, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"linux-basics", "docker-fundamentos"} {
+		if out, err := env.run(t, "lab", "install", "teste", id); err != nil {
+			t.Fatalf("lab install %s: %v\n%s", id, err, out)
+		}
+	}
+	restarts := func() int {
+		n := 0
+		for _, action := range clientset.Actions() {
+			if action.GetVerb() == "patch" && action.GetResource().Resource == "deployments" {
+				n++
+			}
+		}
+		return n
+	}
+	before := restarts()
+
+	// O índice novo só é lido depois que o cache expira
+	version = "1.1.0"
+	if err := os.RemoveAll(filepath.Join(dir, "cache")); err != nil {
+		t.Fatal(err)
+	}
+	out, err := env.run(t, "lab", "upgrade", "--all")
+	if err == nil || !strings.Contains(err.Error(), "Manifesto do laboratório inválido") {
+		t.Fatalf("erro = %v, esperado a falha do linux-basics\n%s", err, out)
+	}
+	if !strings.Contains(out, "1 laboratório(s) atualizado(s)") {
+		t.Errorf("o docker-fundamentos não foi atualizado:\n%s", out)
+	}
+	if restarts() == before {
+		t.Error("o backend não foi reiniciado após a atualização parcial")
+	}
+}
@@ -4,10 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/executil"
+	"github.com/badtuxx/girus-cli/internal/k8s"
 	"github.com/badtuxx/girus-cli/internal/repo"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -27,76 +28,93 @@ var listCmd = &cobra.Command{
 
 var listClustersCmd = &cobra.Command{
 	Use:   "clusters",
-	Short: common.T("Lista os clusters Kind disponíveis", "Lista los clusters Kind disponibles"),
-	Long:  common.T("Lista todos os clusters Kind disponíveis no sistema, destacando os que executam o Girus.", "Lista todos los clusters Kind disponibles en el sistema, destacando los que ejecutan Girus."),
-	Run: func(cmd *cobra.Command, args []string) {
-
-		fmt.Println(headerColor(common.T("CLUSTERS KIND", "CLUSTERS KIND")))
-		fmt.Println(strings.Repeat("─", 80))
-		fmt.Println(common.T("Obtendo lista de clusters Kind...", "Obteniendo lista de clusters Kind..."))
-
-		getCmd := exec.Command("kind", "get", "clusters")
-		output, err := getCmd.Output()
+	Short: common.T("Lista os clusters disponíveis", "Lista los clusters disponibles"),
+	Long: common.T("Lista todos os clusters do provider (kind, k3d, minikube ou os contextos do kubeconfig no provider byo), destacando os que executam o Girus. O contexto atual do kubectl não é alterado.",
+		"Lista todos los clusters del proveedor (kind, k3d, minikube o los contextos del kubeconfig en el proveedor byo), destacando los que ejecutan Girus. El contexto actual de kubectl no se modifica."),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		provider, err := clusterProvider()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("ERRO:"), common.T("Erro ao obter clusters Kind", "Error al obtener clusters Kind"), err)
-			os.Exit(1)
+			return fmt.Errorf("%s %v", red("ERRO:"), err)
 		}
+		table := !machineOutput()
+		providerLabel := strings.ToUpper(provider.Name())
 
-		clusters := strings.Split(strings.TrimSpace(string(output)), "\n")
-
-		if len(clusters) == 0 || (len(clusters) == 1 && clusters[0] == "") {
-			fmt.Println(common.T("Nenhum cluster Kind encontrado.", "Ningún cluster Kind encontrado."))
-			return
+		if table {
+			fmt.Println(headerColor(common.T("CLUSTERS ", "CLUSTERS ") + providerLabel))
+			fmt.Println(strings.Repeat("─", 80))
+			fmt.Println(common.T("Obtendo lista de clusters...", "Obteniendo lista de clusters..."))
 		}
 
-		fmt.Println("\n" + headerColor(common.T("Clusters Kind disponíveis:", "Clusters Kind disponibles:")))
+		clusters, err := provider.List(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", red("ERRO:"), common.T("Erro ao obter clusters", "Error al obtener clusters"), err)
+		}
 
+		doc := ClusterListDocument{Document: newDocument("ClusterList"), Provider: provider.Name(), Items: []ClusterInfo{}}
 		for _, cluster := range clusters {
-			if cluster == "" {
-				continue
+			info := ClusterInfo{Name: cluster, Context: provider.KubeContext(cluster)}
+
+			// Verificar se é um cluster Girus pelo namespace girus, consultando o
+			// contexto do cluster sem alterar o contexto atual
+			clusterFlags := k8s.ClientOptions{Kubeconfig: k8s.CurrentClientOptions().Kubeconfig, Context: info.Context}.Flags()
+			checkOutput, _ := runner.Output(cmd.Context(), "kubectl", append(clusterFlags, "get", "namespace", k8s.Namespace(), "--no-headers", "--ignore-not-found")...)
+			info.Girus = strings.Contains(string(checkOutput), k8s.Namespace())
+
+			if info.Girus {
+				// Verificar o status dos pods no namespace girus
+				podsOutput, _ := runner.Output(cmd.Context(), "kubectl", append(clusterFlags, "get", "pods", "-n", k8s.Namespace(), "-o", "custom-columns=NAME:.metadata.name,STATUS:.status.phase,READY:.status.containerStatuses[0].ready", "--no-headers")...)
+				for _, podLine := range executil.Lines(podsOutput) {
+					fields := strings.Fields(podLine)
+					if len(fields) < 3 {
+						continue
+					}
+					ready := "False"
+					if fields[2] == "true" {
+						ready = "True"
+					}
+					info.Pods = append(info.Pods, PodInfo{Name: fields[0], Status: fields[1], Ready: ready})
+				}
 			}
+			doc.Items = append(doc.Items, info)
+		}
 
-			// Verificar se é um cluster Girus verificando o namespace girus
-			// Mudar contexto do kubectl para o cluster atual
-			contextCmd := exec.Command("kubectl", "config", "use-context", fmt.Sprintf("kind-%s", cluster))
-			contextCmd.Run() // Ignoramos erros aqui, pois vamos verificar no próximo comando
+		if !table {
+			return printDocument(os.Stdout, doc)
+		}
 
-			// Verificar se o namespace girus existe
-			checkCmd := exec.Command("kubectl", "get", "namespace", "girus", "--no-headers", "--ignore-not-found")
-			checkOutput, _ := checkCmd.Output()
+		if len(doc.Items) == 0 {
+			fmt.Println(common.T("Nenhum cluster encontrado.", "Ningún cluster encontrado."))
+			return nil
+		}
 
-			isGirus := strings.Contains(string(checkOutput), "girus")
+		fmt.Println("\n" + headerColor(common.T("Clusters disponíveis:", "Clusters disponibles:")))
 
-			if isGirus {
-				fmt.Printf("%s Cluster %s (%s)\n", green(common.T("ATIVO", "ACTIVO")), magenta(cluster), "cluster Girus")
+		for _, info := range doc.Items {
+			if !info.Girus {
+				fmt.Printf("%s Cluster %s (%s)\n", red(common.T("INATIVO", "INACTIVO")), magenta(info.Name), "cluster não-Girus")
+				continue
+			}
 
-				// Verificar o status dos pods no namespace girus
-				podsCmd := exec.Command("kubectl", "get", "pods", "-n", "girus", "-o", "custom-columns=NAME:.metadata.name,STATUS:.status.phase,READY:.status.containerStatuses[0].ready", "--no-headers")
-				podsOutput, _ := podsCmd.Output()
-
-				if len(podsOutput) > 0 {
-					fmt.Println("   " + cyan(common.T("Pods:", "Pods:")))
-					podLines := strings.Split(strings.TrimSpace(string(podsOutput)), "\n")
-					for _, podLine := range podLines {
-						if podLine != "" {
-							fmt.Printf("   └─ %s\n", podLine)
-						}
-					}
+			fmt.Printf("%s Cluster %s (%s)\n", green(common.T("ATIVO", "ACTIVO")), magenta(info.Name), "cluster Girus")
+			if len(info.Pods) > 0 {
+				fmt.Println("   " + cyan(common.T("Pods:", "Pods:")))
+				for _, pod := range info.Pods {
+					fmt.Printf("   └─ %-40s %-10s %s\n", pod.Name, pod.Status, pod.Ready)
 				}
-			} else {
-				fmt.Printf("%s Cluster %s (%s)\n", red(common.T("INATIVO", "INACTIVO")), magenta(cluster), "cluster não-Girus")
 			}
 		}
+		return nil
 	},
 }
 
 // Para compatibilidade, mantemos o comando singular, mas ele chamará o plural
 var listClusterCmd = &cobra.Command{
 	Use:    "cluster",
-	Short:  common.T("Lista os clusters Kind disponíveis (alias para 'clusters')", "Lista los clusters Kind disponibles (alias de 'clusters')"),
+	Short:  common.T("Lista os clusters disponíveis (alias para 'clusters')", "Lista los clusters disponibles (alias de 'clusters')"),
 	Hidden: true,
-	Run: func(cmd *cobra.Command, args []string) {
-		listClustersCmd.Run(cmd, args)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listClustersCmd.RunE(cmd, args)
 	},
 }
 
@@ -113,64 +131,67 @@ type LabListResponse struct {
 }
 
 var listLabsCmd = &cobra.Command{
-	Use:   "labs",
-	Short: common.T("Lista os laboratórios disponíveis no Girus", "Lista los laboratorios disponibles en Girus"),
-	Long:  common.T("Lista todos os laboratórios disponíveis no cluster Girus ativo.", "Lista todos los laboratorios disponibles en el cluster Girus activo."),
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:          "labs",
+	Short:        common.T("Lista os laboratórios disponíveis no Girus", "Lista los laboratorios disponibles en Girus"),
+	Long:         common.T("Lista todos os laboratórios disponíveis no cluster Girus ativo.", "Lista todos los laboratorios disponibles en el cluster Girus activo."),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Criar formatadores de cores
-		red := color.New(color.FgRed).SprintFunc()
 		cyan := color.New(color.FgCyan).SprintFunc()
 		magenta := color.New(color.FgMagenta).SprintFunc()
 		yellow := color.New(color.FgYellow).SprintFunc()
 		headerColor := color.New(color.FgCyan, color.Bold).SprintFunc()
 		bold := color.New(color.Bold).SprintFunc()
 
-		fmt.Println(headerColor(common.T("LABORATÓRIOS DISPONÍVEIS", "LABORATORIOS DISPONIBLES")))
-		fmt.Println(strings.Repeat("─", 80))
-		fmt.Println(common.T("Obtendo lista de laboratórios do Girus...", "Obteniendo lista de laboratorios de Girus..."))
+		if !machineOutput() {
+			fmt.Println(headerColor(common.T("LABORATÓRIOS DISPONÍVEIS", "LABORATORIOS DISPONIBLES")))
+			fmt.Println(strings.Repeat("─", 80))
+			fmt.Println(common.T("Obtendo lista de laboratórios do Girus...", "Obteniendo lista de laboratorios de Girus..."))
+		}
 
 		// Verificar se há um cluster Girus ativo
-		checkCmd := exec.Command("kubectl", "get", "namespace", "girus", "--no-headers", "--ignore-not-found")
-		checkOutput, err := checkCmd.Output()
-		if err != nil || !strings.Contains(string(checkOutput), "girus") {
-			fmt.Fprintf(os.Stderr, "%s %s\n", red("ERRO:"), common.T("Nenhum cluster Girus ativo encontrado", "Ningún cluster Girus activo encontrado"))
-			fmt.Println(common.T("Use 'girus create cluster' para criar um cluster ou 'girus list clusters' para ver os clusters disponíveis.", "Use 'girus create cluster' para crear un cluster o 'girus list clusters' para ver los clusters disponibles."))
-			os.Exit(1)
+		checkOutput, err := runner.Output(cmd.Context(), "kubectl", k8s.KubectlArgs("get", "namespace", k8s.Namespace(), "--no-headers", "--ignore-not-found")...)
+		if err != nil || !strings.Contains(string(checkOutput), k8s.Namespace()) {
+			fmt.Fprintln(os.Stderr, common.T("Use 'girus create cluster' para criar um cluster ou 'girus list clusters' para ver os clusters disponíveis.", "Use 'girus create cluster' para crear un cluster o 'girus list clusters' para ver los clusters disponibles."))
+			return common.WithExitCode(common.ExitClusterNotFound, fmt.Errorf("%s %s", red("ERRO:"), common.T("Nenhum cluster Girus ativo encontrado", "Ningún cluster Girus activo encontrado")))
 		}
 
 		// Verificar o pod do backend
-		backendCmd := exec.Command("kubectl", "get", "pods", "-n", "girus", "-l", "app=girus-backend", "-o", "jsonpath={.items[0].status.phase}")
-		backendOutput, err := backendCmd.Output()
+		backendOutput, err := runner.Output(cmd.Context(), "kubectl", k8s.KubectlArgs("get", "pods", "-n", k8s.Namespace(), "-l", "app=girus-backend", "-o", "jsonpath={.items[0].status.phase}")...)
 		if err != nil || string(backendOutput) != "Running" {
-			fmt.Fprintf(os.Stderr, "%s %s\n", red("ERRO:"), common.T("O backend do Girus não está em execução", "El backend de Girus no está en ejecución"))
-			fmt.Println(common.T("Verifique o status dos pods com 'kubectl get pods -n girus'", "Verifique el estado de los pods con 'kubectl get pods -n girus'"))
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, common.T("Verifique o status dos pods com 'kubectl get pods -n girus'", "Verifique el estado de los pods con 'kubectl get pods -n girus'"))
+			return common.WithExitCode(common.ExitBackendUnhealthy, fmt.Errorf("%s %s", red("ERRO:"), common.T("O backend do Girus não está em execução", "El backend de Girus no está en ejecución")))
 		}
 
 		// Fazer uma solicitação para a API para obter a lista de laboratórios
-		apiCmd := exec.Command("kubectl", "exec", "-n", "girus", "deploy/girus-backend", "--",
-			"wget", "-q", "-O-", "http://localhost:8080/api/v1/templates")
-		apiOutput, err := apiCmd.Output()
+		apiOutput, err := runner.Output(cmd.Context(), "kubectl", k8s.KubectlArgs("exec", "-n", k8s.Namespace(), "deploy/girus-backend", "--",
+			"wget", "-q", "-O-", "http://localhost:8080/api/v1/templates")...)
 
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("ERRO:"), common.T("Erro ao obter a lista de laboratórios", "Error al obtener la lista de laboratorios"), err)
-			fmt.Println(common.T("Verifique se o serviço do backend está respondendo.", "Verifique si el servicio del backend está respondiendo."))
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, common.T("Verifique se o serviço do backend está respondendo.", "Verifique si el servicio del backend está respondiendo."))
+			return common.WithExitCode(common.ExitBackendUnhealthy, fmt.Errorf("%s %s: %w", red("ERRO:"), common.T("Erro ao obter a lista de laboratórios", "Error al obtener la lista de laboratorios"), err))
 		}
 
 		// Processar a resposta JSON
 		var response LabListResponse
 		if err := json.Unmarshal(apiOutput, &response); err != nil {
-			fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("ERRO:"), common.T("Erro ao processar a resposta", "Error al procesar la respuesta"), err)
-			fmt.Println(common.T("Resposta da API:", "Respuesta de la API:"))
-			fmt.Println(string(apiOutput))
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, common.T("Resposta da API:", "Respuesta de la API:"))
+			fmt.Fprintln(os.Stderr, string(apiOutput))
+			return common.WithExitCode(common.ExitBackendUnhealthy, fmt.Errorf("%s %s: %w", red("ERRO:"), common.T("Erro ao processar a resposta", "Error al procesar la respuesta"), err))
+		}
+
+		if machineOutput() {
+			doc := LabTemplateListDocument{Document: newDocument("LabTemplateList"), Items: response.Templates}
+			if doc.Items == nil {
+				doc.Items = []LabTemplate{}
+			}
+			return printDocument(os.Stdout, doc)
 		}
 
 		// Exibir a lista de laboratórios
 		if len(response.Templates) == 0 {
 			fmt.Printf("\n%s %s\n", yellow("AVISO:"), common.T("Nenhum laboratório disponível.", "Ningún laboratorio disponible."))
-			return
+			return nil
 		}
 
 		fmt.Println("\n" + headerColor(common.T("Laboratórios disponíveis:", "Laboratorios disponibles:")))
@@ -190,17 +211,18 @@ var listLabsCmd = &cobra.Command{
 
 		fmt.Println("\n" + common.T("Para criar um laboratório, use:", "Para crear un laboratorio, use:"))
 		fmt.Println("  " + magenta("girus create lab <lab-id>"))
+		return nil
 	},
 }
 
 // Comando para listar laboratórios do repositório remoto
 var listRepoLabsCmd = &cobra.Command{
-	Use:   "repo-labs",
-	Short: common.T("Lista os laboratórios disponíveis no repositório remoto", "Lista los laboratorios disponibles en el repositorio remoto"),
-	Long:  common.T("Lista todos os laboratórios disponíveis no repositório remoto do GIRUS.", "Lista todos los laboratorios disponibles en el repositorio remoto de GIRUS."),
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:          "repo-labs",
+	Short:        common.T("Lista os laboratórios disponíveis nos repositórios", "Lista los laboratorios disponibles en los repositorios"),
+	Long:         common.T("Lista todos os laboratórios disponíveis nos repositórios configurados com girus repo.", "Lista todos los laboratorios disponibles en los repositorios configurados con girus repo."),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Criar formatadores de cores
-		red := color.New(color.FgRed).SprintFunc()
 		cyan := color.New(color.FgCyan).SprintFunc()
 		magenta := color.New(color.FgMagenta).SprintFunc()
 		yellow := color.New(color.FgYellow).SprintFunc()
@@ -209,24 +231,27 @@ var listRepoLabsCmd = &cobra.Command{
 
 		fmt.Println(headerColor(common.T("LABORATÓRIOS DO REPOSITÓRIO", "LABORATORIOS DEL REPOSITORIO")))
 		fmt.Println(strings.Repeat("─", 80))
-		fmt.Println(common.T("Buscando laboratórios no repositório remoto...", "Buscando laboratorios en el repositorio remoto..."))
+		fmt.Println(common.T("Buscando laboratórios nos repositórios configurados...", "Buscando laboratorios en los repositorios configurados..."))
 
-		// Obter o index.yaml
-		index, err := repo.GetLabsIndex(listRepoIndexURL)
+		client, err := repo.NewClient(listRepoIndexURL)
+		if err != nil {
+			return fmt.Errorf("%s %w", red("ERRO:"), err)
+		}
+		labs, err := listRepositoryLabs(cmd.Context(), client)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "%s %v\n", red("ERRO:"), err)
-			os.Exit(1)
+			return withLabExitCode(fmt.Errorf("%s %w", red("ERRO:"), err))
 		}
 
-		if len(index.Labs) == 0 {
+		entries := repositoryLabs(labs, nil)
+		if len(entries) == 0 {
 			fmt.Printf("\n%s %s\n", yellow("AVISO:"), common.T("Nenhum laboratório disponível no repositório.", "Ningún laboratorio disponible en el repositorio."))
-			return
+			return nil
 		}
 
 		fmt.Println("\n" + headerColor(common.T("Laboratórios disponíveis no GIRUS Hub:", "Laboratorios disponibles en GIRUS Hub:")))
 		fmt.Println(strings.Repeat("─", 60))
 
-		for i, lab := range index.Labs {
+		for i, lab := range entries {
 			if i > 0 {
 				// Separador entre os laboratórios
 				fmt.Println(strings.Repeat("─", 60))
@@ -248,11 +273,13 @@ var listRepoLabsCmd = &cobra.Command{
 			}
 
 			fmt.Printf("%s: %s\n", cyan("Tags"), repo.FormatTags(lab.Tags))
+			fmt.Printf("%s: %s\n", cyan("Repositório"), lab.Repository)
 		}
 
 		fmt.Println(strings.Repeat("─", 60))
 		fmt.Println("\n" + common.T("Para instalar um laboratório, use:", "Para instalar un laboratorio, use:"))
 		fmt.Println("  " + magenta("girus create lab <lab-id>"))
+		return nil
 	},
 }
 
@@ -265,5 +292,5 @@ func init() {
 	listCmd.AddCommand(listRepoLabsCmd)
 
 	// Flags para o comando repo-labs
-	listRepoLabsCmd.Flags().StringVarP(&listRepoIndexURL, "url", "u", "", "URL do arquivo index.yaml (opcional)")
+	listRepoLabsCmd.Flags().StringVarP(&listRepoIndexURL, "url", "u", "", "URL do repositório ou do index.yaml, usada no lugar dos repositórios configurados (opcional)")
 }
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/executil"
+)
+
+func TestListClusters(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.On("kind get clusters", executil.Response{Output: "girus\noutro\n"})
+	env.runner.On("kubectl --context kind-girus get namespace girus", executil.Response{Output: "girus   Active   2d\n"})
+	env.runner.On("kubectl --context kind-girus get pods", executil.Response{Output: "girus-backend-abc   Running   true\n"})
+
+	out, err := env.run(t, "list", "clusters", "--provider", "kind")
+	if err != nil {
+		t.Fatalf("list clusters: %v\n%s", err, out)
+	}
+	for _, want := range []string{"ATIVO Cluster girus", "girus-backend-abc", "INATIVO Cluster outro"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("saída sem %q:\n%s", want, out)
+		}
+	}
+	// Cada cluster é consultado pelo seu contexto
+	if !env.runner.Ran("kubectl --context kind-outro get namespace girus") {
+		t.Errorf("cluster outro não consultado; comandos: %q", env.runner.Commands())
+	}
+}
+
+func TestListClustersEmpty(t *testing.T) {
+	env := newTestEnv(t, nil)
+
+	out, err := env.run(t, "list", "clusters", "--provider", "kind")
+	if err != nil {
+		t.Fatalf("list clusters: %v", err)
+	}
+	if !strings.Contains(out, "Nenhum cluster encontrado") {
+		t.Errorf("saída inesperada:\n%s", out)
+	}
+}
+
+func TestListClustersMissingBinary(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.Missing("kind")
+
+	_, err := env.run(t, "list", "clusters", "--provider", "kind")
+	if err == nil || !strings.Contains(err.Error(), "Erro ao obter clusters") {
+		t.Fatalf("erro = %v, esperado kind ausente", err)
+	}
+}
+
+func TestListClustersFailure(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.On("kind get clusters", executil.Response{Output: "ERROR: failed to list clusters", ExitCode: 1})
+
+	if _, err := env.run(t, "list", "clusters", "--provider", "kind"); err == nil {
+		t.Fatal("esperado erro ao listar os clusters")
+	}
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/badtuxx/girus-cli/internal/doctor"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/repo"
+	"sigs.k8s.io/yaml"
+)
+
+// OutputSchemaVersion é a versão do esquema dos documentos emitidos com
+// --output json ou yaml. Dentro de uma versão os campos existentes não mudam de
+// nome nem de tipo; novos campos podem ser adicionados. Remoções ou mudanças
+// incompatíveis incrementam a versão.
+const OutputSchemaVersion = "girus/v1"
+
+// Formatos aceitos pela flag --output
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+// outputFlag é o valor da flag global --output, validado durante o parse
+type outputFlag string
+
+func (o *outputFlag) String() string {
+	return string(*o)
+}
+
+func (o *outputFlag) Set(value string) error {
+	switch value {
+	case OutputTable, OutputJSON, OutputYAML:
+		*o = outputFlag(value)
+		return nil
+	}
+	// -o é o formato da saída; arquivos de saída usam --output-file (-f)
+	if strings.ContainsAny(value, "./\\") {
+		return fmt.Errorf("formato inválido %q (use %s, %s ou %s; para gravar em um arquivo use --output-file/-f)", value, OutputTable, OutputJSON, OutputYAML)
+	}
+	return fmt.Errorf("formato inválido %q (use %s, %s ou %s)", value, OutputTable, OutputJSON, OutputYAML)
+}
+
+func (o *outputFlag) Type() string {
+	return "formato"
+}
+
+// outputFormat é o formato de saída selecionado com --output
+var outputFormat = outputFlag(OutputTable)
+
+// machineOutput indica se a saída foi solicitada em JSON ou YAML. Nesse caso os
+// comandos não imprimem cabeçalhos nem mensagens na saída padrão, apenas o
+// documento.
+func machineOutput() bool {
+	return outputFormat != OutputTable
+}
+
+// Document é o cabeçalho comum dos documentos JSON/YAML
+type Document struct {
+	SchemaVersion string `json:"schemaVersion"`
+	Kind          string `json:"kind"`
+}
+
+// newDocument cria o cabeçalho de um documento do tipo informado
+func newDocument(kind string) Document {
+	return Document{SchemaVersion: OutputSchemaVersion, Kind: kind}
+}
+
+// StatusDocument é o documento emitido por girus status
+type StatusDocument struct {
+	Document
+	CLIVersion   string            `json:"cliVersion"`
+	Provider     string            `json:"provider"`
+	Cluster      string            `json:"cluster"`
+	Namespace    string            `json:"namespace"`
+	Backend      ComponentStatus   `json:"backend"`
+	Frontend     ComponentStatus   `json:"frontend"`
+	Pods         []PodInfo         `json:"pods"`
+	Services     []ServiceInfo     `json:"services"`
+	PortForwards []PortForwardInfo `json:"portForwards"`
+	// ConnectPID é o PID do girus connect, zero se ele não estiver em execução
+	ConnectPID int           `json:"connectPID,omitempty"`
+	Labs       []LabTemplate `json:"labs"`
+	Resources  ResourceUsage `json:"resources"`
+	AccessURL  string        `json:"accessURL"`
+}
+
+// PortForwardInfo descreve um encaminhamento ativo do girus connect
+type PortForwardInfo struct {
+	Service    string `json:"service"`
+	LocalPort  int    `json:"localPort"`
+	RemotePort int    `json:"remotePort"`
+	Connected  bool   `json:"connected"`
+}
+
+// ClusterListDocument é o documento emitido por girus list clusters
+type ClusterListDocument struct {
+	Document
+	Provider string        `json:"provider"`
+	Items    []ClusterInfo `json:"items"`
+}
+
+// ClusterInfo descreve um cluster do provider
+type ClusterInfo struct {
+	Name    string `json:"name"`
+	Context string `json:"context"`
+	// Girus indica se o namespace do GIRUS existe no cluster
+	Girus bool      `json:"girus"`
+	Pods  []PodInfo `json:"pods,omitempty"`
+}
+
+// LabTemplateListDocument é o documento emitido por girus list labs, com os
+// laboratórios carregados no backend
+type LabTemplateListDocument struct {
+	Document
+	Items []LabTemplate `json:"items"`
+}
+
+// RepositoryLabListDocument é o documento emitido por girus lab list e girus
+// lab search, com os laboratórios dos repositórios configurados
+type RepositoryLabListDocument struct {
+	Document
+	Items []RepositoryLab `json:"items"`
+}
+
+// RepositoryLab é uma entrada do índice com o repositório de origem
+type RepositoryLab struct {
+	Repository string `json:"repository"`
+	repo.LabEntry
+}
+
+// LabVersionListDocument é o documento emitido por girus lab versions, com as
+// versões publicadas de um laboratório da mais alta para a mais baixa
+type LabVersionListDocument struct {
+	Document
+	Lab   string       `json:"lab"`
+	Items []LabVersion `json:"items"`
+}
+
+// LabVersion é uma versão publicada de um laboratório. Default indica a versão
+// instalada quando nenhuma restrição é informada.
+type LabVersion struct {
+	RepositoryLab
+	Default bool `json:"default"`
+}
+
+// InstalledLabListDocument é o documento emitido por girus lab installed, com
+// os laboratórios aplicados no namespace do GIRUS
+type InstalledLabListDocument struct {
+	Document
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	// Offline indica que o cluster não respondeu e os itens vêm do estado
+	// local em ~/.girus/installed-labs.json
+	Offline bool           `json:"offline"`
+	Items   []InstalledLab `json:"items"`
+}
+
+// InstalledLab é um laboratório instalado. Provenance é omitido para
+// laboratórios que não foram instalados de um repositório.
+type InstalledLab struct {
+	ConfigMap  string             `json:"configMap"`
+	Name       string             `json:"name,omitempty"`
+	Title      string             `json:"title,omitempty"`
+	Provenance *k8s.LabProvenance `json:"provenance,omitempty"`
+}
+
+// RepositoryListDocument é o documento emitido por girus repo list
+type RepositoryListDocument struct {
+	Document
+	Items []repo.Repository `json:"items"`
+}
+
+// DoctorDocument é o documento emitido por girus doctor
+type DoctorDocument struct {
+	Document
+	CLIVersion string         `json:"cliVersion"`
+	OS         string         `json:"os"`
+	Arch       string         `json:"arch"`
+	Provider   string         `json:"provider"`
+	Summary    DoctorSummary  `json:"summary"`
+	Checks     []doctor.Check `json:"checks"`
+}
+
+// DoctorSummary conta as verificações por resultado
+type DoctorSummary struct {
+	Pass int `json:"pass"`
+	Warn int `json:"warn"`
+	Fail int `json:"fail"`
+}
+
+// printDocument escreve o documento em w no formato de --output
+func printDocument(w io.Writer, doc interface{}) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if outputFormat == OutputYAML {
+		if data, err = yaml.JSONToYAML(data); err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
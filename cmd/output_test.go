@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/executil"
+	"github.com/badtuxx/girus-cli/internal/k8s/k8sfake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestStatusJSON(t *testing.T) {
+	env := newTestEnv(t, nil)
+	scriptGirusCluster(env.runner)
+	env.runner.On("kubectl --context kind-girus get pods -n girus -o", executil.Response{
+		Output: "NAME READY STATUS RESTARTS AGE\ngirus-backend-abc true Running 0 2025-01-01T00:00:00Z\n",
+	})
+
+	out, err := env.run(t, "status", "--provider", "kind", "-o", "json")
+	if err != nil {
+		t.Fatalf("status: %v\n%s", err, env.stderr)
+	}
+
+	var doc StatusDocument
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("saída não é JSON: %v\n%s", err, out)
+	}
+	if doc.SchemaVersion != OutputSchemaVersion || doc.Kind != "Status" {
+		t.Errorf("cabeçalho = %+v", doc.Document)
+	}
+	if doc.Provider != "kind" || doc.Cluster != "girus" || doc.Namespace != "girus" {
+		t.Errorf("cluster = %s/%s/%s", doc.Provider, doc.Cluster, doc.Namespace)
+	}
+	if doc.Backend != (ComponentStatus{Phase: "Running", Ready: true}) || doc.Frontend != (ComponentStatus{Phase: "Pending"}) {
+		t.Errorf("componentes = %+v %+v", doc.Backend, doc.Frontend)
+	}
+	if len(doc.Pods) != 1 || doc.Pods[0].Name != "girus-backend-abc" || doc.Pods[0].Ready != "True" {
+		t.Errorf("pods = %+v", doc.Pods)
+	}
+
+	// Listas vazias são emitidas como [] e não como null
+	for _, field := range []string{`"services": []`, `"portForwards": []`, `"labs": []`} {
+		if !strings.Contains(out, field) {
+			t.Errorf("saída sem %s:\n%s", field, out)
+		}
+	}
+}
+
+func TestListClustersYAML(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.On("kind get clusters", executil.Response{Output: "girus\noutro\n"})
+	env.runner.On("kubectl --context kind-girus get namespace girus", executil.Response{Output: "girus   Active   2d\n"})
+	env.runner.On("kubectl --context kind-girus get pods", executil.Response{Output: "girus-backend-abc   Running   true\n"})
+
+	out, err := env.run(t, "list", "clusters", "--provider", "kind", "--output", "yaml")
+	if err != nil {
+		t.Fatalf("list clusters: %v\n%s", err, env.stderr)
+	}
+
+	var doc ClusterListDocument
+	if err := yaml.UnmarshalStrict([]byte(out), &doc); err != nil {
+		t.Fatalf("saída não é YAML: %v\n%s", err, out)
+	}
+	want := []ClusterInfo{
+		{Name: "girus", Context: "kind-girus", Girus: true, Pods: []PodInfo{{Name: "girus-backend-abc", Status: "Running", Ready: "True"}}},
+		{Name: "outro", Context: "kind-outro"},
+	}
+	if doc.Kind != "ClusterList" || doc.Provider != "kind" || len(doc.Items) != 2 {
+		t.Fatalf("documento = %+v", doc)
+	}
+	for i := range want {
+		got := doc.Items[i]
+		if got.Name != want[i].Name || got.Context != want[i].Context || got.Girus != want[i].Girus || len(got.Pods) != len(want[i].Pods) {
+			t.Errorf("cluster %d = %+v, esperado %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestLabListJSON(t *testing.T) {
+	client, _, _ := k8sfake.NewClient()
+	env := newTestEnv(t, client)
+	serveLabRepository(t, filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+
+	out, err := env.run(t, "lab", "list", "-o", "json")
+	if err != nil {
+		t.Fatalf("lab list: %v\n%s", err, env.stderr)
+	}
+
+	var doc RepositoryLabListDocument
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("saída não é JSON: %v\n%s", err, out)
+	}
+	if doc.Kind != "RepositoryLabList" || len(doc.Items) != 1 {
+		t.Fatalf("documento = %+v", doc)
+	}
+	if lab := doc.Items[0]; lab.Repository != "teste" || lab.ID != "docker-fundamentos" || lab.Version != "1.0.0" {
+		t.Errorf("laboratório = %+v", lab)
+	}
+
+	// A busca emite o mesmo documento, apenas com os laboratórios encontrados
+	out, err = env.run(t, "lab", "search", "kubernetes", "-o", "json")
+	if err != nil {
+		t.Fatalf("lab search: %v", err)
+	}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil || len(doc.Items) != 0 {
+		t.Errorf("busca = %+v, %v\n%s", doc, err, out)
+	}
+}
+
+func TestRepoListJSON(t *testing.T) {
+	env := newTestEnv(t, nil)
+	serveLabRepository(t, filepath.Join("..", "labs", "docker_fundamentos", "lab.yaml"))
+
+	out, err := env.run(t, "repo", "list", "-o", "json")
+	if err != nil {
+		t.Fatalf("repo list: %v", err)
+	}
+	var doc RepositoryListDocument
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("saída não é JSON: %v\n%s", err, out)
+	}
+	if doc.Kind != "RepositoryList" || len(doc.Items) != 1 || doc.Items[0].Name != "teste" {
+		t.Errorf("documento = %+v", doc)
+	}
+}
+
+func TestOutputInvalidFormat(t *testing.T) {
+	env := newTestEnv(t, nil)
+
+	_, err := env.run(t, "repo", "list", "-o", "xml")
+	if err == nil || !strings.Contains(err.Error(), "formato inválido") {
+		t.Fatalf("erro = %v, esperado formato inválido", err)
+	}
+}
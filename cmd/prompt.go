@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+)
+
+// Flags globais que controlam as perguntas feitas ao usuário
+var (
+	// assumeYes confirma as operações sem perguntar (--yes)
+	assumeYes bool
+	// nonInteractive impede qualquer leitura da entrada padrão (--non-interactive)
+	nonInteractive bool
+)
+
+// stdinIsTerminal indica se a entrada padrão é um terminal
+var stdinIsTerminal = func() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// interactive indica se o girus pode fazer perguntas ao usuário. Sem terminal
+// na entrada padrão (em jobs de CI, por exemplo) as perguntas nunca bloqueiam.
+func interactive() bool {
+	return !nonInteractive && stdinIsTerminal()
+}
+
+// errInteractionRequired é o erro das perguntas feitas sem terminal ou com
+// --non-interactive
+func errInteractionRequired(question string) error {
+	return common.WithExitCode(common.ExitInteractionRequired, fmt.Errorf("%s %s: %q",
+		red(common.T("ERRO:", "ERROR:")),
+		common.T("confirmação necessária sem terminal interativo (use --yes para confirmar)", "confirmación necesaria sin terminal interactiva (use --yes para confirmar)"),
+		question))
+}
+
+// confirm pede a confirmação de uma operação, usando defaultYes quando a
+// resposta é vazia. Com --yes a operação é confirmada sem perguntar; sem
+// terminal ou com --non-interactive retorna um erro com
+// common.ExitInteractionRequired em vez de esperar uma resposta.
+func confirm(question string, defaultYes bool) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+	if !interactive() {
+		return false, errInteractionRequired(question)
+	}
+
+	options := "[s/N]"
+	if defaultYes {
+		options = "[S/n]"
+	}
+	fmt.Printf("%s %s: ", question, options)
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "":
+		return defaultYes, nil
+	case "s", "sim", "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/cluster"
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/helpers"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+)
+
+// providerName é o provider de cluster informado em --provider
+var providerName string
+
+// clusterProvider retorna o provider escolhido em --provider ou na chave
+// provider do ~/.girus/config.yaml (kind por padrão)
+func clusterProvider() (cluster.ClusterProvider, error) {
+	name := providerName
+	if name == "" {
+		name = common.LoadConfig().Provider
+	}
+	return cluster.NewProvider(name, runner, k8s.CurrentClientOptions().Context)
+}
+
+// runWithProgress executa uma operação do provider mostrando a saída completa no
+// modo detalhado ou uma barra de progresso. Retorna a saída capturada quando
+// a barra de progresso é usada.
+func runWithProgress(verbose bool, description string, run func(out io.Writer) error) (string, error) {
+	if verbose {
+		return "", run(os.Stdout)
+	}
+
+	bar := helpers.CreateProgressBar(helpers.ProgressBarConfig{
+		Total:            100,
+		Description:      description,
+		Width:            80,
+		Throttle:         65,
+		SpinnerType:      14,
+		RenderBlankState: true,
+		ShowBytes:        false,
+		SetPredictTime:   false,
+	})
+
+	// Atualizar a barra de progresso enquanto a operação está em execução
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				bar.Add(1)
+				time.Sleep(200 * time.Millisecond)
+			}
+		}
+	}()
+
+	var out bytes.Buffer
+	err := run(&out)
+	close(done)
+	bar.Finish()
+	return out.String(), err
+}
@@ -3,11 +3,15 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/badtuxx/girus-cli/internal/common"
 	"github.com/badtuxx/girus-cli/internal/repo"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var repoCmd = &cobra.Command{
@@ -19,20 +23,28 @@ var repoCmd = &cobra.Command{
 var repoAddCmd = &cobra.Command{
 	Use:   "add [nome] [url]",
 	Short: common.T("Adiciona um novo repositório", "Agrega un nuevo repositorio"),
-	Long:  common.T(`Adiciona um novo repositório de laboratórios com o nome e URL especificados.`, `Agrega un nuevo repositorio de laboratorios con el nombre y URL especificados.`),
-	Args:  cobra.ExactArgs(2),
+	Long: common.T(`Adiciona um novo repositório de laboratórios com o nome e URL especificados.
+Com --public-key, o index.yaml precisa de uma assinatura destacada (index.yaml.sig)
+válida para uma das chaves, e os laboratórios precisam de digest no índice.`, `Agrega un nuevo repositorio de laboratorios con el nombre y URL especificados.
+Con --public-key, el index.yaml necesita una firma separada (index.yaml.sig)
+válida para una de las claves, y los laboratorios necesitan digest en el índice.`),
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 		url := args[1]
 		description, _ := cmd.Flags().GetString("description")
+		publicKeys, err := readPublicKeys(cmd)
+		if err != nil {
+			return err
+		}
 
 		rm, err := repo.NewRepositoryManager()
 		if err != nil {
 			return err
 		}
 
-		if err := rm.AddRepository(name, url, description); err != nil {
-			return err
+		if err := rm.AddRepository(name, url, description, publicKeys); err != nil {
+			return withLabExitCode(err)
 		}
 
 		fmt.Printf(common.T("Repositório '%s' adicionado com sucesso.\n", "Repositorio '%s' agregado con éxito.\n"), name)
@@ -73,6 +85,11 @@ var repoListCmd = &cobra.Command{
 		}
 
 		repos := rm.ListRepositories()
+		sort.Slice(repos, func(i, j int) bool { return repos[i].Name < repos[j].Name })
+		if machineOutput() {
+			return printDocument(os.Stdout, RepositoryListDocument{Document: newDocument("RepositoryList"), Items: repos})
+		}
+
 		if len(repos) == 0 {
 			fmt.Println(common.T("Nenhum repositório configurado.", "Ningún repositorio configurado."))
 			return nil
@@ -98,14 +115,23 @@ var repoUpdateCmd = &cobra.Command{
 		name := args[0]
 		url := args[1]
 		description, _ := cmd.Flags().GetString("description")
+		// Sem --public-key as chaves confiáveis atuais são mantidas
+		var publicKeys []string
+		if cmd.Flags().Changed("public-key") {
+			keys, err := readPublicKeys(cmd)
+			if err != nil {
+				return err
+			}
+			publicKeys = keys
+		}
 
 		rm, err := repo.NewRepositoryManager()
 		if err != nil {
 			return err
 		}
 
-		if err := rm.UpdateRepository(name, url, description); err != nil {
-			return err
+		if err := rm.UpdateRepository(name, url, description, publicKeys); err != nil {
+			return withLabExitCode(err)
 		}
 
 		fmt.Printf(common.T("Repositório '%s' atualizado com sucesso.\n", "Repositorio '%s' actualizado con éxito.\n"), name)
@@ -113,10 +139,107 @@ var repoUpdateCmd = &cobra.Command{
 	},
 }
 
+var repoIndexCmd = &cobra.Command{
+	Use:   "index [diretório]",
+	Short: common.T("Gera o index.yaml a partir de um diretório de laboratórios", "Genera el index.yaml a partir de un directorio de laboratorios"),
+	Long: common.T(`Percorre o diretório, lê cada lab.yaml e lab_es.yaml e gera o index.yaml do
+repositório com id, título, descrição, duração, versão, tags, url e digest.
+A versão vem de metadata.version (formato nativo) ou da anotação
+girus.linuxtips.io/version, e as tags da categoria do diretório
+(<categoria>_<id>) e da anotação girus.linuxtips.io/tags.
+
+Com --merge, as versões do índice existente que não estão no diretório são
+mantidas, preservando o histórico de versões publicadas.`, `Recorre el directorio, lee cada lab.yaml y lab_es.yaml y genera el index.yaml del
+repositorio con id, título, descripción, duración, versión, tags, url y digest.
+La versión viene de metadata.version (formato nativo) o de la anotación
+girus.linuxtips.io/version, y las tags de la categoría del directorio
+(<categoria>_<id>) y de la anotación girus.linuxtips.io/tags.
+
+Con --merge, las versiones del índice existente que no están en el directorio se
+mantienen, preservando el historial de versiones publicadas.`),
+	Example: `  girus repo index labs --base-url https://raw.githubusercontent.com/badtuxx/girus-cli/main/labs
+  girus repo index labs --merge labs/index.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := args[0]
+		baseURL, _ := cmd.Flags().GetString("base-url")
+		mergeFile, _ := cmd.Flags().GetString("merge")
+		outputFile, _ := cmd.Flags().GetString("output-file")
+		if outputFile == "" {
+			outputFile = filepath.Join(dir, "index.yaml")
+		}
+
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return common.WithExitCode(common.ExitUsage, fmt.Errorf("%s %s", red(common.T("ERRO:", "ERROR:")), fmt.Sprintf(common.T("'%s' não é um diretório", "'%s' no es un directorio"), dir)))
+		}
+
+		opts := repo.IndexOptions{BaseURL: baseURL}
+		if mergeFile != "" {
+			existing, err := repo.ReadIndexFile(mergeFile)
+			if err != nil {
+				return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+			}
+			opts.Merge = existing
+		}
+
+		index, err := repo.GenerateIndex(dir, opts)
+		if err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+
+		file, err := os.Create(outputFile)
+		if err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+		defer file.Close()
+		enc := yaml.NewEncoder(file)
+		enc.SetIndent(2)
+		if err := enc.Encode(index); err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+		if err := enc.Close(); err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+
+		fmt.Printf("%s %s\n", green(common.T("SUCESSO:", "ÉXITO:")), fmt.Sprintf(common.T("%d entradas escritas em %s", "%d entradas escritas en %s"), len(index.Labs), outputFile))
+		return nil
+	},
+}
+
+// readPublicKeys lê as chaves da flag --public-key, informadas diretamente ou
+// como @arquivo (por exemplo, @minisign.pub)
+func readPublicKeys(cmd *cobra.Command) ([]string, error) {
+	values, _ := cmd.Flags().GetStringArray("public-key")
+	keys := []string{}
+	for _, value := range values {
+		if path, ok := strings.CutPrefix(value, "@"); ok {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf(common.T("erro ao ler a chave pública: %v", "error al leer la clave pública: %v"), err)
+			}
+			value = string(data)
+		}
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		if _, err := repo.ParsePublicKey(value); err != nil {
+			return nil, common.WithExitCode(common.ExitUsage, err)
+		}
+		keys = append(keys, value)
+	}
+	return keys, nil
+}
+
 func init() {
-	repoCmd.AddCommand(repoAddCmd, repoRemoveCmd, repoListCmd, repoUpdateCmd)
+	repoCmd.AddCommand(repoAddCmd, repoRemoveCmd, repoListCmd, repoUpdateCmd, repoIndexCmd)
 
 	// Flags para os comandos
 	repoAddCmd.Flags().String("description", "", common.T("Descrição do repositório", "Descripción del repositorio"))
 	repoUpdateCmd.Flags().String("description", "", common.T("Nova descrição do repositório", "Nueva descripción del repositorio"))
+	repoIndexCmd.Flags().String("base-url", "", common.T("URL base dos laboratórios; sem ela as URLs ficam relativas ao index.yaml", "URL base de los laboratorios; sin ella las URLs quedan relativas al index.yaml"))
+	repoIndexCmd.Flags().String("merge", "", common.T("Índice existente cujas versões anteriores são mantidas", "Índice existente cuyas versiones anteriores se mantienen"))
+	repoIndexCmd.Flags().String("output-file", "", common.T("Arquivo de saída (padrão: <diretório>/index.yaml)", "Archivo de salida (predeterminado: <directorio>/index.yaml)"))
+	for _, c := range []*cobra.Command{repoAddCmd, repoUpdateCmd} {
+		c.Flags().StringArray("public-key", nil, common.T("Chave pública ed25519 confiável (base64, PEM ou minisign), ou @arquivo; pode ser repetida", "Clave pública ed25519 de confianza (base64, PEM o minisign), o @archivo; puede repetirse"))
+	}
 }
@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
+	"github.com/badtuxx/girus-cli/internal/cluster"
 	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/repo"
 )
 
 var rootCmd = &cobra.Command{
@@ -19,11 +27,74 @@ SRE, Dev e Platform Engineering.`,
 gestionar y ejecutar entornos de aprendizaje práctico para tecnologías como Linux,
 Docker, Kubernetes, Terraform y otras herramientas esenciales para profesionales de DevOps,
 SRE, Dev y Platform Engineering.`),
+	// Os erros são exibidos uma única vez pelo main, junto com o código de saída
+	SilenceErrors: true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		commandStarted = true
+		setClientOptions(cmd)
+		repo.SetInsecureSkipVerify(insecureSkipVerify)
+		if insecureSkipVerify {
+			fmt.Fprintf(os.Stderr, "%s %s\n", yellow("AVISO:"), common.T("verificação de digests e assinaturas dos repositórios desativada (--insecure-skip-verify)", "verificación de digests y firmas de los repositorios desactivada (--insecure-skip-verify)"))
+		}
+	},
+}
+
+// commandStarted indica que as flags e os argumentos foram validados e o
+// comando começou a executar; erros anteriores são erros de uso
+var commandStarted bool
+
+// insecureSkipVerify desativa a verificação de integridade dos repositórios
+var insecureSkipVerify bool
+
+// Opções do cluster informadas nas flags globais
+var (
+	kubeconfigFlag string
+	contextFlag    string
+	namespaceFlag  string
+)
+
+// setClientOptions define o kubeconfig, o contexto e o namespace usados pelo
+// GIRUS a partir das flags globais ou, se omitidas, do ~/.girus/config.yaml.
+// Sem contexto informado, os comandos acessam o cluster girus do provider
+// (kind-girus no kind, por exemplo), e não o contexto atual do kubectl; o
+// install e o uninstall, feitos para clusters existentes, usam o contexto atual.
+func setClientOptions(cmd *cobra.Command) {
+	cfg := common.LoadConfig()
+	opts := k8s.ClientOptions{Kubeconfig: cfg.Kubeconfig, Context: cfg.Context, Namespace: cfg.Namespace}
+	if kubeconfigFlag != "" {
+		opts.Kubeconfig = kubeconfigFlag
+	}
+	if contextFlag != "" {
+		opts.Context = contextFlag
+	}
+	if namespaceFlag != "" {
+		opts.Namespace = namespaceFlag
+	}
+	// kind, k3d, minikube e kubectl executados pelo GIRUS usam o mesmo kubeconfig
+	if opts.Kubeconfig != "" {
+		os.Setenv("KUBECONFIG", opts.Kubeconfig)
+	}
+	k8s.SetClientOptions(opts)
+
+	if opts.Context == "" && cmd != installCmd && cmd != uninstallCmd {
+		if provider, err := clusterProvider(); err == nil && provider.Name() != cluster.ProviderBYO {
+			opts.Context = provider.KubeContext(clusterName)
+			k8s.SetClientOptions(opts)
+		}
+	}
 }
 
-// Execute executa o comando raiz
+// Execute executa o comando raiz. O contexto dos comandos é cancelado com
+// Ctrl-C (SIGINT) ou SIGTERM, interrompendo as esperas pelo cluster. Erros de
+// comando, flag ou argumento inválido recebem o código common.ExitUsage.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	err := rootCmd.ExecuteContext(ctx)
+	if err != nil && !commandStarted {
+		return common.WithExitCode(common.ExitUsage, err)
+	}
+	return err
 }
 
 func init() {
@@ -99,9 +170,21 @@ func init() {
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(connectCmd)
+	rootCmd.AddCommand(disconnectCmd)
+	rootCmd.AddCommand(installCmd)
+	rootCmd.AddCommand(uninstallCmd)
 
 	// Não adicionar updateCmd aqui, pois já é adicionado no update.go
 
 	// Configura flags globais
 	rootCmd.PersistentFlags().StringP("config", "c", "", common.T("arquivo de configuração (padrão: $HOME/.girus/config.yaml)", "archivo de configuración (predeterminado: $HOME/.girus/config.yaml)"))
+	rootCmd.PersistentFlags().StringVar(&providerName, "provider", "", common.T("provider do cluster: kind, k3d, minikube ou byo (padrão: provider do config.yaml ou kind)", "proveedor del cluster: kind, k3d, minikube o byo (predeterminado: proveedor del config.yaml o kind)"))
+	rootCmd.PersistentFlags().StringVar(&kubeconfigFlag, "kubeconfig", "", common.T("caminho do kubeconfig (padrão: $KUBECONFIG ou $HOME/.kube/config)", "ruta del kubeconfig (predeterminado: $KUBECONFIG o $HOME/.kube/config)"))
+	rootCmd.PersistentFlags().StringVar(&contextFlag, "context", "", common.T("contexto do kubeconfig usado pelo GIRUS, sem alterar o contexto atual", "contexto del kubeconfig usado por GIRUS, sin cambiar el contexto actual"))
+	rootCmd.PersistentFlags().StringVarP(&namespaceFlag, "namespace", "n", "", common.T("namespace do GIRUS (padrão: girus)", "namespace de GIRUS (predeterminado: girus)"))
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, common.T("confirma as operações sem perguntar", "confirma las operaciones sin preguntar"))
+	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, common.T("nunca lê a entrada padrão; perguntas sem --yes falham com o código de saída 8 (padrão quando a entrada não é um terminal)", "nunca lee la entrada estándar; las preguntas sin --yes fallan con el código de salida 8 (predeterminado cuando la entrada no es una terminal)"))
+	rootCmd.PersistentFlags().BoolVar(&insecureSkipVerify, "insecure-skip-verify", false, common.T("não verifica os digests dos laboratórios nem as assinaturas dos índices dos repositórios", "no verifica los digests de los laboratorios ni las firmas de los índices de los repositorios"))
+	rootCmd.PersistentFlags().VarP(&outputFormat, "output", "o", common.T("formato da saída de status, doctor e listagens: table, json ou yaml (esquema "+OutputSchemaVersion+")", "formato de la salida de status, doctor y listados: table, json o yaml (esquema "+OutputSchemaVersion+")"))
 }
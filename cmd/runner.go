@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/executil"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+)
+
+// Dependências externas dos comandos, substituídas nos testes
+var (
+	// runner executa os binários externos (kind, k3d, minikube, kubectl, docker)
+	runner executil.Runner = executil.ExecRunner{}
+	// newKubernetesClient cria o cliente do cluster do GIRUS
+	newKubernetesClient = k8s.NewKubernetesClient
+	// waitForPodsReady espera os componentes do GIRUS responderem
+	waitForPodsReady = k8s.WaitForPodsReady
+	// latestVersion consulta a última versão publicada do CLI
+	latestVersion = func() (string, error) { return GetLatestGitHubVersion("badtuxx/girus-cli") }
+	// backendStartupDelay é a espera pela inicialização do backend reiniciado
+	backendStartupDelay = 5 * time.Second
+	// setupPortForward inicia o girus connect em segundo plano
+	setupPortForward = k8s.SetupPortForward
+)
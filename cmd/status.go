@@ -1,42 +1,112 @@
 package cmd
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/badtuxx/girus-cli/internal/common"
-	"os/exec"
+	"os"
 	"strconv"
 	"strings"
 	"time"
-	"unicode"
 
+	"github.com/badtuxx/girus-cli/internal/cluster"
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/k8s"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // Estrutura para armazenar informações sobre os serviços expostos
 type ServiceInfo struct {
-	Name      string
-	Type      string
-	ClusterIP string
-	Ports     string
-	Age       string
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	ClusterIP string `json:"clusterIP"`
+	Ports     string `json:"ports"`
+	Age       string `json:"age"`
 }
 
 // Estrutura para armazenar informações do pod
 type PodInfo struct {
-	Name     string
-	Ready    string
-	Status   string
-	Restarts string
-	Age      string
+	Name     string `json:"name"`
+	Ready    string `json:"ready"`
+	Status   string `json:"status"`
+	Restarts string `json:"restarts,omitempty"`
+	Age      string `json:"age,omitempty"`
 }
 
-// Estrutura para armazenar uso de recursos
+// Estrutura para armazenar uso de recursos. CPU e Memory resumem o consumo dos
+// nós em quantidades do Kubernetes, como "1/4 (25%)" e "2Gi/8Gi (25%)", e
+// ficam vazios sem métricas; os detalhes por nó e por pod vêm da API de
+// métricas (metrics-server).
 type ResourceUsage struct {
-	CPU    string
-	Memory string
+	CPU    string `json:"cpu"`
+	Memory string `json:"memory"`
+	// MetricsAvailable indica se a API metrics.k8s.io respondeu
+	MetricsAvailable bool `json:"metricsAvailable"`
+	// Message explica por que as métricas não estão disponíveis
+	Message string             `json:"message,omitempty"`
+	Nodes   []NodeResourceInfo `json:"nodes"`
+	// Pods traz os pods do GIRUS e os pods de laboratório em execução
+	Pods []PodResourceInfo `json:"pods"`
+	// LabLimits são os recursos dos pods de laboratório definidos no girus-config
+	LabLimits *LabResourceLimits `json:"labLimits,omitempty"`
+}
+
+// NodeResourceInfo é o consumo de um nó
+type NodeResourceInfo struct {
+	Name                     string `json:"name"`
+	CPUMillicores            int64  `json:"cpuMillicores"`
+	CPUAllocatableMillicores int64  `json:"cpuAllocatableMillicores"`
+	MemoryBytes              int64  `json:"memoryBytes"`
+	MemoryAllocatableBytes   int64  `json:"memoryAllocatableBytes"`
+}
+
+// PodResourceInfo é o consumo de um pod. Os limites são preenchidos apenas
+// para os pods de laboratório.
+type PodResourceInfo struct {
+	Namespace          string `json:"namespace"`
+	Name               string `json:"name"`
+	Lab                bool   `json:"lab"`
+	CPUMillicores      int64  `json:"cpuMillicores"`
+	MemoryBytes        int64  `json:"memoryBytes"`
+	CPULimitMillicores int64  `json:"cpuLimitMillicores,omitempty"`
+	MemoryLimitBytes   int64  `json:"memoryLimitBytes,omitempty"`
+	// NearLimit indica que o pod atingiu 90% de algum dos limites
+	NearLimit bool `json:"nearLimit,omitempty"`
+}
+
+// LabResourceLimits são os recursos dos pods de laboratório no girus-config
+type LabResourceLimits struct {
+	CPURequest    string `json:"cpuRequest,omitempty"`
+	CPULimit      string `json:"cpuLimit,omitempty"`
+	MemoryRequest string `json:"memoryRequest,omitempty"`
+	MemoryLimit   string `json:"memoryLimit,omitempty"`
+}
+
+// ComponentStatus é o estado do pod de um componente do GIRUS. Phase fica vazia
+// quando o pod não é encontrado.
+type ComponentStatus struct {
+	Phase string `json:"phase"`
+	Ready bool   `json:"ready"`
+}
+
+// String descreve o estado do componente na saída em tabela
+func (c ComponentStatus) String() string {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	switch {
+	case c.Phase == "":
+		return red("Não encontrado")
+	case c.Phase != "Running":
+		return yellow(c.Phase)
+	case c.Ready:
+		return green("Pronto")
+	}
+	return yellow("Inicializando")
 }
 
 var statusCmd = &cobra.Command{
@@ -48,235 +118,376 @@ var statusCmd = &cobra.Command{
 - Serviços expostos e portas
 - Laboratórios instalados
 - Uso de recursos
-- Versão do CLI`, `Muestra información detallada sobre el estado actual de GIRUS, incluyendo:
+- Versão do CLI
+
+Com --watch o status é atualizado continuamente pelos watches da API do
+Kubernetes: mudanças nos pods, serviços, port-forwards, laboratórios e nós
+são destacadas e os eventos de Warning recentes do namespace são exibidos.
+Use --full-screen para ocupar o terminal inteiro.`, `Muestra información detallada sobre el estado actual de GIRUS, incluyendo:
 - Estado del cluster
 - Pods en ejecución (backend y frontend)
 - Servicios expuestos y puertos
 - Laboratorios instalados
 - Uso de recursos
-- Versión de la CLI`),
-	Run: func(cmd *cobra.Command, args []string) {
+- Versión de la CLI
+
+Con --watch el estado se actualiza continuamente mediante los watches de la API
+de Kubernetes: los cambios en pods, servicios, port-forwards, laboratorios y
+nodos se resaltan y se muestran los eventos de Warning recientes del namespace.
+Use --full-screen para ocupar toda la terminal.`),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Criar formatadores de cores
 		green := color.New(color.FgGreen).SprintFunc()
 		red := color.New(color.FgRed).SprintFunc()
-		cyan := color.New(color.FgCyan).SprintFunc()
 		bold := color.New(color.Bold).SprintFunc()
 		magenta := color.New(color.FgMagenta).SprintFunc() // Para informações importantes
 
+		// A tela cheia implica o modo --watch
+		watch := statusWatch || statusFullScreen
+		if watch && machineOutput() {
+			return common.WithExitCode(common.ExitUsage, fmt.Errorf("%s %s", red(common.T("ERRO:", "ERROR:")), common.T("--watch não pode ser usado com --output json/yaml", "--watch no puede usarse con --output json/yaml")))
+		}
+		if watch && statusInterval <= 0 {
+			return common.WithExitCode(common.ExitUsage, fmt.Errorf("%s %s", red(common.T("ERRO:", "ERROR:")), common.T("--interval deve ser maior que zero", "--interval debe ser mayor que cero")))
+		}
+
 		// Criar formatador para títulos
 		headerColor := color.New(color.FgCyan, color.Bold).SprintFunc()
 
+		// Na saída JSON/YAML apenas o documento vai para a saída padrão
+		table := !machineOutput()
+
 		// Exibir cabeçalho
-		fmt.Println(strings.Repeat("─", 80))
-		fmt.Println(headerColor(common.T("GIRUS STATUS", "GIRUS ESTADO")))
-		fmt.Println(strings.Repeat("─", 80))
+		if table {
+			fmt.Println(strings.Repeat("─", 80))
+			fmt.Println(headerColor(common.T("GIRUS STATUS", "GIRUS ESTADO")))
+			fmt.Println(strings.Repeat("─", 80))
 
-		// Verificar versão da CLI
-		fmt.Printf(common.T("%s: %s\n", "%s: %s\n"), bold(common.T("Versão da CLI", "Versión de la CLI")), magenta(common.Version))
+			// Verificar versão da CLI
+			fmt.Printf(common.T("%s: %s\n", "%s: %s\n"), bold(common.T("Versão da CLI", "Versión de la CLI")), magenta(common.Version))
 
-		// Verificar se o cluster existe
-		fmt.Println("\n" + headerColor(common.T("Verificando Cluster...", "Verificando Cluster...")))
-		clusterExists, clusterName := checkClusterExists()
+			fmt.Println("\n" + headerColor(common.T("Verificando Cluster...", "Verificando Cluster...")))
+		}
 
+		// Verificar se o cluster existe
+		clusterExists, clusterName, err := checkClusterExists(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao verificar o cluster", "Error al verificar el cluster"), err)
+		}
 		if !clusterExists {
-			fmt.Println(red(common.T("Nenhum cluster Girus encontrado.", "Ningún cluster Girus encontrado.")))
-			fmt.Println(common.T("  Use 'girus create cluster' para criar um novo cluster.", "  Use 'girus create cluster' para crear un nuevo cluster."))
-			return
+			if table {
+				fmt.Println(common.T("  Use 'girus create cluster' para criar um novo cluster.", "  Use 'girus create cluster' para crear un nuevo cluster."))
+			}
+			return common.WithExitCode(common.ExitClusterNotFound, fmt.Errorf("%s %s", red(common.T("ERRO:", "ERROR:")), common.T("Nenhum cluster Girus encontrado.", "Ningún cluster Girus encontrado.")))
 		}
 
-		fmt.Printf("%s Cluster Girus '%s' está ativo\n", green("ATIVO"), magenta(clusterName))
+		if table {
+			fmt.Printf("%s Cluster Girus '%s' está ativo\n", green("ATIVO"), magenta(clusterName))
+			fmt.Println("\n" + headerColor(common.T("Verificando Namespace...", "Verificando Namespace...")))
+		}
 
 		// Verificar namespace girus
-		fmt.Println("\n" + headerColor(common.T("Verificando Namespace...", "Verificando Namespace...")))
-		namespaceExists := checkNamespaceExists()
-
-		if !namespaceExists {
-			fmt.Println(red(common.T("Namespace 'girus' não encontrado no cluster.", "El namespace 'girus' no se encontró en el cluster.")))
-			fmt.Println(common.T("  O cluster pode não ter sido criado corretamente.", "  El cluster puede no haberse creado correctamente."))
-			return
+		if !checkNamespaceExists(cmd.Context()) {
+			if table {
+				fmt.Println(common.T("  O cluster pode não ter sido criado corretamente.", "  El cluster puede no haberse creado correctamente."))
+			}
+			return common.WithExitCode(common.ExitClusterNotFound, fmt.Errorf(common.T("%s Namespace '%s' não encontrado no cluster.", "%s El namespace '%s' no se encontró en el cluster."), red(common.T("ERRO:", "ERROR:")), k8s.Namespace()))
+		}
+
+		if table {
+			fmt.Printf(common.T("%s Namespace '%s' está presente\n", "%s Namespace '%s' está presente\n"), green(common.T("ATIVO", "ACTIVO")), magenta(k8s.Namespace()))
+		}
+
+		if watch {
+			return watchStatus(cmd.Context(), os.Stdout, statusFullScreen, statusInterval)
+		}
+
+		provider, err := clusterProvider()
+		if err != nil {
+			return fmt.Errorf("%s %v", red(common.T("ERRO:", "ERROR:")), err)
+		}
+
+		doc := StatusDocument{
+			Document:   newDocument("Status"),
+			CLIVersion: common.Version,
+			Provider:   provider.Name(),
+			Cluster:    clusterName,
+			Namespace:  k8s.Namespace(),
+			Pods:       getPodDetails(cmd.Context()),
+			Services:   getServiceDetails(cmd.Context()),
+			Labs:       getInstalledLabs(cmd.Context()),
+			Resources:  getResourceUsage(cmd.Context(), table),
+			AccessURL:  getAccessURL(cmd.Context()),
+			// Listas vazias são emitidas como [] e não como null
+			PortForwards: []PortForwardInfo{},
+		}
+		doc.Backend, doc.Frontend = checkComponentStatus(cmd.Context())
+		if connectStatus, _ := k8s.QueryConnect(); connectStatus != nil {
+			doc.ConnectPID = connectStatus.PID
+			for _, pf := range connectStatus.Forwards {
+				doc.PortForwards = append(doc.PortForwards, PortForwardInfo{
+					Service:    pf.Service,
+					LocalPort:  pf.LocalPort,
+					RemotePort: pf.RemotePort,
+					Connected:  pf.Connected,
+				})
+			}
 		}
 
-		fmt.Printf(common.T("%s Namespace '%s' está presente\n", "%s Namespace '%s' está presente\n"), green(common.T("ATIVO", "ACTIVO")), magenta("girus"))
+		if !table {
+			return printDocument(os.Stdout, doc)
+		}
+		printStatus(doc)
+		return nil
+	},
+}
 
-		// Obter informações sobre os pods
-		fmt.Println("\n" + headerColor(common.T("Componentes da Aplicação:", "Componentes de la Aplicación:")))
-		backendStatus, frontendStatus := checkComponentStatus()
+// Flags do girus status
+var (
+	statusWatch      bool
+	statusFullScreen bool
+	statusInterval   time.Duration
+	// statusInstallMetrics instala o metrics-server quando a API de métricas
+	// não está disponível
+	statusInstallMetrics bool
+)
 
-		fmt.Printf("   %s: %s\n", bold("Backend"), backendStatus)
-		fmt.Printf("   %s: %s\n", bold("Frontend"), frontendStatus)
+func init() {
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, common.T("Atualiza o status continuamente até Ctrl-C", "Actualiza el estado continuamente hasta Ctrl-C"))
+	statusCmd.Flags().BoolVar(&statusFullScreen, "full-screen", false, common.T("Exibe o --watch em tela cheia", "Muestra el --watch en pantalla completa"))
+	statusCmd.Flags().BoolVar(&statusInstallMetrics, "install-metrics-server", false, common.T("Instala o metrics-server se a API de métricas não estiver disponível", "Instala el metrics-server si la API de métricas no está disponible"))
+	statusCmd.Flags().DurationVar(&statusInterval, "interval", 2*time.Second, common.T("Intervalo de verificação dos port-forwards no --watch", "Intervalo de verificación de los port-forwards en --watch"))
+}
 
-		// Obter informações sobre os pods detalhadas
-		pods := getPodDetails()
-		if len(pods) > 0 {
-			fmt.Println("\n" + headerColor("Detalhes dos Pods:"))
+// printStatus exibe os componentes, os pods, os serviços, os laboratórios e os
+// recursos do GIRUS na saída em tabela
+func printStatus(doc StatusDocument) {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+	magenta := color.New(color.FgMagenta).SprintFunc()
+	headerColor := color.New(color.FgCyan, color.Bold).SprintFunc()
+
+	// Obter informações sobre os pods
+	fmt.Println("\n" + headerColor(common.T("Componentes da Aplicação:", "Componentes de la Aplicación:")))
+	fmt.Printf("   %s: %s\n", bold("Backend"), doc.Backend)
+	fmt.Printf("   %s: %s\n", bold("Frontend"), doc.Frontend)
+
+	// Obter informações sobre os pods detalhadas
+	if len(doc.Pods) > 0 {
+		fmt.Println("\n" + headerColor("Detalhes dos Pods:"))
+		fmt.Printf("   %-35s %-10s %-10s %-10s %-10s\n",
+			cyan("NOME"),
+			cyan("PRONTO"),
+			cyan("STATUS"),
+			cyan("RESTARTS"),
+			cyan("IDADE"))
+		for _, pod := range doc.Pods {
 			fmt.Printf("   %-35s %-10s %-10s %-10s %-10s\n",
-				cyan("NOME"),
-				cyan("PRONTO"),
-				cyan("STATUS"),
-				cyan("RESTARTS"),
-				cyan("IDADE"))
-			for _, pod := range pods {
-				fmt.Printf("   %-35s %-10s %-10s %-10s %-10s\n",
-					magenta(pod.Name), pod.Ready, pod.Status, pod.Restarts, pod.Age)
-			}
+				magenta(pod.Name), pod.Ready, pod.Status, pod.Restarts, pod.Age)
 		}
+	}
 
-		// Obter informações sobre os serviços expostos
-		services := getServiceDetails()
-		if len(services) > 0 {
-			fmt.Println("\n" + headerColor("Serviços Expostos:"))
+	// Obter informações sobre os serviços expostos
+	if len(doc.Services) > 0 {
+		fmt.Println("\n" + headerColor("Serviços Expostos:"))
+		fmt.Printf("   %-20s %-10s %-15s %-20s %-10s\n",
+			cyan("NOME"),
+			cyan("TIPO"),
+			cyan("CLUSTER-IP"),
+			cyan("PORTAS"),
+			cyan("IDADE"))
+		for _, svc := range doc.Services {
 			fmt.Printf("   %-20s %-10s %-15s %-20s %-10s\n",
-				cyan("NOME"),
-				cyan("TIPO"),
-				cyan("CLUSTER-IP"),
-				cyan("PORTAS"),
-				cyan("IDADE"))
-			for _, svc := range services {
-				fmt.Printf("   %-20s %-10s %-15s %-20s %-10s\n",
-					magenta(svc.Name), svc.Type, svc.ClusterIP, magenta(svc.Ports), svc.Age)
-			}
+				magenta(svc.Name), svc.Type, svc.ClusterIP, magenta(svc.Ports), svc.Age)
 		}
+	}
 
-		// Verificar port-forwards ativos
-		portForwards := getActivePortForwards()
-		if len(portForwards) > 0 {
-			fmt.Println("\n" + headerColor("Port-Forwards Ativos:"))
-			for _, pf := range portForwards {
-				parts := strings.Fields(pf)
-				if len(parts) >= 2 {
-					fmt.Printf("   %s %s\n", parts[0], magenta(strings.Join(parts[1:], " ")))
-				} else {
-					fmt.Printf("   %s\n", magenta(pf))
-				}
+	// Verificar port-forwards ativos (girus connect)
+	if len(doc.PortForwards) > 0 {
+		fmt.Printf("\n%s (girus connect, PID %d)\n", headerColor("Port-Forwards Ativos:"), doc.ConnectPID)
+		for _, pf := range doc.PortForwards {
+			state := green("conectado")
+			if !pf.Connected {
+				state = yellow("reconectando")
 			}
+			forward := k8s.PortForward{Service: pf.Service, LocalPort: pf.LocalPort, RemotePort: pf.RemotePort}
+			fmt.Printf("   %s %s\n", magenta(forward.String()), state)
 		}
+	} else {
+		fmt.Println("\n" + headerColor("Port-Forwards Ativos:") + " Nenhum")
+		fmt.Println("   Use " + cyan("'girus connect'") + " para acessar o GIRUS localmente")
+	}
 
-		// Listar laboratórios instalados
-		labs := getInstalledLabs()
-		if len(labs) > 0 {
-			fmt.Println("\n" + headerColor("Laboratórios Instalados:"))
-			for i, lab := range labs {
-				parts := strings.SplitN(lab, " - ", 2)
-				if len(parts) == 2 {
-					fmt.Printf("   %d. %s - %s\n", i+1, magenta(parts[0]), parts[1])
-				} else {
-					fmt.Printf("   %d. %s\n", i+1, magenta(lab))
-				}
+	// Listar laboratórios instalados
+	if len(doc.Labs) > 0 {
+		fmt.Println("\n" + headerColor("Laboratórios Instalados:"))
+		for i, lab := range doc.Labs {
+			fmt.Printf("   %d. %s - %s\n", i+1, magenta(lab.Name), lab.Title)
+		}
+	} else {
+		fmt.Println("\n" + headerColor("Laboratórios Instalados:") + " Nenhum")
+		fmt.Println("   Use " + cyan("'girus lab install <nome-do-lab>'") + " para instalar um laboratório")
+	}
+
+	// Obter uso de recursos
+	printResourceUsage(doc.Resources)
+
+	// URL de acesso
+	fmt.Println("\n" + headerColor("Acesso à Aplicação:"))
+	if doc.AccessURL != "" {
+		fmt.Printf("   %s\n", magenta(doc.AccessURL))
+	} else {
+		fmt.Printf("   %s\n", magenta(common.T("Execute 'girus connect' para acessar", "Ejecute 'girus connect' para acceder")))
+	}
+
+	// Dicas e informações adicionais
+	fmt.Println("\n" + headerColor("Dicas Rápidas:"))
+	fmt.Println("   • Para listar laboratórios disponíveis: " + magenta("girus lab list"))
+	fmt.Println("   • Para instalar um laboratório: " + magenta("girus lab install <nome>"))
+	fmt.Println("   • Para excluir o cluster: " + magenta("girus delete cluster"))
+	fmt.Println("   • Para atualizar a CLI: " + magenta("girus update"))
+
+	fmt.Println(strings.Repeat("─", 80))
+}
+
+// printResourceUsage exibe o consumo dos nós e dos pods. Pods de laboratório
+// perto dos limites do girus-config são destacados.
+func printResourceUsage(usage ResourceUsage) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+	magenta := color.New(color.FgMagenta).SprintFunc()
+	headerColor := color.New(color.FgCyan, color.Bold).SprintFunc()
+
+	fmt.Println("\n" + headerColor(common.T("Recursos do Cluster:", "Recursos del Clúster:")))
+	if !usage.MetricsAvailable {
+		fmt.Printf("   %s: %s\n", bold("CPU"), magenta(common.T("Não disponível", "No disponible")))
+		fmt.Printf("   %s: %s\n", bold(common.T("Memória", "Memoria")), magenta(common.T("Não disponível", "No disponible")))
+		if usage.Message != "" {
+			fmt.Printf("   %s %s\n", yellow(common.T("AVISO:", "AVISO:")), usage.Message)
+		}
+		fmt.Println("   " + common.T("Para instalar o metrics-server: ", "Para instalar el metrics-server: ") + magenta("girus status --install-metrics-server"))
+		return
+	}
+
+	var cpu, cpuTotal, memory, memoryTotal int64
+	for _, n := range usage.Nodes {
+		cpu, cpuTotal = cpu+n.CPUMillicores, cpuTotal+n.CPUAllocatableMillicores
+		memory, memoryTotal = memory+n.MemoryBytes, memoryTotal+n.MemoryAllocatableBytes
+	}
+	fmt.Printf("   %s: %s\n", bold("CPU"), magenta(fmt.Sprintf(common.T("%.2f de %.2f cores em uso (%d%%)", "%.2f de %.2f núcleos en uso (%d%%)"),
+		float64(cpu)/1000, float64(cpuTotal)/1000, percent(cpu, cpuTotal))))
+	fmt.Printf("   %s: %s\n", bold(common.T("Memória", "Memoria")), magenta(fmt.Sprintf(common.T("%s de %s em uso (%d%%)", "%s de %s en uso (%d%%)"),
+		formatBytes(memory), formatBytes(memoryTotal), percent(memory, memoryTotal))))
+
+	if len(usage.Nodes) > 1 {
+		fmt.Printf("\n   %-30s %-20s %s\n", cyan(common.T("NÓ", "NODO")), cyan("CPU"), cyan(common.T("MEMÓRIA", "MEMORIA")))
+		for _, n := range usage.Nodes {
+			fmt.Printf("   %-30s %-20s %s\n", n.Name,
+				fmt.Sprintf("%dm (%d%%)", n.CPUMillicores, percent(n.CPUMillicores, n.CPUAllocatableMillicores)),
+				fmt.Sprintf("%s (%d%%)", formatBytes(n.MemoryBytes), percent(n.MemoryBytes, n.MemoryAllocatableBytes)))
+		}
+	}
+
+	if len(usage.Pods) > 0 {
+		fmt.Printf("\n   %-20s %-35s %-16s %s\n", cyan("NAMESPACE"), cyan("POD"), cyan("CPU"), cyan(common.T("MEMÓRIA", "MEMORIA")))
+		for _, p := range usage.Pods {
+			cpu := fmt.Sprintf("%dm", p.CPUMillicores)
+			if p.CPULimitMillicores > 0 {
+				cpu += fmt.Sprintf("/%dm", p.CPULimitMillicores)
+			}
+			memory := formatBytes(p.MemoryBytes)
+			if p.MemoryLimitBytes > 0 {
+				memory += "/" + formatBytes(p.MemoryLimitBytes)
 			}
-		} else {
-			fmt.Println("\n" + headerColor("Laboratórios Instalados:") + " Nenhum")
-			fmt.Println("   Use " + cyan("'girus lab install <nome-do-lab>'") + " para instalar um laboratório")
+			line := fmt.Sprintf("   %-20s %-35s %-16s %s", p.Namespace, p.Name, cpu, memory)
+			if p.NearLimit {
+				line = red(line + "  " + common.T("(perto do limite do laboratório)", "(cerca del límite del laboratorio)"))
+			}
+			fmt.Println(line)
 		}
+	}
 
-		// Obter uso de recursos
-		nodeResources := getNodeResources()
-		fmt.Println("\n" + headerColor("Recursos do Cluster:"))
-		fmt.Printf("   %s: %s\n", bold("CPU"), magenta(nodeResources.CPU))
-		fmt.Printf("   %s: %s\n", bold("Memória"), magenta(nodeResources.Memory))
-
-		// URL de acesso
-		fmt.Println("\n" + headerColor("Acesso à Aplicação:"))
-		url := getAccessURL()
-		fmt.Printf("   %s\n", magenta(url))
-
-		// Dicas e informações adicionais
-		fmt.Println("\n" + headerColor("Dicas Rápidas:"))
-		fmt.Println("   • Para listar laboratórios disponíveis: " + magenta("girus lab list"))
-		fmt.Println("   • Para instalar um laboratório: " + magenta("girus lab install <nome>"))
-		fmt.Println("   • Para excluir o cluster: " + magenta("girus delete cluster"))
-		fmt.Println("   • Para atualizar a CLI: " + magenta("girus update"))
-
-		fmt.Println(strings.Repeat("─", 80))
-	},
+	if l := usage.LabLimits; l != nil {
+		fmt.Printf(common.T("\n   %s: CPU %s-%s, memória %s-%s (girus-config)\n", "\n   %s: CPU %s-%s, memoria %s-%s (girus-config)\n"), bold(common.T("Limites dos laboratórios", "Límites de los laboratorios")),
+			orDash(l.CPURequest), orDash(l.CPULimit), orDash(l.MemoryRequest), orDash(l.MemoryLimit))
+	}
 }
 
-// checkClusterExists verifica se o cluster Kind existe
-func checkClusterExists() (bool, string) {
-	cmd := exec.Command("kind", "get", "clusters")
-	output, err := cmd.Output()
+// orDash retorna "-" para valores vazios
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// checkClusterExists verifica se o cluster Girus existe no provider configurado,
+// retornando o nome do cluster. Falhas do provider (binário ausente, por
+// exemplo) são retornadas como erro.
+func checkClusterExists(ctx context.Context) (bool, string, error) {
+	provider, err := clusterProvider()
 	if err != nil {
-		return false, ""
+		return false, "", err
 	}
 
-	clusters := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, cluster := range clusters {
-		if cluster == "girus" {
-			return true, cluster
+	// No provider byo o cluster é o contexto informado em --context ou o
+	// contexto atual do kubeconfig
+	if provider.Name() == cluster.ProviderBYO {
+		name := k8s.CurrentClientOptions().Context
+		if name == "" {
+			out, err := runner.Output(ctx, "kubectl", k8s.KubectlArgs("config", "current-context")...)
+			if err != nil {
+				return false, "", nil
+			}
+			name = strings.TrimSpace(string(out))
 		}
+		ok, err := provider.Exists(ctx, name)
+		return ok, name, err
 	}
 
-	return false, ""
+	ok, err := provider.Exists(ctx, clusterName)
+	return ok, clusterName, err
 }
 
 // checkNamespaceExists verifica se o namespace girus existe
-func checkNamespaceExists() bool {
-	cmd := exec.Command("kubectl", "get", "namespace", "girus", "--no-headers", "--ignore-not-found")
-	output, err := cmd.Output()
+func checkNamespaceExists(ctx context.Context) bool {
+	output, err := runner.Output(ctx, "kubectl", k8s.KubectlArgs("get", "namespace", k8s.Namespace(), "--no-headers", "--ignore-not-found")...)
 	if err != nil {
 		return false
 	}
 
-	return strings.Contains(string(output), "girus")
+	return strings.Contains(string(output), k8s.Namespace())
 }
 
 // checkComponentStatus verifica o status dos componentes backend e frontend
-func checkComponentStatus() (string, string) {
-	// Criar formatadores de cores
-	green := color.New(color.FgGreen).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
+func checkComponentStatus(ctx context.Context) (ComponentStatus, ComponentStatus) {
+	return componentStatus(ctx, "girus-backend"), componentStatus(ctx, "girus-frontend")
+}
 
-	// Verificar o backend
-	backendCmd := exec.Command("kubectl", "get", "pods", "-n", "girus", "-l", "app=girus-backend", "-o", "jsonpath={.items[0].status.phase}")
-	backendOutput, err := backendCmd.Output()
-	var backendStatus string
-	if err == nil && len(backendOutput) > 0 {
-		status := string(backendOutput)
-		if status == "Running" {
-			// Verificar se todos os containers estão prontos
-			readyCmd := exec.Command("kubectl", "get", "pods", "-n", "girus", "-l", "app=girus-backend", "-o", "jsonpath={.items[0].status.containerStatuses[0].ready}")
-			readyOutput, err := readyCmd.Output()
-			if err == nil && string(readyOutput) == "true" {
-				backendStatus = green("Pronto")
-			} else {
-				backendStatus = yellow("Inicializando")
-			}
-		} else {
-			backendStatus = yellow(status)
-		}
-	} else {
-		backendStatus = red("Não encontrado")
-	}
-
-	// Verificar o frontend
-	frontendCmd := exec.Command("kubectl", "get", "pods", "-n", "girus", "-l", "app=girus-frontend", "-o", "jsonpath={.items[0].status.phase}")
-	frontendOutput, err := frontendCmd.Output()
-	var frontendStatus string
-	if err == nil && len(frontendOutput) > 0 {
-		status := string(frontendOutput)
-		if status == "Running" {
-			// Verificar se todos os containers estão prontos
-			readyCmd := exec.Command("kubectl", "get", "pods", "-n", "girus", "-l", "app=girus-frontend", "-o", "jsonpath={.items[0].status.containerStatuses[0].ready}")
-			readyOutput, err := readyCmd.Output()
-			if err == nil && string(readyOutput) == "true" {
-				frontendStatus = green("Pronto")
-			} else {
-				frontendStatus = yellow("Inicializando")
-			}
-		} else {
-			frontendStatus = yellow(status)
-		}
-	} else {
-		frontendStatus = red("Não encontrado")
+// componentStatus obtém a fase e a prontidão do pod do componente
+func componentStatus(ctx context.Context, app string) ComponentStatus {
+	output, err := runner.Output(ctx, "kubectl", k8s.KubectlArgs("get", "pods", "-n", k8s.Namespace(), "-l", "app="+app, "-o", "jsonpath={.items[0].status.phase}")...)
+	if err != nil || len(output) == 0 {
+		return ComponentStatus{}
 	}
 
-	return backendStatus, frontendStatus
+	status := ComponentStatus{Phase: string(output)}
+	if status.Phase == "Running" {
+		// Verificar se todos os containers estão prontos
+		readyOutput, err := runner.Output(ctx, "kubectl", k8s.KubectlArgs("get", "pods", "-n", k8s.Namespace(), "-l", "app="+app, "-o", "jsonpath={.items[0].status.containerStatuses[0].ready}")...)
+		status.Ready = err == nil && string(readyOutput) == "true"
+	}
+	return status
 }
 
 // getPodDetails obtém detalhes sobre os pods
-func getPodDetails() []PodInfo {
-	cmd := exec.Command("kubectl", "get", "pods", "-n", "girus", "-o", "custom-columns=NAME:.metadata.name,READY:.status.containerStatuses[0].ready,STATUS:.status.phase,RESTARTS:.status.containerStatuses[0].restartCount,AGE:.metadata.creationTimestamp")
-	output, err := cmd.Output()
+func getPodDetails(ctx context.Context) []PodInfo {
+	output, err := runner.Output(ctx, "kubectl", k8s.KubectlArgs("get", "pods", "-n", k8s.Namespace(), "-o", "custom-columns=NAME:.metadata.name,READY:.status.containerStatuses[0].ready,STATUS:.status.phase,RESTARTS:.status.containerStatuses[0].restartCount,AGE:.metadata.creationTimestamp")...)
 	if err != nil {
 		return []PodInfo{}
 	}
@@ -286,7 +497,7 @@ func getPodDetails() []PodInfo {
 		return []PodInfo{}
 	}
 
-	var pods []PodInfo
+	pods := []PodInfo{}
 	for i, line := range lines {
 		if i == 0 {
 			// Pular o cabeçalho
@@ -330,9 +541,8 @@ func getPodDetails() []PodInfo {
 }
 
 // getServiceDetails obtém detalhes sobre os serviços
-func getServiceDetails() []ServiceInfo {
-	cmd := exec.Command("kubectl", "get", "services", "-n", "girus", "-o", "custom-columns=NAME:.metadata.name,TYPE:.spec.type,CLUSTER-IP:.spec.clusterIP,PORT:.spec.ports[*].port,AGE:.metadata.creationTimestamp")
-	output, err := cmd.Output()
+func getServiceDetails(ctx context.Context) []ServiceInfo {
+	output, err := runner.Output(ctx, "kubectl", k8s.KubectlArgs("get", "services", "-n", k8s.Namespace(), "-o", "custom-columns=NAME:.metadata.name,TYPE:.spec.type,CLUSTER-IP:.spec.clusterIP,PORT:.spec.ports[*].port,AGE:.metadata.creationTimestamp")...)
 	if err != nil {
 		return []ServiceInfo{}
 	}
@@ -342,7 +552,7 @@ func getServiceDetails() []ServiceInfo {
 		return []ServiceInfo{}
 	}
 
-	var services []ServiceInfo
+	services := []ServiceInfo{}
 	for i, line := range lines {
 		if i == 0 {
 			// Pular o cabeçalho
@@ -352,8 +562,7 @@ func getServiceDetails() []ServiceInfo {
 		fields := strings.Fields(line)
 		if len(fields) >= 5 {
 			// Obter portas expostas
-			portsCmd := exec.Command("kubectl", "get", "service", fields[0], "-n", "girus", "-o", "jsonpath={.spec.ports[*].port}:{.spec.ports[*].nodePort}")
-			portsOutput, err := portsCmd.Output()
+			portsOutput, err := runner.Output(ctx, "kubectl", k8s.KubectlArgs("get", "service", fields[0], "-n", k8s.Namespace(), "-o", "jsonpath={.spec.ports[*].port}:{.spec.ports[*].nodePort}")...)
 			ports := fields[3]
 			if err == nil && len(portsOutput) > 0 {
 				portParts := strings.Split(string(portsOutput), ":")
@@ -391,353 +600,183 @@ func getServiceDetails() []ServiceInfo {
 	return services
 }
 
-// getActivePortForwards obtém os port-forwards ativos
-func getActivePortForwards() []string {
-	cmd := exec.Command("ps", "-ef")
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err := cmd.Run()
-	if err != nil {
-		return []string{}
-	}
-
-	var portForwards []string
-	for _, line := range strings.Split(out.String(), "\n") {
-		if strings.Contains(line, "kubectl port-forward") && strings.Contains(line, "girus") {
-			parts := strings.Fields(line)
-			if len(parts) > 8 {
-				portForwards = append(portForwards, strings.Join(parts[7:], " "))
-			}
-		}
-	}
-
-	return portForwards
-}
-
 // getInstalledLabs obtém os laboratórios instalados
-func getInstalledLabs() []string {
+func getInstalledLabs(ctx context.Context) []LabTemplate {
 	// Verificar se há um cluster Girus ativo
-	if !checkNamespaceExists() {
-		return []string{}
+	if !checkNamespaceExists(ctx) {
+		return []LabTemplate{}
 	}
 
 	// Verificar se o backend está pronto
-	backendCmd := exec.Command("kubectl", "get", "pods", "-n", "girus", "-l", "app=girus-backend", "-o", "jsonpath={.items[0].status.phase}")
-	backendOutput, err := backendCmd.Output()
+	backendOutput, err := runner.Output(ctx, "kubectl", k8s.KubectlArgs("get", "pods", "-n", k8s.Namespace(), "-l", "app=girus-backend", "-o", "jsonpath={.items[0].status.phase}")...)
 	if err != nil || string(backendOutput) != "Running" {
-		return []string{}
+		return []LabTemplate{}
 	}
 
 	// Fazer uma solicitação para a API para obter a lista de laboratórios
-	apiCmd := exec.Command("kubectl", "exec", "-n", "girus", "deploy/girus-backend", "--",
-		"wget", "-q", "-O-", "http://localhost:8080/api/v1/templates")
-	apiOutput, err := apiCmd.Output()
+	apiOutput, err := runner.Output(ctx, "kubectl", k8s.KubectlArgs("exec", "-n", k8s.Namespace(), "deploy/girus-backend", "--",
+		"wget", "-q", "-O-", "http://localhost:8080/api/v1/templates")...)
 
 	if err != nil {
-		return []string{}
+		return []LabTemplate{}
 	}
 
 	// Processar a resposta JSON
-	var response struct {
-		Templates []struct {
-			Name        string `json:"name"`
-			Title       string `json:"title"`
-			Description string `json:"description"`
-		} `json:"templates"`
+	var response LabListResponse
+	if err := json.Unmarshal(apiOutput, &response); err != nil || response.Templates == nil {
+		return []LabTemplate{}
 	}
 
-	if err := json.Unmarshal(apiOutput, &response); err != nil {
-		return []string{}
-	}
-
-	var labs []string
-	for _, template := range response.Templates {
-		labs = append(labs, fmt.Sprintf("%s - %s", template.Name, template.Title))
-	}
-
-	return labs
+	return response.Templates
 }
 
-// getNodeResources obtém informações sobre os recursos do cluster
-func getNodeResources() ResourceUsage {
-	cpuUsage := "Não disponível"
-	memoryUsage := "Não disponível"
-
-	// Abordagem 1: Tentar kubectl top nodes
-	topNodesCmd := exec.Command("kubectl", "top", "nodes", "--no-headers")
-	topNodesOutput, err := topNodesCmd.Output()
-	if err == nil && len(topNodesOutput) > 0 {
-		fields := strings.Fields(string(topNodesOutput))
-		if len(fields) >= 3 {
-			// Formatar CPU
-			cpuStr := fields[2]
-			if strings.HasSuffix(cpuStr, "m") {
-				// Converter milicores para cores
-				cpuMilli, _ := strconv.Atoi(strings.TrimSuffix(cpuStr, "m"))
-				cpuUsage = fmt.Sprintf("%.2f cores (em uso)", float64(cpuMilli)/1000.0)
-			} else {
-				cpuUsage = fmt.Sprintf("%s cores (em uso)", cpuStr)
-			}
-
-			// Formatar memória
-			memStr := fields[3]
-			memUsage := formatMemory(memStr)
-			memoryUsage = fmt.Sprintf("%s (em uso)", memUsage)
-
-			return ResourceUsage{
-				CPU:    cpuUsage,
-				Memory: memoryUsage,
-			}
-		}
+// getResourceUsage obtém o consumo de CPU e memória dos nós e dos pods pela API
+// de métricas (metrics.k8s.io). Sem o metrics-server, o motivo fica em Message
+// e, no terminal, o usuário pode instalá-lo.
+func getResourceUsage(ctx context.Context, table bool) ResourceUsage {
+	usage := ResourceUsage{
+		Nodes: []NodeResourceInfo{},
+		Pods:  []PodResourceInfo{},
 	}
 
-	// Abordagem 2: Obter recursos através dos pods
-	topPodsCmd := exec.Command("kubectl", "top", "pods", "-n", "girus", "--no-headers")
-	topPodsOutput, err := topPodsCmd.Output()
-	if err == nil && len(topPodsOutput) > 0 {
-		lines := strings.Split(strings.TrimSpace(string(topPodsOutput)), "\n")
-		totalCPU := 0
-		totalMemory := 0.0
-		for _, line := range lines {
-			fields := strings.Fields(line)
-			if len(fields) >= 3 {
-				// Extrair valores de CPU (em m)
-				cpuStr := fields[1]
-				cpuStr = strings.TrimSuffix(cpuStr, "m")
-				var cpu int
-				fmt.Sscanf(cpuStr, "%d", &cpu)
-				totalCPU += cpu
-
-				// Extrair valores de memória (em Mi)
-				memStr := fields[2]
-				memStr = strings.TrimSuffix(memStr, "Mi")
-				var mem float64
-				fmt.Sscanf(memStr, "%f", &mem)
-				totalMemory += mem
-			}
-		}
-		if len(lines) > 0 {
-			// Formatar CPU
-			if totalCPU >= 1000 {
-				cpuUsage = fmt.Sprintf("%.2f cores (pods em execução)", float64(totalCPU)/1000.0)
-			} else {
-				cpuUsage = fmt.Sprintf("%dm (%.2f cores) (pods em execução)", totalCPU, float64(totalCPU)/1000.0)
-			}
-
-			// Formatar memória
-			if totalMemory >= 1024 {
-				memoryUsage = fmt.Sprintf("%.2f GB (pods em execução)", totalMemory/1024)
-			} else {
-				memoryUsage = fmt.Sprintf("%.1f MB (pods em execução)", totalMemory)
-			}
-
-			return ResourceUsage{
-				CPU:    cpuUsage,
-				Memory: memoryUsage,
-			}
-		}
+	client, err := newKubernetesClient()
+	if err != nil {
+		usage.Message = err.Error()
+		return usage
 	}
-
-	// Abordagem 3: Obter informações através do kubectl describe node
-	describeNodeCmd := exec.Command("kubectl", "describe", "node")
-	describeOutput, err := describeNodeCmd.Output()
-	if err == nil {
-		describeStr := string(describeOutput)
-
-		// Procurar alocação total de recursos
-		cpuTotalIdx := strings.Index(describeStr, "Capacity:")
-		cpuTotal := ""
-		memTotal := ""
-
-		if cpuTotalIdx >= 0 {
-			// Extrair seção Capacity
-			capacitySection := describeStr[cpuTotalIdx : cpuTotalIdx+200]
-			capacityLines := strings.Split(capacitySection, "\n")
-
-			for _, line := range capacityLines {
-				if strings.Contains(line, "cpu:") {
-					cpuFields := strings.Fields(line)
-					if len(cpuFields) >= 2 {
-						cpuTotal = cpuFields[1]
-					}
-				}
-				if strings.Contains(line, "memory:") {
-					memFields := strings.Fields(line)
-					if len(memFields) >= 2 {
-						memTotal = memFields[1]
-					}
-				}
-			}
-		}
-
-		// Procurar alocação usada de recursos
-		cpuAllocIdx := strings.Index(describeStr, "Allocated resources:")
-		cpuAlloc := ""
-		memAlloc := ""
-
-		if cpuAllocIdx >= 0 {
-			// Extrair seção Allocated resources
-			allocSection := describeStr[cpuAllocIdx : cpuAllocIdx+500]
-			allocLines := strings.Split(allocSection, "\n")
-
-			for _, line := range allocLines {
-				if strings.Contains(line, "cpu") && !strings.Contains(line, "limits") {
-					cpuFields := strings.Fields(line)
-					if len(cpuFields) >= 2 {
-						cpuAlloc = cpuFields[1]
-					}
-				}
-				if strings.Contains(line, "memory") && !strings.Contains(line, "limits") {
-					memFields := strings.Fields(line)
-					if len(memFields) >= 2 {
-						memAlloc = memFields[1]
-					}
-				}
-			}
-		}
-
-		// Formatar saída com alocação/total quando disponível
-		if cpuTotal != "" {
-			if cpuAlloc != "" {
-				cpuUsage = fmt.Sprintf("%s de %s cores alocados", cpuAlloc, cpuTotal)
-			} else {
-				cpuUsage = fmt.Sprintf("%s cores (total)", cpuTotal)
-			}
-		}
-
-		if memTotal != "" {
-			memTotalFormatted := formatMemory(memTotal)
-			if memAlloc != "" {
-				memAllocFormatted := formatMemory(memAlloc)
-				memoryUsage = fmt.Sprintf("%s de %s alocados", memAllocFormatted, memTotalFormatted)
-			} else {
-				memoryUsage = fmt.Sprintf("%s (total)", memTotalFormatted)
-			}
-		}
-
-		if cpuUsage != "Não disponível" || memoryUsage != "Não disponível" {
-			return ResourceUsage{
-				CPU:    cpuUsage,
-				Memory: memoryUsage,
-			}
-		}
+	report, err := client.ResourceReport(ctx, k8s.Namespace())
+	if errors.Is(err, k8s.ErrMetricsUnavailable) {
+		usage.Message = common.T("metrics-server não instalado ou ainda não está pronto", "metrics-server no instalado o aún no está listo")
+		offerMetricsServer(ctx, client, table)
+		return usage
+	}
+	if err != nil {
+		usage.Message = err.Error()
+		return usage
 	}
 
-	// Abordagem 4: Verificar a definição do nó Kind
-	kindNodeCmd := exec.Command("kubectl", "get", "node", "-o", "jsonpath={.items[0].status.capacity}")
-	kindOutput, err := kindNodeCmd.Output()
-	if err == nil && len(kindOutput) > 0 {
-		// Parsear a saída JSON
-		capacityStr := string(kindOutput)
-
-		// Extrair valores de CPU e memória
-		if strings.Contains(capacityStr, "cpu") {
-			cpuStart := strings.Index(capacityStr, "cpu") + 5
-			cpuEnd := strings.Index(capacityStr[cpuStart:], "\"") + cpuStart
-			if cpuEnd > cpuStart {
-				cpuValue := capacityStr[cpuStart:cpuEnd]
-				cpuUsage = fmt.Sprintf("%s cores (total)", cpuValue)
-			}
-		}
-
-		if strings.Contains(capacityStr, "memory") {
-			memStart := strings.Index(capacityStr, "memory") + 9
-			memEnd := strings.Index(capacityStr[memStart:], "\"") + memStart
-			if memEnd > memStart {
-				memValue := capacityStr[memStart:memEnd]
-				memFormatted := formatMemory(memValue)
-				memoryUsage = fmt.Sprintf("%s (total)", memFormatted)
-			}
+	usage.MetricsAvailable = true
+	var cpu, cpuTotal, memory, memoryTotal int64
+	for _, n := range report.Nodes {
+		usage.Nodes = append(usage.Nodes, NodeResourceInfo{
+			Name:                     n.Name,
+			CPUMillicores:            n.CPU,
+			CPUAllocatableMillicores: n.CPUAllocatable,
+			MemoryBytes:              n.Memory,
+			MemoryAllocatableBytes:   n.MemoryAllocatable,
+		})
+		cpu, cpuTotal = cpu+n.CPU, cpuTotal+n.CPUAllocatable
+		memory, memoryTotal = memory+n.Memory, memoryTotal+n.MemoryAllocatable
+	}
+	usage.CPU = fmt.Sprintf("%s/%s (%d%%)",
+		resource.NewMilliQuantity(cpu, resource.DecimalSI), resource.NewMilliQuantity(cpuTotal, resource.DecimalSI), percent(cpu, cpuTotal))
+	usage.Memory = fmt.Sprintf("%s/%s (%d%%)",
+		resource.NewQuantity(memory, resource.BinarySI), resource.NewQuantity(memoryTotal, resource.BinarySI), percent(memory, memoryTotal))
+
+	for _, p := range report.Pods {
+		usage.Pods = append(usage.Pods, PodResourceInfo{
+			Namespace:          p.Namespace,
+			Name:               p.Name,
+			Lab:                p.Lab,
+			CPUMillicores:      p.CPU,
+			MemoryBytes:        p.Memory,
+			CPULimitMillicores: p.CPULimit,
+			MemoryLimitBytes:   p.MemoryLimit,
+			NearLimit:          p.NearLimit(),
+		})
+	}
+	if res := report.Lab.Resources; res != (k8s.ResourceConfig{}) {
+		usage.LabLimits = &LabResourceLimits{
+			CPURequest:    res.CPURequest,
+			CPULimit:      res.CPULimit,
+			MemoryRequest: res.MemoryRequest,
+			MemoryLimit:   res.MemoryLimit,
 		}
 	}
+	return usage
+}
 
-	return ResourceUsage{
-		CPU:    cpuUsage,
-		Memory: memoryUsage,
+// offerMetricsServer instala o metrics-server com --install-metrics-server ou,
+// em um terminal, pergunta se ele deve ser instalado. O --yes não confirma a
+// instalação: ela altera o cluster além do GIRUS e precisa ser pedida
+// explicitamente. Nos clusters locais o metrics-server aceita os certificados
+// autoassinados dos kubelets.
+func offerMetricsServer(ctx context.Context, client *k8s.KubernetesClient, table bool) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	// Fora da tabela as mensagens não podem misturar-se ao documento
+	out := os.Stdout
+	if !table {
+		out = os.Stderr
 	}
-}
 
-// formatMemory converte valores de memória para uma representação mais legível
-func formatMemory(memStr string) string {
-	// Remover unidades
-	var value float64
-	var unit string
-
-	// Extrair número e unidade
-	for i, char := range memStr {
-		if !unicode.IsDigit(char) && char != '.' {
-			numStr := memStr[:i]
-			unit = memStr[i:]
-			value, _ = strconv.ParseFloat(numStr, 64)
-			break
+	if !statusInstallMetrics {
+		if !table || assumeYes || !interactive() {
+			return
+		}
+		fmt.Printf("\n%s %s\n", yellow(common.T("AVISO:", "AVISO:")), common.T(
+			"a API de métricas não está disponível; o uso de CPU e memória requer o metrics-server.",
+			"la API de métricas no está disponible; el uso de CPU y memoria requiere el metrics-server."))
+		ok, err := confirm(fmt.Sprintf(common.T("Deseja instalar o metrics-server %s agora?", "¿Desea instalar el metrics-server %s ahora?"), k8s.MetricsServerVersion), false)
+		if err != nil || !ok {
+			return
 		}
 	}
 
-	// Se não tiver unidade, assumir bytes
-	if unit == "" {
-		value, _ = strconv.ParseFloat(memStr, 64)
-		unit = "B"
+	insecureTLS := true
+	if provider, err := clusterProvider(); err == nil && provider.Name() == cluster.ProviderBYO {
+		insecureTLS = false
+	}
+	fmt.Fprintf(out, common.T("Instalando o metrics-server %s...\n", "Instalando el metrics-server %s...\n"), k8s.MetricsServerVersion)
+	if _, err := client.InstallMetricsServer(ctx, insecureTLS); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %s: %v\n", yellow(common.T("AVISO:", "AVISO:")), common.T("falha ao instalar o metrics-server", "error al instalar el metrics-server"), err)
+		return
 	}
+	fmt.Fprintln(out, common.T("metrics-server instalado. As métricas ficam disponíveis em cerca de um minuto; execute 'girus status' novamente.",
+		"metrics-server instalado. Las métricas estarán disponibles en aproximadamente un minuto; ejecute 'girus status' nuevamente."))
+}
 
-	// Converter para unidade mais apropriada
-	switch strings.ToUpper(unit) {
-	case "KI", "KB", "K":
-		if value > 1024 {
-			return fmt.Sprintf("%.2f MB", value/1024)
-		}
-		return fmt.Sprintf("%.0f KB", value)
-	case "MI", "MB", "M":
-		if value > 1024 {
-			return fmt.Sprintf("%.2f GB", value/1024)
-		}
-		return fmt.Sprintf("%.0f MB", value)
-	case "GI", "GB", "G":
-		if value > 1024 {
-			return fmt.Sprintf("%.2f TB", value/1024)
-		}
-		return fmt.Sprintf("%.2f GB", value)
-	case "TI", "TB", "T":
-		return fmt.Sprintf("%.2f TB", value)
-	case "B":
-		if value > 1073741824 { // 1GB
-			return fmt.Sprintf("%.2f GB", value/1073741824)
-		}
-		if value > 1048576 { // 1MB
-			return fmt.Sprintf("%.2f MB", value/1048576)
-		}
-		if value > 1024 { // 1KB
-			return fmt.Sprintf("%.2f KB", value/1024)
-		}
-		return fmt.Sprintf("%.0f B", value)
-	default:
-		return memStr
+// formatBytes converte bytes para uma representação mais legível
+func formatBytes(b int64) string {
+	switch {
+	case b >= 1<<40:
+		return fmt.Sprintf("%.2f TB", float64(b)/(1<<40))
+	case b >= 1<<30:
+		return fmt.Sprintf("%.2f GB", float64(b)/(1<<30))
+	case b >= 1<<20:
+		return fmt.Sprintf("%.0f MB", float64(b)/(1<<20))
+	case b >= 1<<10:
+		return fmt.Sprintf("%.0f KB", float64(b)/(1<<10))
 	}
+	return fmt.Sprintf("%d B", b)
 }
 
-// getAccessURL obtém a URL de acesso à aplicação
-func getAccessURL() string {
+// getAccessURL obtém a URL de acesso à aplicação, ou "" se não houver uma
+func getAccessURL(ctx context.Context) string {
 	// Verificar se o serviço frontend existe
-	frontendCmd := exec.Command("kubectl", "get", "service", "girus-frontend", "-n", "girus", "--no-headers", "--ignore-not-found")
-	_, err := frontendCmd.Output()
+	_, err := runner.Output(ctx, "kubectl", k8s.KubectlArgs("get", "service", "girus-frontend", "-n", k8s.Namespace(), "--no-headers", "--ignore-not-found")...)
 	if err != nil {
-		return "Não disponível"
+		return ""
 	}
 
 	// Verificar se há port-forward ativo
-	portForwards := getActivePortForwards()
-	for _, pf := range portForwards {
-		if strings.Contains(pf, "girus-frontend") && strings.Contains(pf, "8000:") {
-			return "http://localhost:8000"
+	if connectStatus, _ := k8s.QueryConnect(); connectStatus != nil {
+		if pf, ok := connectStatus.Forward("girus-frontend"); ok {
+			return fmt.Sprintf("http://localhost:%d", pf.LocalPort)
 		}
 	}
 
 	// Verificar nodePort
-	nodePortCmd := exec.Command("kubectl", "get", "service", "girus-frontend", "-n", "girus", "-o", "jsonpath={.spec.ports[0].nodePort}")
-	nodePortOutput, err := nodePortCmd.Output()
-	if err == nil && len(nodePortOutput) > 0 {
-		return fmt.Sprintf("http://localhost:%s", string(nodePortOutput))
+	nodePortOutput, err := runner.Output(ctx, "kubectl", k8s.KubectlArgs("get", "service", "girus-frontend", "-n", k8s.Namespace(), "-o", "jsonpath={.spec.ports[0].nodePort}")...)
+	if nodePort := strings.TrimSpace(string(nodePortOutput)); err == nil && nodePort != "" {
+		// Nos clusters criados com --host-port o NodePort do frontend é
+		// alcançado pela porta mapeada no host
+		if nodePort == strconv.Itoa(cluster.FrontendNodePort) {
+			return fmt.Sprintf("http://localhost:%d", cluster.FrontendHostPort)
+		}
+		return fmt.Sprintf("http://localhost:%s", nodePort)
 	}
 
-	// Se não encontrou nenhuma forma de acesso
-	return "Execute 'kubectl port-forward svc/girus-frontend -n girus 8000:80' para acessar"
+	// Sem nenhuma forma de acesso a URL fica vazia; a dica do girus connect é
+	// exibida apenas na tabela
+	return ""
 }
@@ -0,0 +1,378 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/executil"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/k8s/k8sfake"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+// scriptGirusCluster roteiriza um cluster kind com o Girus em execução
+func scriptGirusCluster(f *executil.Fake) {
+	f.On("kind get clusters", executil.Response{Output: "girus\n"})
+	f.On("kubectl --context kind-girus get namespace girus", executil.Response{Output: "girus   Active   2d\n"})
+	f.On("kubectl --context kind-girus get pods -n girus -l app=girus-backend -o jsonpath={.items[0].status.phase}", executil.Response{Output: "Running"})
+	f.On("kubectl --context kind-girus get pods -n girus -l app=girus-backend -o jsonpath={.items[0].status.containerStatuses[0].ready}", executil.Response{Output: "true"})
+	f.On("kubectl --context kind-girus get pods -n girus -l app=girus-frontend -o jsonpath={.items[0].status.phase}", executil.Response{Output: "Pending"})
+}
+
+func TestStatus(t *testing.T) {
+	env := newTestEnv(t, nil)
+	scriptGirusCluster(env.runner)
+
+	out, err := env.run(t, "status", "--provider", "kind")
+	if err != nil {
+		t.Fatalf("status: %v\n%s", err, out)
+	}
+	for _, want := range []string{"Cluster Girus 'girus' está ativo", "Namespace 'girus' está presente", "Backend: Pronto", "Frontend: Pending", "Execute 'girus connect' para acessar"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("saída sem %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestStatusHostPortAccessURL(t *testing.T) {
+	env := newTestEnv(t, nil)
+	scriptGirusCluster(env.runner)
+	// Cluster criado com --host-port: a porta 8000 do host leva ao NodePort
+	env.runner.On("kubectl --context kind-girus get service girus-frontend -n girus -o jsonpath={.spec.ports[0].nodePort}", executil.Response{Output: "30000"})
+
+	out, err := env.run(t, "status", "--provider", "kind", "-o", "json")
+	if err != nil {
+		t.Fatalf("status: %v\n%s", err, env.stderr)
+	}
+	var doc StatusDocument
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("saída não é JSON: %v\n%s", err, out)
+	}
+	if doc.AccessURL != "http://localhost:8000" {
+		t.Errorf("accessURL = %q, esperado a porta do host", doc.AccessURL)
+	}
+}
+
+func TestStatusJSONWithoutAccessURL(t *testing.T) {
+	env := newTestEnv(t, nil)
+	scriptGirusCluster(env.runner)
+
+	out, err := env.run(t, "status", "--provider", "kind", "-o", "json")
+	if err != nil {
+		t.Fatalf("status: %v\n%s", err, env.stderr)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("saída não é JSON: %v\n%s", err, out)
+	}
+	// Sem port-forward nem NodePort o campo fica vazio, sem a dica em texto
+	if url, ok := doc["accessURL"]; !ok || url != "" {
+		t.Errorf("accessURL = %v, esperado vazio", url)
+	}
+}
+
+func TestStatusNoCluster(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.On("kind get clusters", executil.Response{Output: "outro\n"})
+
+	_, err := env.run(t, "status", "--provider", "kind")
+	if err == nil || !strings.Contains(err.Error(), "Nenhum cluster Girus encontrado") {
+		t.Fatalf("erro = %v, esperado cluster ausente", err)
+	}
+}
+
+func TestStatusMissingBinary(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.Missing("kind")
+
+	_, err := env.run(t, "status", "--provider", "kind")
+	if err == nil || !strings.Contains(err.Error(), "Erro ao verificar o cluster") {
+		t.Fatalf("erro = %v, esperado kind ausente", err)
+	}
+}
+
+func TestStatusNamespaceMissing(t *testing.T) {
+	env := newTestEnv(t, nil)
+	env.runner.On("kind get clusters", executil.Response{Output: "girus\n"})
+	env.runner.On("kubectl --context kind-girus get namespace", executil.Response{Output: "error: You must be logged in to the server", ExitCode: 1})
+
+	_, err := env.run(t, "status", "--provider", "kind")
+	if err == nil || !strings.Contains(err.Error(), "Namespace 'girus' não encontrado") {
+		t.Fatalf("erro = %v, esperado namespace ausente", err)
+	}
+}
+
+func TestStatusWatchRejectsMachineOutput(t *testing.T) {
+	env := newTestEnv(t, nil)
+
+	_, err := env.run(t, "status", "--watch", "-o", "json")
+	if code := common.ExitCode(err); code != common.ExitUsage {
+		t.Fatalf("código = %d (%v), esperado %d", code, err, common.ExitUsage)
+	}
+	if len(env.runner.Commands()) != 0 {
+		t.Errorf("comandos executados: %v", env.runner.Commands())
+	}
+}
+
+func TestWatchStatus(t *testing.T) {
+	backend := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "girus-backend-1", Namespace: "girus", Labels: map[string]string{"app": "girus-backend"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "backend"}}},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "backend", Ready: true}},
+		},
+	}
+	client, clientset, _ := k8sfake.NewClient(backend)
+	newTestEnv(t, client)
+	k8s.SetClientOptions(k8s.ClientOptions{Namespace: "girus"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var out syncBuffer
+	done := make(chan error, 1)
+	go func() { done <- watchStatus(ctx, &out, false, time.Hour) }()
+
+	waitFor(t, &out, "girus-backend-1")
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "ev", Namespace: "girus"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "girus-backend-1"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "Unhealthy",
+		Message:        "Readiness probe failed",
+		LastTimestamp:  metav1.Now(),
+	}
+	if _, err := clientset.CoreV1().Events("girus").Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, &out, "Pod/girus-backend-1 Unhealthy: Readiness probe failed")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("watchStatus: %v", err)
+	}
+	if !strings.Contains(out.String(), "* ") {
+		t.Errorf("o evento novo não foi destacado:\n%s", out.String())
+	}
+}
+
+func TestWatchStatusPermissionDenied(t *testing.T) {
+	client, clientset, _ := k8sfake.NewClient()
+	k8sfake.AllowAccessReviews(clientset, "list/pods")
+	newTestEnv(t, client)
+	k8s.SetClientOptions(k8s.ClientOptions{Namespace: "girus"})
+
+	err := watchStatus(context.Background(), io.Discard, false, time.Hour)
+	if err == nil || !strings.Contains(err.Error(), "list pods (namespace girus)") {
+		t.Fatalf("erro = %v, esperado a permissão negada", err)
+	}
+	if code := common.ExitCode(err); code == common.ExitBackendUnhealthy {
+		t.Errorf("código %d: permissão negada não indica backend parado", code)
+	}
+}
+
+func TestRenderStatusWatch(t *testing.T) {
+	previous := statusSections(k8s.Snapshot{Pods: []k8s.PodSnapshot{
+		{Name: "girus-backend-1", Ready: "0/1", Status: "ContainerCreating"},
+		{Name: "girus-frontend-1", Ready: "1/1", Status: "Running"},
+	}}, nil)
+	current := statusSections(k8s.Snapshot{Pods: []k8s.PodSnapshot{
+		{Name: "girus-backend-1", Ready: "1/1", Status: "Running"},
+		{Name: "girus-frontend-2", Ready: "1/1", Status: "Running"},
+	}}, nil)
+
+	var out bytes.Buffer
+	renderStatusWatch(&out, current, previous, time.Now())
+	lines := strings.Split(out.String(), "\n")
+
+	for name, marker := range map[string]string{
+		"girus-backend-1":  " * ",
+		"girus-frontend-2": " * ",
+		"girus-frontend-1": " - ",
+	} {
+		found := false
+		for _, line := range lines {
+			if strings.Contains(line, name) {
+				found = strings.HasPrefix(line, marker)
+			}
+		}
+		if !found {
+			t.Errorf("%s sem o marcador %q:\n%s", name, marker, out.String())
+		}
+	}
+}
+
+// syncBuffer é um bytes.Buffer seguro para uso concorrente
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitFor espera até que a saída contenha want
+func waitFor(t *testing.T, out *syncBuffer, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("saída sem %q:\n%s", want, out.String())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestStatusResources(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "girus-control-plane"},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+		}},
+	}
+	config := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "girus-config", Namespace: "girus"},
+		Data:       map[string]string{"config.yaml": "lab:\n  resources:\n    cpuLimit: 500m\n    memoryLimit: 256Mi\n"},
+	}
+	usage := func(cpu, memory string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)}
+	}
+	metrics := metricsfake.NewSimpleClientset()
+	metrics.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &metricsv1beta1.NodeMetricsList{Items: []metricsv1beta1.NodeMetrics{
+			{ObjectMeta: metav1.ObjectMeta{Name: "girus-control-plane"}, Usage: usage("1", "2Gi")},
+		}}, nil
+	})
+	metrics.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &metricsv1beta1.PodMetricsList{Items: []metricsv1beta1.PodMetrics{{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-linux-1", Namespace: "aluno"},
+			Containers: []metricsv1beta1.ContainerMetrics{{Name: "linux-lab", Usage: usage("490m", "64Mi")}},
+		}}}, nil
+	})
+	client, _, _ := k8sfake.NewClient(node, config)
+	env := newTestEnv(t, client.WithMetrics(metrics))
+	scriptGirusCluster(env.runner)
+
+	out, err := env.run(t, "status", "--provider", "kind", "-o", "json")
+	if err != nil {
+		t.Fatalf("status: %v\n%s", err, env.stderr)
+	}
+	var doc StatusDocument
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("saída não é JSON: %v\n%s", err, out)
+	}
+
+	res := doc.Resources
+	if !res.MetricsAvailable || res.CPU != "1/4 (25%)" || res.Memory != "2Gi/8Gi (25%)" {
+		t.Errorf("resumo = %+v", res)
+	}
+	want := PodResourceInfo{Namespace: "aluno", Name: "lab-linux-1", Lab: true, CPUMillicores: 490, MemoryBytes: 64 << 20, CPULimitMillicores: 500, MemoryLimitBytes: 256 << 20, NearLimit: true}
+	if len(res.Pods) != 1 || res.Pods[0] != want {
+		t.Errorf("pods = %+v", res.Pods)
+	}
+	if res.LabLimits == nil || res.LabLimits.CPULimit != "500m" {
+		t.Errorf("labLimits = %+v", res.LabLimits)
+	}
+	if env.runner.Ran("kubectl --context kind-girus top") {
+		t.Error("o status não deve executar kubectl top")
+	}
+}
+
+func TestStatusResourcesWithoutMetricsServer(t *testing.T) {
+	metrics := metricsfake.NewSimpleClientset()
+	metrics.PrependReactor("list", "*", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "metrics.k8s.io", Resource: "nodes"}, "")
+	})
+	client, _, _ := k8sfake.NewClient()
+	env := newTestEnv(t, client.WithMetrics(metrics))
+	scriptGirusCluster(env.runner)
+
+	out, err := env.run(t, "status", "--provider", "kind")
+	if err != nil {
+		t.Fatalf("status: %v\n%s", err, env.stderr)
+	}
+	for _, want := range []string{"metrics-server não instalado", "girus status --install-metrics-server"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("saída sem %q:\n%s", want, out)
+		}
+	}
+
+	out, err = env.run(t, "status", "--provider", "kind", "-o", "json")
+	if err != nil {
+		t.Fatalf("status -o json: %v\n%s", err, env.stderr)
+	}
+	var doc StatusDocument
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("saída não é JSON: %v\n%s", err, out)
+	}
+	if doc.Resources.MetricsAvailable || doc.Resources.CPU != "" || doc.Resources.Memory != "" {
+		t.Errorf("resources = %+v, esperado sem o resumo", doc.Resources)
+	}
+}
+
+func TestStatusInstallMetricsServer(t *testing.T) {
+	var downloads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		fmt.Fprint(w, "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: metrics-server\n  namespace: kube-system\nspec:\n  template:\n    spec:\n      containers:\n        - name: metrics-server\n")
+	}))
+	defer server.Close()
+	prev := k8s.MetricsServerManifestURL
+	k8s.MetricsServerManifestURL = server.URL
+	defer func() { k8s.MetricsServerManifestURL = prev }()
+
+	metrics := metricsfake.NewSimpleClientset()
+	metrics.PrependReactor("list", "*", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "metrics.k8s.io", Resource: "nodes"}, "")
+	})
+	client, _, dynamicClient := k8sfake.NewClient()
+	env := newTestEnv(t, client.WithMetrics(metrics))
+	scriptGirusCluster(env.runner)
+	stdinIsTerminal = func() bool { return true }
+
+	// O --yes não confirma a instalação do metrics-server
+	if out, err := env.run(t, "status", "--provider", "kind", "--yes"); err != nil {
+		t.Fatalf("status --yes: %v\n%s", err, out)
+	}
+	if downloads != 0 {
+		t.Fatal("o status --yes instalou o metrics-server")
+	}
+
+	if out, err := env.run(t, "status", "--provider", "kind", "--install-metrics-server"); err != nil || !strings.Contains(out, "metrics-server instalado") {
+		t.Fatalf("status --install-metrics-server: %v\n%s", err, out)
+	}
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	if _, err := dynamicClient.Tracker().Get(gvr, "kube-system", "metrics-server"); err != nil {
+		t.Errorf("metrics-server não aplicado: %v", err)
+	}
+}
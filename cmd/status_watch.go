@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/fatih/color"
+)
+
+// Sequências ANSI da tela cheia do girus status --watch
+const (
+	enterFullScreen = "\033[?1049h\033[?25l"
+	leaveFullScreen = "\033[?25h\033[?1049l"
+	clearScreen     = "\033[H\033[2J"
+)
+
+// watchSection é uma seção da tela do girus status --watch
+type watchSection struct {
+	title  string
+	header string
+	rows   []watchRow
+	// empty é exibido quando a seção não tem linhas
+	empty string
+}
+
+// watchRow é uma linha de uma seção. A chave identifica a linha entre duas
+// telas para destacar o que mudou.
+type watchRow struct {
+	key  string
+	text string
+}
+
+// watchStatus acompanha o namespace do GIRUS com os watches do client-go e
+// redesenha o status a cada mudança, até o contexto terminar (Ctrl-C). O
+// estado dos port-forwards do girus connect é consultado a cada intervalo.
+// Na tela cheia o status ocupa o terminal e é redesenhado a cada intervalo;
+// sem ela uma nova tela é impressa apenas quando algo muda.
+func watchStatus(ctx context.Context, out io.Writer, fullScreen bool, interval time.Duration) error {
+	red := color.New(color.FgRed).SprintFunc()
+
+	client, err := newKubernetesClient()
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao criar o cliente Kubernetes", "Error al crear el cliente Kubernetes"), err)
+	}
+	watcher, err := client.WatchStatus(ctx, k8s.Namespace())
+	var permErr *k8s.PermissionError
+	if errors.As(err, &permErr) {
+		return fmt.Errorf("%s %w\n%s", red(common.T("ERRO:", "ERROR:")), err,
+			common.T("Peça ao administrador do cluster as permissões acima ou use outro contexto com --context.", "Solicite al administrador del clúster los permisos anteriores o use otro contexto con --context."))
+	}
+	if err == nil {
+		err = watcher.Start(ctx)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("%s %w", red(common.T("ERRO:", "ERROR:")), err)
+	}
+
+	if fullScreen {
+		fmt.Fprint(out, enterFullScreen)
+		defer fmt.Fprint(out, leaveFullScreen)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// shown é a última tela com mudanças e previous a anterior a ela; as
+	// linhas destacadas são as que mudaram entre as duas
+	var shown, previous []watchSection
+	for {
+		connect, _ := k8s.QueryConnect()
+		sections := statusSections(watcher.Snapshot(), connect)
+		changed := !reflect.DeepEqual(sections, shown)
+		if changed {
+			previous, shown = shown, sections
+		}
+
+		switch {
+		case fullScreen:
+			fmt.Fprint(out, clearScreen)
+			renderStatusWatch(out, shown, previous, time.Now())
+		case changed:
+			renderStatusWatch(out, shown, previous, time.Now())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-watcher.Changes():
+		case <-ticker.C:
+		}
+	}
+}
+
+// statusSections monta as seções da tela a partir do estado do cluster e dos
+// port-forwards
+func statusSections(snap k8s.Snapshot, connect *k8s.ConnectStatus) []watchSection {
+	components := watchSection{title: common.T("Componentes da Aplicação:", "Componentes de la Aplicación:")}
+	for _, c := range snap.Components {
+		state := c.Summary()
+		if !c.Ready {
+			state = color.YellowString(state)
+		}
+		components.rows = append(components.rows, watchRow{key: c.Name, text: fmt.Sprintf("%-16s %s", c.Name, state)})
+	}
+
+	pods := watchSection{
+		title:  common.T("Pods:", "Pods:"),
+		header: fmt.Sprintf("%-40s %-7s %-20s %-9s %s", "NOME", "PRONTO", "STATUS", "RESTARTS", "NÓ"),
+		empty:  common.T("Nenhum", "Ninguno"),
+	}
+	for _, p := range snap.Pods {
+		pods.rows = append(pods.rows, watchRow{key: p.Name, text: fmt.Sprintf("%-40s %-7s %-20s %-9d %s", p.Name, p.Ready, p.Status, p.Restarts, p.Node)})
+	}
+
+	services := watchSection{
+		title:  common.T("Serviços:", "Servicios:"),
+		header: fmt.Sprintf("%-20s %-10s %-15s %-15s %s", "NOME", "TIPO", "CLUSTER-IP", "PORTAS", "ENDPOINTS"),
+		empty:  common.T("Nenhum", "Ninguno"),
+	}
+	for _, s := range snap.Services {
+		endpoints := fmt.Sprintf("%d", s.Endpoints)
+		if s.Endpoints == 0 {
+			endpoints = color.YellowString(common.T("0 (sem pods prontos)", "0 (sin pods listos)"))
+		}
+		services.rows = append(services.rows, watchRow{key: s.Name, text: fmt.Sprintf("%-20s %-10s %-15s %-15s %s", s.Name, s.Type, s.ClusterIP, s.Ports, endpoints)})
+	}
+
+	forwards := watchSection{
+		title: common.T("Port-Forwards (girus connect):", "Port-Forwards (girus connect):"),
+		empty: common.T("Nenhum (use 'girus connect' para acessar o GIRUS localmente)", "Ninguno (use 'girus connect' para acceder a GIRUS localmente)"),
+	}
+	if connect != nil {
+		for _, pf := range connect.Forwards {
+			state := color.GreenString(common.T("conectado", "conectado"))
+			if !pf.Connected {
+				state = color.YellowString(common.T("reconectando", "reconectando"))
+				if pf.LastError != "" {
+					state += ": " + pf.LastError
+				}
+			}
+			forwards.rows = append(forwards.rows, watchRow{key: pf.Service, text: fmt.Sprintf("%-35s %s", pf.PortForward.String(), state)})
+		}
+	}
+
+	labs := watchSection{
+		title: common.T("Laboratórios Instalados:", "Laboratorios Instalados:"),
+		empty: common.T("Nenhum", "Ninguno"),
+	}
+	for _, l := range snap.Labs {
+		text := l.Name
+		if l.Title != "" {
+			text += " - " + l.Title
+		}
+		labs.rows = append(labs.rows, watchRow{key: l.Name, text: text})
+	}
+
+	nodes := watchSection{
+		title:  common.T("Recursos dos Nós (requests):", "Recursos de los Nodos (requests):"),
+		header: fmt.Sprintf("%-30s %-10s %-24s %s", "NÓ", "STATUS", "CPU", common.T("MEMÓRIA", "MEMORIA")),
+	}
+	for _, n := range snap.Nodes {
+		state := "Ready"
+		if !n.Ready {
+			state = color.RedString("NotReady")
+		}
+		cpu := fmt.Sprintf("%dm/%dm (%d%%)", n.CPURequested, n.CPUAllocatable, percent(n.CPURequested, n.CPUAllocatable))
+		memory := fmt.Sprintf("%s/%s (%d%%)",
+			formatBytes(n.MemoryRequested), formatBytes(n.MemoryAllocatable),
+			percent(n.MemoryRequested, n.MemoryAllocatable))
+		nodes.rows = append(nodes.rows, watchRow{key: n.Name, text: fmt.Sprintf("%-30s %-10s %-24s %s", n.Name, state, cpu, memory)})
+	}
+
+	events := watchSection{
+		title: common.T("Eventos de Warning Recentes:", "Eventos de Warning Recientes:"),
+		empty: common.T("Nenhum", "Ninguno"),
+	}
+	for _, ev := range snap.Events {
+		text := fmt.Sprintf("%s %s %s: %s", ev.Time.Local().Format("15:04:05"), ev.Object, color.RedString(ev.Reason), ev.Message)
+		if ev.Count > 1 {
+			text += fmt.Sprintf(" (x%d)", ev.Count)
+		}
+		events.rows = append(events.rows, watchRow{key: ev.Object + "/" + ev.Reason, text: text})
+	}
+
+	sections := []watchSection{components, pods, services, forwards, labs}
+	// Sem acesso aos nós e aos pods do cluster a seção de recursos é omitida
+	if snap.NodeAccess {
+		sections = append(sections, nodes)
+	}
+	return append(sections, events)
+}
+
+// percent calcula a porcentagem de used em relação a total
+func percent(used, total int64) int64 {
+	if total == 0 {
+		return 0
+	}
+	return used * 100 / total
+}
+
+// renderStatusWatch escreve a tela. Linhas novas ou alteradas em relação à
+// tela anterior são marcadas com "*" e as removidas com "-"; sem tela anterior
+// nada é destacado.
+func renderStatusWatch(out io.Writer, sections, previous []watchSection, at time.Time) {
+	headerColor := color.New(color.FgCyan, color.Bold).SprintFunc()
+	highlight := color.New(color.FgYellow, color.Bold).SprintFunc()
+	removed := color.New(color.FgRed, color.CrossedOut).SprintFunc()
+
+	fmt.Fprintln(out, strings.Repeat("─", 80))
+	fmt.Fprintf(out, "%s  %s  %s\n", headerColor(common.T("GIRUS STATUS", "GIRUS ESTADO")), k8s.Namespace(), at.Format("15:04:05"))
+	fmt.Fprintln(out, strings.Repeat("─", 80))
+
+	for i, section := range sections {
+		var before map[string]string
+		if i < len(previous) {
+			before = map[string]string{}
+			for _, row := range previous[i].rows {
+				before[row.key] = row.text
+			}
+		}
+
+		fmt.Fprintln(out, "\n"+headerColor(section.title))
+		if len(section.rows) == 0 && section.empty != "" {
+			fmt.Fprintf(out, "   %s\n", section.empty)
+		} else if section.header != "" {
+			fmt.Fprintf(out, "   %s\n", color.CyanString(section.header))
+		}
+		for _, row := range section.rows {
+			if text, ok := before[row.key]; before != nil && (!ok || text != row.text) {
+				fmt.Fprintf(out, " %s %s\n", highlight("*"), highlight(row.text))
+				continue
+			}
+			fmt.Fprintf(out, "   %s\n", row.text)
+		}
+		if i < len(previous) {
+			for _, row := range previous[i].rows {
+				if !hasRow(section, row.key) {
+					fmt.Fprintf(out, " %s %s\n", color.RedString("-"), removed(row.text))
+				}
+			}
+		}
+	}
+	fmt.Fprintln(out, "\n"+common.T("Ctrl-C para sair", "Ctrl-C para salir"))
+}
+
+func hasRow(section watchSection, key string) bool {
+	for _, row := range section.rows {
+		if row.key == key {
+			return true
+		}
+	}
+	return false
+}
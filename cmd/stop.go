@@ -1,10 +1,8 @@
 package cmd
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/badtuxx/girus-cli/internal/common"
@@ -24,39 +22,37 @@ var (
 )
 
 var stopCmd = &cobra.Command{
-	Use:   "stop",
-	Short: common.T("Parar o ambiente do GIRUS", "Detener el entorno de GIRUS"),
-	Long:  common.T("Parar o ambiente do GIRUS CLI, removendo todos os recursos criados pelo GIRUS CLI.", "Detener el entorno del GIRUS CLI eliminando todos los recursos creados por el GIRUS CLI."),
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:          "stop",
+	Short:        common.T("Parar o ambiente do GIRUS", "Detener el entorno de GIRUS"),
+	Long:         common.T("Parar o ambiente do GIRUS CLI, removendo todos os recursos criados pelo GIRUS CLI.", "Detener el entorno del GIRUS CLI eliminando todos los recursos creados por el GIRUS CLI."),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Printf(common.T("%s Você está prestes a parar o %s e o %s no cluster %s.\n",
 			"%s Está a punto de detener %s y %s en el cluster %s.\n"),
 			yellow(common.T("AVISO:", "AVISO:")), magenta("frontend"), magenta("backend"), magenta(clusterName))
-		fmt.Print(common.T("Deseja continuar? [s/N]: ", "¿Desea continuar? [s/N]: "))
 
-		reader := bufio.NewReader(os.Stdin)
-		confirmStr, _ := reader.ReadString('\n')
-		confirm := strings.TrimSpace(strings.ToLower(confirmStr))
-
-		if confirm != "s" && confirm != "sim" && confirm != "y" && confirm != "yes" {
+		ok, err := confirm(common.T("Deseja continuar?", "¿Desea continuar?"), false)
+		if err != nil {
+			return err
+		}
+		if !ok {
 			fmt.Println(common.T("Operação cancelada pelo usuário.", "Operación cancelada por el usuario."))
-			return
+			return nil
 		}
 		// Define os nomes dos deployments
 		frontendDeploymentName := "girus-frontend"
 		backendDeploymentName := "girus-backend"
 		// Criando um client para interagir com o cluster do Kubernetes
-		client, err := k8s.NewKubernetesClient()
+		client, err := newKubernetesClient()
 		if err != nil {
-			fmt.Printf("%s %s: %v\n", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao criar cliente Kubernetes", "Error al crear cliente de Kubernetes"), err)
-			return
+			return fmt.Errorf("%s %s: %w", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao criar cliente Kubernetes", "Error al crear cliente de Kubernetes"), err)
 		}
 
-		ctx := context.Background()
+		ctx := cmd.Context()
 		// Pega todos os pods do namespace do girus
-		pods, err := client.ListRunningPods(ctx, "girus")
+		pods, err := client.ListRunningPods(ctx, k8s.Namespace())
 		if err != nil {
-			fmt.Printf("%s %s: %v\n", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao tentar pegar a lista de pods", "Error al obtener la lista de pods"), err)
-			return
+			return fmt.Errorf("%s %s: %w", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao tentar pegar a lista de pods", "Error al obtener la lista de pods"), err)
 		}
 
 		// Pega o nome dos pods do frontend e do backend
@@ -71,11 +67,10 @@ var stopCmd = &cobra.Command{
 		}
 
 		// Verifica se o backend está em execução, se sim, parar o deploy e remover o serviço
-		if isRunning, _ := client.IsPodRunning(ctx, "girus", backendPod); isRunning {
+		if isRunning, _ := client.IsPodRunning(ctx, k8s.Namespace(), backendPod); isRunning {
 			err := deleteDeployment(client, ctx, backendDeploymentName)
 			if err != nil {
-				fmt.Printf("falha ao tentar parar o deploy do backend do GIRUS: %v\n", err)
-				return
+				return fmt.Errorf("%s %s: %w", red(common.T("ERRO:", "ERROR:")), common.T("falha ao tentar parar o deploy do backend do GIRUS", "fallo al intentar detener el deploy del backend de GIRUS"), err)
 			}
 			fmt.Println("✅ Backend parado com sucesso.")
 
@@ -84,41 +79,22 @@ var stopCmd = &cobra.Command{
 		}
 
 		// Verifica se o frontend está em execução, se sim, parar o deploy e remover o serviço
-		if isRunning, _ := client.IsPodRunning(ctx, "girus", frontendPod); isRunning {
+		if isRunning, _ := client.IsPodRunning(ctx, k8s.Namespace(), frontendPod); isRunning {
 			err := deleteDeployment(client, ctx, frontendDeploymentName)
 			if err != nil {
-				fmt.Printf("%s %s: %v\n", red(common.T("ERRO:", "ERROR:")), common.T("falha ao tentar parar o deploy do frontend do GIRUS", "fallo al intentar detener el deploy del frontend de GIRUS"), err)
-				return
+				return fmt.Errorf("%s %s: %w", red(common.T("ERRO:", "ERROR:")), common.T("falha ao tentar parar o deploy do frontend do GIRUS", "fallo al intentar detener el deploy del frontend de GIRUS"), err)
 			}
 			fmt.Println("✅ " + common.T("Frontend parado com sucesso.", "Frontend detenido con éxito."))
 		} else {
 			fmt.Println("⚠️ " + common.T("O frontend não está em execução..", "El frontend no está en ejecución."))
 		}
+		return nil
 	},
 }
 
 func deleteDeployment(client *k8s.KubernetesClient, ctx context.Context, deploymentName string) error {
-	err := client.StopDeployAndWait(ctx, "girus", deploymentName)
-	if err != nil {
-		_, err := fmt.Fprintf(os.Stderr, "%s %s: %v\n", red(common.T("ERRO:", "ERROR:")), common.T("Erro ao tentar parar o deploy", "Error al intentar detener el deploy"), err)
-		if err != nil {
-			return err
-		}
-
-		fmt.Printf(common.T("%s Você quer forçar a parada do deployment %s?\n",
-			"%s ¿Desea forzar la detención del deployment %s?\n"), yellow(common.T("AVISO:", "AVISO:")), magenta(deploymentName))
-		fmt.Print(common.T("Deseja continuar? [s/N]: ", "¿Desea continuar? [s/N]: "))
-
-		reader := bufio.NewReader(os.Stdin)
-		confirmStr, _ := reader.ReadString('\n')
-		confirm := strings.TrimSpace(strings.ToLower(confirmStr))
-
-		if confirm != "s" && confirm != "sim" && confirm != "y" && confirm != "yes" {
-			fmt.Println(common.T("Operação cancelada pelo usuário.", "Operación cancelada por el usuario."))
-			return err
-		}
-		return err
+	if err := client.StopDeployAndWait(ctx, k8s.Namespace(), deploymentName); err != nil {
+		return fmt.Errorf("%s %s: %w", common.T("Erro ao tentar parar o deploy", "Error al intentar detener el deploy"), magenta(deploymentName), err)
 	}
-
 	return nil
 }
@@ -3,16 +3,16 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/badtuxx/girus-cli/internal/common"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
-	"strconv"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
+	"github.com/badtuxx/girus-cli/internal/common"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -55,7 +55,7 @@ compatibilidad con las nuevas funciones.`),
 		fmt.Println("\n" + headerColor(common.T("Verificando atualizações...", "Verificando actualizaciones...")))
 		latestCliVersion, err := GetLatestGitHubVersion(cliRepo)
 		if err != nil {
-			return fmt.Errorf("%s %s: %v", red(common.T("ERRO:", "ERROR:")), common.T("erro ao verificar última versão da CLI", "error al verificar la última versión de la CLI"), err)
+			return fmt.Errorf("%s %s: %w", red(common.T("ERRO:", "ERROR:")), common.T("erro ao verificar última versão da CLI", "error al verificar la última versión de la CLI"), err)
 		}
 
 		fmt.Printf("%s: %s\n", bold(common.T("Última versão disponível", "Última versión disponible")), magenta(latestCliVersion))
@@ -69,11 +69,12 @@ compatibilidad con las nuevas funciones.`),
 		}
 
 		// Confirmar atualização
-		fmt.Printf("\n%s (%s). %s ",
-			yellow(common.T("Nova versão disponível", "Nueva versión disponible")), magenta(latestCliVersion), common.T("Deseja atualizar? (S/n):", "¿Desea actualizar? (S/n):"))
-		var response string
-		fmt.Scanln(&response)
-		if strings.ToLower(response) != "s" && response != "" {
+		fmt.Printf("\n%s (%s).\n", yellow(common.T("Nova versão disponível", "Nueva versión disponible")), magenta(latestCliVersion))
+		ok, err := confirm(common.T("Deseja atualizar?", "¿Desea actualizar?"), true)
+		if err != nil {
+			return err
+		}
+		if !ok {
 			fmt.Println(yellow(common.T("Atualização cancelada.", "Actualización cancelada.")))
 			return nil
 		}
@@ -81,31 +82,28 @@ compatibilidad con las nuevas funciones.`),
 		// Atualizar CLI
 		fmt.Println("\n" + headerColor(common.T("Atualizando CLI...", "Actualizando CLI...")))
 		if err := downloadAndInstall(latestCliVersion); err != nil {
-			return fmt.Errorf("%s %s: %v", red(common.T("ERRO:", "ERROR:")), common.T("erro ao atualizar CLI", "error al actualizar la CLI"), err)
+			return fmt.Errorf("%s %s: %w", red(common.T("ERRO:", "ERROR:")), common.T("erro ao atualizar CLI", "error al actualizar la CLI"), err)
 		}
 		fmt.Printf("%s %s %s %s!\n",
 			green(common.T("SUCESSO:", "ÉXITO:")), common.T("CLI atualizada com sucesso para a versão", "CLI actualizada con éxito a la versión"), magenta(latestCliVersion), "")
 
 		// Perguntar se deseja recriar o cluster
-		fmt.Print("\n" + yellow(common.T("Deseja recriar o cluster para garantir compatibilidade com as novas features? (S/n): ", "¿Desea recrear el cluster para garantizar compatibilidad con las nuevas funcionalidades? (S/n): ")))
-		fmt.Scanln(&response)
-		if strings.ToLower(response) == "s" || response == "" {
+		fmt.Println()
+		recreate, err := confirm(yellow(common.T("Deseja recriar o cluster para garantir compatibilidade com as novas features?", "¿Desea recrear el cluster para garantizar compatibilidad con las nuevas funcionalidades?")), true)
+		if err != nil {
+			return err
+		}
+		if recreate {
 			fmt.Println("\n" + headerColor(common.T("Recriando o cluster...", "Recreando el cluster...")))
 
-			// Executar o comando delete
-			deleteCmd := exec.Command("girus", "delete")
-			deleteCmd.Stdout = os.Stdout
-			deleteCmd.Stderr = os.Stderr
-			if err := deleteCmd.Run(); err != nil {
-				return fmt.Errorf("%s %s: %v", red(common.T("ERRO:", "ERROR:")), common.T("erro ao deletar o cluster", "error al eliminar el cluster"), err)
+			// O novo binário recria o cluster; a confirmação já foi dada acima
+			ctx := cmd.Context()
+			if err := runner.Run(ctx, os.Stdout, "girus", "delete", "cluster", "--yes"); err != nil {
+				return fmt.Errorf("%s %s: %w", red(common.T("ERRO:", "ERROR:")), common.T("erro ao deletar o cluster", "error al eliminar el cluster"), err)
 			}
 
-			// Executar o comando create
-			createCmd := exec.Command("girus", "create")
-			createCmd.Stdout = os.Stdout
-			createCmd.Stderr = os.Stderr
-			if err := createCmd.Run(); err != nil {
-				return fmt.Errorf("%s %s: %v", red(common.T("ERRO:", "ERROR:")), common.T("erro ao criar o cluster", "error al crear el cluster"), err)
+			if err := runner.Run(ctx, os.Stdout, "girus", "create", "cluster", "--yes"); err != nil {
+				return fmt.Errorf("%s %s: %w", red(common.T("ERRO:", "ERROR:")), common.T("erro ao criar o cluster", "error al crear el cluster"), err)
 			}
 
 			fmt.Println("\n" + green(common.T("Cluster recriado com sucesso!", "¡Cluster recreado con éxito!")))
@@ -144,34 +142,19 @@ func GetLatestGitHubVersion(repo string) (string, error) {
 	return strings.TrimPrefix(release.TagName, "v"), nil
 }
 
-// IsNewerVersion compara duas versões semânticas
-// retorna TRUE se v1 é MAIS NOVA que v2
+// IsNewerVersion compara duas versões semânticas e retorna true se v1 é mais
+// nova que v2. Uma versão v2 inválida (como "dev" ou vazia) é considerada mais
+// antiga que qualquer versão válida.
 func IsNewerVersion(v1, v2 string) bool {
-	// Remove prefixo 'v' se existir e divide as versões
-	v1 = strings.TrimPrefix(v1, "v")
-	v2 = strings.TrimPrefix(v2, "v")
-	parts1 := strings.Split(v1, ".")
-	parts2 := strings.Split(v2, ".")
-
-	// Comparar cada parte como inteiro (major, minor, patch)
-	for i := 0; i < 3; i++ {
-		var p1, p2 int
-		if i < len(parts1) {
-			p1, _ = strconv.Atoi(parts1[i])
-		}
-		if i < len(parts2) {
-			p2, _ = strconv.Atoi(parts2[i])
-		}
-		if p1 > p2 {
-			return true
-		}
-		if p1 < p2 {
-			return false
-		}
+	newer, err := semver.NewVersion(v1)
+	if err != nil {
+		return false
 	}
-
-	// São iguais
-	return false
+	current, err := semver.NewVersion(v2)
+	if err != nil {
+		return true
+	}
+	return newer.GreaterThan(current)
 }
 
 // downloadAndInstall baixa e instala a nova versão da CLI
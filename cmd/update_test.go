@@ -0,0 +1,24 @@
+package cmd
+
+import "testing"
+
+func TestIsNewerVersion(t *testing.T) {
+	tests := []struct {
+		v1, v2 string
+		want   bool
+	}{
+		{"0.4.0", "0.3.9", true},
+		{"v0.10.0", "0.9.0", true},
+		{"1.2", "1.2.0", false},
+		{"1.0.0", "1.0.0-rc.1", true},
+		{"1.0.0-rc.1", "1.0.0", false},
+		{"0.3.0", "dev", true},
+		{"0.3.0", "", true},
+		{"invalida", "0.3.0", false},
+	}
+	for _, tt := range tests {
+		if got := IsNewerVersion(tt.v1, tt.v2); got != tt.want {
+			t.Errorf("IsNewerVersion(%q, %q) = %v, esperado %v", tt.v1, tt.v2, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package cluster gera a configuração dos clusters locais usados pelo GIRUS
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/badtuxx/girus-cli/internal/templates"
+)
+
+// Portas do nó usadas pelos serviços NodePort do GIRUS (ver
+// internal/templates/cluster/nodeport-services.yaml)
+const (
+	BackendNodePort  = 30080
+	FrontendNodePort = 30000
+)
+
+// Portas do host mapeadas para os NodePorts com --host-port
+const (
+	BackendHostPort  = 8080
+	FrontendHostPort = 8000
+)
+
+// DefaultKindNodeImage é o repositório das imagens de nó do kind
+const DefaultKindNodeImage = "kindest/node"
+
+var k8sVersionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+$`)
+
+// KindOptions são as opções do girus create cluster que definem a topologia do
+// cluster kind
+type KindOptions struct {
+	Name string
+	// Nodes é o total de nós: um control-plane e Nodes-1 workers
+	Nodes int
+	// K8sVersion seleciona a imagem kindest/node (ex.: v1.33.1)
+	K8sVersion string
+	// HostPort mapeia as portas 8000 e 8080 do host para o nó, dispensando o port-forward
+	HostPort bool
+	// ListenAddress é o endereço do host usado nos mapeamentos de porta
+	ListenAddress string
+	// Mounts no formato caminho-do-host:caminho-no-nó
+	Mounts []string
+	// RegistryMirrors no formato registro=endpoint (ex.: docker.io=http://localhost:5000)
+	RegistryMirrors []string
+}
+
+// PortMapping mapeia uma porta do host para uma porta do nó do kind
+type PortMapping struct {
+	ContainerPort int
+	HostPort      int
+	ListenAddress string
+}
+
+// Mount monta um diretório do host nos nós do kind
+type Mount struct {
+	HostPath      string
+	ContainerPath string
+}
+
+// RegistryMirror configura um espelho de registro no containerd dos nós
+type RegistryMirror struct {
+	Registry string
+	Endpoint string
+}
+
+// KindConfig são os dados usados para renderizar o template kind-config.yaml.tmpl
+type KindConfig struct {
+	Name            string
+	Image           string
+	Workers         []struct{}
+	PortMappings    []PortMapping
+	Mounts          []Mount
+	RegistryMirrors []RegistryMirror
+}
+
+// NewKindConfig valida as opções e monta a configuração do cluster
+func NewKindConfig(opts KindOptions) (*KindConfig, error) {
+	if opts.Name == "" {
+		return nil, fmt.Errorf("o nome do cluster é obrigatório")
+	}
+	if opts.Nodes < 1 {
+		return nil, fmt.Errorf("o cluster precisa de pelo menos 1 nó (obtido %d)", opts.Nodes)
+	}
+
+	cfg := &KindConfig{Name: opts.Name, Workers: make([]struct{}, opts.Nodes-1)}
+
+	if opts.K8sVersion != "" {
+		version, err := k8sVersion(opts.K8sVersion)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Image = DefaultKindNodeImage + ":" + version
+	}
+
+	if opts.HostPort {
+		address := opts.ListenAddress
+		if address == "" {
+			address = "127.0.0.1"
+		}
+		cfg.PortMappings = []PortMapping{
+			{ContainerPort: FrontendNodePort, HostPort: FrontendHostPort, ListenAddress: address},
+			{ContainerPort: BackendNodePort, HostPort: BackendHostPort, ListenAddress: address},
+		}
+	}
+
+	for _, m := range opts.Mounts {
+		// O último ":" separa os caminhos, permitindo caminhos do Windows (C:\...)
+		i := strings.LastIndex(m, ":")
+		host, container := m[:max(i, 0)], m[i+1:]
+		if i < 0 || host == "" || !strings.HasPrefix(container, "/") {
+			return nil, fmt.Errorf("montagem inválida %q (esperado caminho-do-host:/caminho/no/nó)", m)
+		}
+		cfg.Mounts = append(cfg.Mounts, Mount{HostPath: host, ContainerPath: container})
+	}
+
+	for _, r := range opts.RegistryMirrors {
+		registry, endpoint, ok := strings.Cut(r, "=")
+		if !ok || registry == "" || !strings.Contains(endpoint, "://") {
+			return nil, fmt.Errorf("espelho de registro inválido %q (esperado registro=http://endpoint)", r)
+		}
+		cfg.RegistryMirrors = append(cfg.RegistryMirrors, RegistryMirror{Registry: registry, Endpoint: endpoint})
+	}
+
+	return cfg, nil
+}
+
+// Render gera o YAML do Cluster do kind a partir do template embutido
+func (c *KindConfig) Render() ([]byte, error) {
+	data, err := templates.GetClusterTemplate("kind-config.yaml.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New("kind-config").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("template de configuração do kind inválido: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, c); err != nil {
+		return nil, fmt.Errorf("falha ao gerar a configuração do kind: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderKindConfig valida as opções e gera o YAML de configuração do kind
+func RenderKindConfig(opts KindOptions) ([]byte, error) {
+	cfg, err := NewKindConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Render()
+}
@@ -0,0 +1,120 @@
+package cluster_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/cluster"
+	"sigs.k8s.io/yaml"
+)
+
+// kindCluster é o subconjunto do Cluster do kind verificado nos testes
+type kindCluster struct {
+	Kind                    string   `json:"kind"`
+	APIVersion              string   `json:"apiVersion"`
+	Name                    string   `json:"name"`
+	ContainerdConfigPatches []string `json:"containerdConfigPatches"`
+	Nodes                   []struct {
+		Role              string `json:"role"`
+		Image             string `json:"image"`
+		ExtraPortMappings []struct {
+			ContainerPort int    `json:"containerPort"`
+			HostPort      int    `json:"hostPort"`
+			ListenAddress string `json:"listenAddress"`
+			Protocol      string `json:"protocol"`
+		} `json:"extraPortMappings"`
+		ExtraMounts []struct {
+			HostPath      string `json:"hostPath"`
+			ContainerPath string `json:"containerPath"`
+		} `json:"extraMounts"`
+	} `json:"nodes"`
+}
+
+func render(t *testing.T, opts cluster.KindOptions) kindCluster {
+	t.Helper()
+	data, err := cluster.RenderKindConfig(opts)
+	if err != nil {
+		t.Fatalf("RenderKindConfig: %v", err)
+	}
+	var c kindCluster
+	if err := yaml.UnmarshalStrict(data, &c); err != nil {
+		t.Fatalf("configuração gerada inválida: %v\n%s", err, data)
+	}
+	return c
+}
+
+func TestRenderKindConfigDefault(t *testing.T) {
+	c := render(t, cluster.KindOptions{Name: "girus", Nodes: 1})
+
+	if c.Kind != "Cluster" || c.APIVersion != "kind.x-k8s.io/v1alpha4" || c.Name != "girus" {
+		t.Errorf("cabeçalho inesperado: %+v", c)
+	}
+	if len(c.Nodes) != 1 || c.Nodes[0].Role != "control-plane" {
+		t.Fatalf("nós = %+v", c.Nodes)
+	}
+	if c.Nodes[0].Image != "" || len(c.Nodes[0].ExtraPortMappings) != 0 || len(c.ContainerdConfigPatches) != 0 {
+		t.Errorf("configuração padrão não deveria ter imagem, portas ou patches: %+v", c)
+	}
+}
+
+func TestRenderKindConfigTopology(t *testing.T) {
+	c := render(t, cluster.KindOptions{
+		Name:            "girus",
+		Nodes:           3,
+		K8sVersion:      "1.33.1",
+		HostPort:        true,
+		Mounts:          []string{"/home/aluno/labs:/labs"},
+		RegistryMirrors: []string{"docker.io=http://registry.local:5000"},
+	})
+
+	if len(c.Nodes) != 3 || c.Nodes[0].Role != "control-plane" || c.Nodes[1].Role != "worker" || c.Nodes[2].Role != "worker" {
+		t.Fatalf("topologia inesperada: %+v", c.Nodes)
+	}
+	for _, n := range c.Nodes {
+		if n.Image != "kindest/node:v1.33.1" {
+			t.Errorf("imagem do nó %s = %q", n.Role, n.Image)
+		}
+		if len(n.ExtraMounts) != 1 || n.ExtraMounts[0].HostPath != "/home/aluno/labs" || n.ExtraMounts[0].ContainerPath != "/labs" {
+			t.Errorf("montagens do nó %s = %+v", n.Role, n.ExtraMounts)
+		}
+	}
+
+	ports := c.Nodes[0].ExtraPortMappings
+	if len(ports) != 2 ||
+		ports[0].HostPort != 8000 || ports[0].ContainerPort != cluster.FrontendNodePort ||
+		ports[1].HostPort != 8080 || ports[1].ContainerPort != cluster.BackendNodePort ||
+		ports[0].ListenAddress != "127.0.0.1" {
+		t.Errorf("mapeamentos de porta = %+v", ports)
+	}
+	if len(c.Nodes[1].ExtraPortMappings) != 0 {
+		t.Error("workers não deveriam mapear portas")
+	}
+
+	if len(c.ContainerdConfigPatches) != 1 ||
+		!strings.Contains(c.ContainerdConfigPatches[0], `mirrors."docker.io"`) ||
+		!strings.Contains(c.ContainerdConfigPatches[0], `endpoint = ["http://registry.local:5000"]`) {
+		t.Errorf("patches do containerd = %q", c.ContainerdConfigPatches)
+	}
+}
+
+func TestNewKindConfigInvalid(t *testing.T) {
+	tests := map[string]cluster.KindOptions{
+		"sem nome":       {Nodes: 1},
+		"sem nós":        {Name: "girus"},
+		"versão":         {Name: "girus", Nodes: 1, K8sVersion: "latest"},
+		"montagem":       {Name: "girus", Nodes: 1, Mounts: []string{"/tmp"}},
+		"montagem rel.":  {Name: "girus", Nodes: 1, Mounts: []string{"/tmp:labs"}},
+		"espelho":        {Name: "girus", Nodes: 1, RegistryMirrors: []string{"docker.io"}},
+		"espelho s/ url": {Name: "girus", Nodes: 1, RegistryMirrors: []string{"docker.io=registry.local"}},
+	}
+	for name, opts := range tests {
+		if _, err := cluster.NewKindConfig(opts); err == nil {
+			t.Errorf("%s: esperado erro", name)
+		}
+	}
+
+	cfg, err := cluster.NewKindConfig(cluster.KindOptions{Name: "girus", Nodes: 1, Mounts: []string{`C:\labs:/labs`}})
+	if err != nil || cfg.Mounts[0].HostPath != `C:\labs` {
+		t.Errorf("caminho do Windows: %+v, %v", cfg, err)
+	}
+}
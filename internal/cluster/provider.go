@@ -0,0 +1,104 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/badtuxx/girus-cli/internal/executil"
+)
+
+// Nomes dos providers suportados
+const (
+	ProviderKind     = "kind"
+	ProviderK3d      = "k3d"
+	ProviderMinikube = "minikube"
+	ProviderBYO      = "byo"
+)
+
+// DefaultProvider é o provider usado quando nenhum é configurado
+const DefaultProvider = ProviderKind
+
+// Providers lista os providers suportados
+var Providers = []string{ProviderKind, ProviderK3d, ProviderMinikube, ProviderBYO}
+
+// CreateOptions são as opções de criação de um cluster
+type CreateOptions struct {
+	Name string
+	// KindConfig é a configuração gerada por RenderKindConfig (apenas kind)
+	KindConfig []byte
+	// Nodes é o total de nós do cluster
+	Nodes      int
+	K8sVersion string
+	// HostPort mapeia as portas 8000 e 8080 do host para os NodePorts do GIRUS
+	HostPort bool
+	// Out recebe a saída do comando do provider
+	Out io.Writer
+}
+
+// ClusterProvider cria e gerencia o cluster onde o GIRUS é instalado
+type ClusterProvider interface {
+	// Name retorna o nome do provider
+	Name() string
+	// Create cria o cluster
+	Create(ctx context.Context, opts CreateOptions) error
+	// Delete remove o cluster
+	Delete(ctx context.Context, name string, out io.Writer) error
+	// List lista os clusters do provider
+	List(ctx context.Context) ([]string, error)
+	// Exists verifica se o cluster existe
+	Exists(ctx context.Context, name string) (bool, error)
+	// KubeContext retorna o contexto do kubeconfig do cluster
+	KubeContext(name string) string
+	// LoadImage carrega uma imagem local nos nós do cluster
+	LoadImage(ctx context.Context, name, image string) error
+}
+
+// NewProvider cria o provider pelo nome. Para o provider byo, kubeContext é o
+// contexto do kubeconfig onde o GIRUS será instalado (vazio usa o contexto atual).
+func NewProvider(name string, runner executil.Runner, kubeContext string) (ClusterProvider, error) {
+	if runner == nil {
+		runner = executil.ExecRunner{}
+	}
+	switch strings.ToLower(name) {
+	case "", ProviderKind:
+		return &KindProvider{runner: runner}, nil
+	case ProviderK3d:
+		return &K3dProvider{runner: runner}, nil
+	case ProviderMinikube:
+		return &MinikubeProvider{runner: runner}, nil
+	case ProviderBYO:
+		return &BYOProvider{runner: runner, Context: kubeContext}, nil
+	}
+	return nil, fmt.Errorf("provider de cluster desconhecido %q (disponíveis: %s)", name, strings.Join(Providers, ", "))
+}
+
+// exists verifica se o nome está na lista de clusters do provider
+func exists(ctx context.Context, p ClusterProvider, name string) (bool, error) {
+	clusters, err := p.List(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, c := range clusters {
+		if c == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// k8sVersion normaliza a versão do Kubernetes para o formato vX.Y.Z
+func k8sVersion(version string) (string, error) {
+	if !k8sVersionPattern.MatchString(version) {
+		return "", fmt.Errorf("versão do Kubernetes inválida %q (esperado, por exemplo, v1.33.1)", version)
+	}
+	return "v" + strings.TrimPrefix(version, "v"), nil
+}
+
+func output(out io.Writer) io.Writer {
+	if out == nil {
+		return io.Discard
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/badtuxx/girus-cli/internal/executil"
+)
+
+// BYOProvider ("bring your own") usa um cluster já existente, acessado por um
+// contexto do kubeconfig. Ele não cria nem remove clusters: Create apenas
+// verifica se o contexto existe e o GIRUS é instalado nele.
+type BYOProvider struct {
+	runner executil.Runner
+	// Context é o contexto do kubeconfig; vazio usa o contexto atual
+	Context string
+}
+
+// Name retorna o nome do provider
+func (p *BYOProvider) Name() string { return ProviderBYO }
+
+// Create verifica se o contexto do cluster existente está no kubeconfig
+func (p *BYOProvider) Create(ctx context.Context, opts CreateOptions) error {
+	if opts.KindConfig != nil || opts.Nodes > 1 || opts.K8sVersion != "" || opts.HostPort {
+		return fmt.Errorf("o provider byo usa um cluster existente e não aceita opções de topologia")
+	}
+	kubeContext, err := p.currentContext(ctx)
+	if err != nil {
+		return err
+	}
+	ok, err := exists(ctx, p, kubeContext)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("contexto %q não encontrado no kubeconfig", kubeContext)
+	}
+	return nil
+}
+
+// Delete não é suportado: o cluster não pertence ao GIRUS
+func (p *BYOProvider) Delete(ctx context.Context, name string, out io.Writer) error {
+	return fmt.Errorf("o provider byo não remove clusters existentes")
+}
+
+// List lista os contextos do kubeconfig
+func (p *BYOProvider) List(ctx context.Context) ([]string, error) {
+	out, err := p.runner.Output(ctx, "kubectl", "config", "get-contexts", "-o", "name")
+	if err != nil {
+		return nil, err
+	}
+	return executil.Lines(out), nil
+}
+
+// Exists verifica se o contexto existe no kubeconfig. No provider byo os
+// clusters são identificados pelos contextos.
+func (p *BYOProvider) Exists(ctx context.Context, name string) (bool, error) {
+	return exists(ctx, p, name)
+}
+
+// KubeContext retorna o próprio nome, já que os clusters são os contextos
+func (p *BYOProvider) KubeContext(name string) string { return name }
+
+// LoadImage não é suportado: as imagens devem estar em um registro acessível
+// pelo cluster
+func (p *BYOProvider) LoadImage(ctx context.Context, name, image string) error {
+	return fmt.Errorf("o provider byo não carrega imagens locais; publique %s em um registro acessível pelo cluster", image)
+}
+
+// currentContext retorna o contexto configurado ou o contexto atual do kubeconfig
+func (p *BYOProvider) currentContext(ctx context.Context) (string, error) {
+	if p.Context != "" {
+		return p.Context, nil
+	}
+	out, err := p.runner.Output(ctx, "kubectl", "config", "current-context")
+	if err != nil {
+		return "", fmt.Errorf("nenhum contexto atual no kubeconfig: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
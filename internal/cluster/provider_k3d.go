@@ -0,0 +1,81 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/badtuxx/girus-cli/internal/executil"
+)
+
+// DefaultK3sImage é o repositório das imagens do k3s usadas pelo k3d
+const DefaultK3sImage = "rancher/k3s"
+
+// K3dProvider cria clusters com o k3d (k3s in Docker)
+type K3dProvider struct {
+	runner executil.Runner
+}
+
+// Name retorna o nome do provider
+func (p *K3dProvider) Name() string { return ProviderK3d }
+
+// Create cria o cluster com um servidor e Nodes-1 agentes, sem alterar o
+// contexto atual do kubeconfig
+func (p *K3dProvider) Create(ctx context.Context, opts CreateOptions) error {
+	args := []string{"cluster", "create", opts.Name, "--wait", "--kubeconfig-switch-context=false"}
+	if opts.Nodes > 1 {
+		args = append(args, "--agents", strconv.Itoa(opts.Nodes-1))
+	}
+	if opts.K8sVersion != "" {
+		version, err := k8sVersion(opts.K8sVersion)
+		if err != nil {
+			return err
+		}
+		args = append(args, "--image", DefaultK3sImage+":"+version+"-k3s1")
+	}
+	if opts.HostPort {
+		args = append(args,
+			"-p", fmt.Sprintf("%d:%d@server:0", FrontendHostPort, FrontendNodePort),
+			"-p", fmt.Sprintf("%d:%d@server:0", BackendHostPort, BackendNodePort))
+	}
+	return p.runner.Run(ctx, output(opts.Out), "k3d", args...)
+}
+
+// Delete remove o cluster
+func (p *K3dProvider) Delete(ctx context.Context, name string, out io.Writer) error {
+	return p.runner.Run(ctx, output(out), "k3d", "cluster", "delete", name)
+}
+
+// List lista os clusters do k3d
+func (p *K3dProvider) List(ctx context.Context) ([]string, error) {
+	out, err := p.runner.Output(ctx, "k3d", "cluster", "list", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var clusters []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(out, &clusters); err != nil {
+		return nil, fmt.Errorf("saída inválida do k3d cluster list: %w", err)
+	}
+	names := make([]string, 0, len(clusters))
+	for _, c := range clusters {
+		names = append(names, c.Name)
+	}
+	return names, nil
+}
+
+// Exists verifica se o cluster existe
+func (p *K3dProvider) Exists(ctx context.Context, name string) (bool, error) {
+	return exists(ctx, p, name)
+}
+
+// KubeContext retorna o contexto criado pelo k3d para o cluster
+func (p *K3dProvider) KubeContext(name string) string { return "k3d-" + name }
+
+// LoadImage importa uma imagem do Docker local nos nós do cluster
+func (p *K3dProvider) LoadImage(ctx context.Context, name, image string) error {
+	return p.runner.Run(ctx, io.Discard, "k3d", "image", "import", image, "--cluster", name)
+}
@@ -0,0 +1,86 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/badtuxx/girus-cli/internal/executil"
+)
+
+// KindProvider cria clusters com o kind (Kubernetes in Docker)
+type KindProvider struct {
+	runner executil.Runner
+}
+
+// Name retorna o nome do provider
+func (p *KindProvider) Name() string { return ProviderKind }
+
+// Create cria o cluster com a configuração gerada por RenderKindConfig. Sem
+// configuração, ela é gerada a partir das demais opções. O kind sempre troca o
+// contexto atual do kubeconfig; o contexto anterior é restaurado em seguida.
+func (p *KindProvider) Create(ctx context.Context, opts CreateOptions) error {
+	config := opts.KindConfig
+	if config == nil {
+		var err error
+		config, err = RenderKindConfig(KindOptions{
+			Name:       opts.Name,
+			Nodes:      max(opts.Nodes, 1),
+			K8sVersion: opts.K8sVersion,
+			HostPort:   opts.HostPort,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp("", "girus-kind-*.yaml")
+	if err != nil {
+		return fmt.Errorf("erro ao criar o arquivo de configuração do kind: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(config); err != nil {
+		tmp.Close()
+		return fmt.Errorf("erro ao gravar a configuração do kind: %w", err)
+	}
+	tmp.Close()
+
+	previous, _ := p.runner.Output(ctx, "kubectl", "config", "current-context")
+	if err := p.runner.Run(ctx, output(opts.Out), "kind", "create", "cluster", "--name", opts.Name, "--config", tmp.Name()); err != nil {
+		return err
+	}
+	if current := strings.TrimSpace(string(previous)); current != "" {
+		return p.runner.Run(ctx, io.Discard, "kubectl", "config", "use-context", current)
+	}
+	return nil
+}
+
+// Delete remove o cluster
+func (p *KindProvider) Delete(ctx context.Context, name string, out io.Writer) error {
+	return p.runner.Run(ctx, output(out), "kind", "delete", "cluster", "--name", name)
+}
+
+// List lista os clusters do kind. A mensagem "No kind clusters found." vai para
+// o stderr e não aparece na lista.
+func (p *KindProvider) List(ctx context.Context) ([]string, error) {
+	out, err := p.runner.Output(ctx, "kind", "get", "clusters")
+	if err != nil {
+		return nil, err
+	}
+	return executil.Lines(out), nil
+}
+
+// Exists verifica se o cluster existe
+func (p *KindProvider) Exists(ctx context.Context, name string) (bool, error) {
+	return exists(ctx, p, name)
+}
+
+// KubeContext retorna o contexto criado pelo kind para o cluster
+func (p *KindProvider) KubeContext(name string) string { return "kind-" + name }
+
+// LoadImage carrega uma imagem do Docker local nos nós do cluster
+func (p *KindProvider) LoadImage(ctx context.Context, name, image string) error {
+	return p.runner.Run(ctx, io.Discard, "kind", "load", "docker-image", image, "--name", name)
+}
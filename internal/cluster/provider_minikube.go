@@ -0,0 +1,78 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/badtuxx/girus-cli/internal/executil"
+)
+
+// MinikubeProvider cria clusters com o minikube, um perfil por cluster
+type MinikubeProvider struct {
+	runner executil.Runner
+}
+
+// Name retorna o nome do provider
+func (p *MinikubeProvider) Name() string { return ProviderMinikube }
+
+// Create inicia o perfil do minikube sem alterar o contexto atual do
+// kubeconfig. O mapeamento de portas do host não é suportado; use girus
+// connect para acessar o GIRUS.
+func (p *MinikubeProvider) Create(ctx context.Context, opts CreateOptions) error {
+	if opts.HostPort {
+		return fmt.Errorf("o provider minikube não suporta --host-port")
+	}
+	args := []string{"start", "--profile", opts.Name, "--keep-context"}
+	if opts.Nodes > 1 {
+		args = append(args, "--nodes", strconv.Itoa(opts.Nodes))
+	}
+	if opts.K8sVersion != "" {
+		version, err := k8sVersion(opts.K8sVersion)
+		if err != nil {
+			return err
+		}
+		args = append(args, "--kubernetes-version", version)
+	}
+	return p.runner.Run(ctx, output(opts.Out), "minikube", args...)
+}
+
+// Delete remove o perfil do minikube
+func (p *MinikubeProvider) Delete(ctx context.Context, name string, out io.Writer) error {
+	return p.runner.Run(ctx, output(out), "minikube", "delete", "--profile", name)
+}
+
+// List lista os perfis do minikube, inclusive os inválidos
+func (p *MinikubeProvider) List(ctx context.Context) ([]string, error) {
+	out, err := p.runner.Output(ctx, "minikube", "profile", "list", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var profiles struct {
+		Invalid []struct{ Name string } `json:"invalid"`
+		Valid   []struct{ Name string } `json:"valid"`
+	}
+	if err := json.Unmarshal(out, &profiles); err != nil {
+		return nil, fmt.Errorf("saída inválida do minikube profile list: %w", err)
+	}
+	var names []string
+	for _, profile := range append(profiles.Valid, profiles.Invalid...) {
+		names = append(names, profile.Name)
+	}
+	return names, nil
+}
+
+// Exists verifica se o perfil existe
+func (p *MinikubeProvider) Exists(ctx context.Context, name string) (bool, error) {
+	return exists(ctx, p, name)
+}
+
+// KubeContext retorna o contexto do perfil, que tem o mesmo nome
+func (p *MinikubeProvider) KubeContext(name string) string { return name }
+
+// LoadImage carrega uma imagem local nos nós do perfil
+func (p *MinikubeProvider) LoadImage(ctx context.Context, name, image string) error {
+	return p.runner.Run(ctx, io.Discard, "minikube", "image", "load", image, "--profile", name)
+}
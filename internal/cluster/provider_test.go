@@ -0,0 +1,194 @@
+package cluster_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/cluster"
+	"github.com/badtuxx/girus-cli/internal/executil"
+)
+
+func newProvider(t *testing.T, name string, runner *executil.Fake) cluster.ClusterProvider {
+	t.Helper()
+	p, err := cluster.NewProvider(name, runner, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestNewProviderUnknown(t *testing.T) {
+	if _, err := cluster.NewProvider("docker-desktop", nil, ""); err == nil {
+		t.Error("esperado erro para provider desconhecido")
+	}
+	p, err := cluster.NewProvider("", nil, "")
+	if err != nil || p.Name() != cluster.DefaultProvider {
+		t.Errorf("provider padrão = %v, %v", p, err)
+	}
+}
+
+func TestProviderCommands(t *testing.T) {
+	opts := cluster.CreateOptions{Name: "girus", Nodes: 3, K8sVersion: "1.33.1"}
+	tests := []struct {
+		provider    string
+		create      string
+		delete      string
+		loadImage   string
+		kubeContext string
+	}{
+		{
+			provider:    cluster.ProviderK3d,
+			create:      "k3d cluster create girus --wait --kubeconfig-switch-context=false --agents 2 --image rancher/k3s:v1.33.1-k3s1",
+			delete:      "k3d cluster delete girus",
+			loadImage:   "k3d image import girus/backend:dev --cluster girus",
+			kubeContext: "k3d-girus",
+		},
+		{
+			provider:    cluster.ProviderMinikube,
+			create:      "minikube start --profile girus --keep-context --nodes 3 --kubernetes-version v1.33.1",
+			delete:      "minikube delete --profile girus",
+			loadImage:   "minikube image load girus/backend:dev --profile girus",
+			kubeContext: "girus",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			runner := executil.NewFake()
+			p := newProvider(t, tt.provider, runner)
+			ctx := context.Background()
+
+			if err := p.Create(ctx, opts); err != nil {
+				t.Fatal(err)
+			}
+			if err := p.Delete(ctx, "girus", nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := p.LoadImage(ctx, "girus", "girus/backend:dev"); err != nil {
+				t.Fatal(err)
+			}
+			want := []string{tt.create, tt.delete, tt.loadImage}
+			if !reflect.DeepEqual(runner.Commands(), want) {
+				t.Errorf("comandos = %q, esperado %q", runner.Commands(), want)
+			}
+			if got := p.KubeContext("girus"); got != tt.kubeContext {
+				t.Errorf("KubeContext = %q, esperado %q", got, tt.kubeContext)
+			}
+		})
+	}
+}
+
+func TestKindProviderCreate(t *testing.T) {
+	runner := executil.NewFake()
+	runner.On("kubectl config current-context", executil.Response{Output: "producao\n"})
+	p := newProvider(t, cluster.ProviderKind, runner)
+
+	if err := p.Create(context.Background(), cluster.CreateOptions{Name: "girus", KindConfig: []byte("kind: Cluster\n")}); err != nil {
+		t.Fatal(err)
+	}
+	// O contexto atual do usuário é restaurado depois da criação
+	if len(runner.Commands()) != 3 || !strings.HasPrefix(runner.Commands()[1], "kind create cluster --name girus --config ") || runner.Commands()[2] != "kubectl config use-context producao" {
+		t.Errorf("comandos = %q", runner.Commands())
+	}
+	if p.KubeContext("girus") != "kind-girus" {
+		t.Errorf("KubeContext = %q", p.KubeContext("girus"))
+	}
+}
+
+func TestProviderHostPort(t *testing.T) {
+	runner := executil.NewFake()
+	k3d := newProvider(t, cluster.ProviderK3d, runner)
+	if err := k3d.Create(context.Background(), cluster.CreateOptions{Name: "girus", HostPort: true}); err != nil {
+		t.Fatal(err)
+	}
+	want := "k3d cluster create girus --wait --kubeconfig-switch-context=false -p 8000:30000@server:0 -p 8080:30080@server:0"
+	if runner.Commands()[0] != want {
+		t.Errorf("comando = %q, esperado %q", runner.Commands()[0], want)
+	}
+
+	minikube := newProvider(t, cluster.ProviderMinikube, executil.NewFake())
+	if err := minikube.Create(context.Background(), cluster.CreateOptions{Name: "girus", HostPort: true}); err == nil {
+		t.Error("esperado erro: minikube não suporta --host-port")
+	}
+}
+
+func TestProviderList(t *testing.T) {
+	tests := []struct {
+		provider string
+		command  string
+		output   string
+		want     []string
+	}{
+		{cluster.ProviderKind, "kind get clusters", "girus\nteste\n", []string{"girus", "teste"}},
+		{cluster.ProviderKind, "kind get clusters", "", nil},
+		{cluster.ProviderK3d, "k3d cluster list -o json", `[{"name":"girus","nodes":[]},{"name":"dev"}]`, []string{"girus", "dev"}},
+		{cluster.ProviderMinikube, "minikube profile list -o json", `{"invalid":[{"Name":"quebrado"}],"valid":[{"Name":"girus"}]}`, []string{"girus", "quebrado"}},
+		{cluster.ProviderBYO, "kubectl config get-contexts -o name", "prod\nkind-girus\n", []string{"prod", "kind-girus"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			runner := executil.NewFake()
+			runner.On(tt.command, executil.Response{Output: tt.output})
+			p := newProvider(t, tt.provider, runner)
+
+			got, err := p.List(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("List = %q, esperado %q", got, tt.want)
+			}
+			if len(tt.want) > 0 {
+				if ok, err := p.Exists(context.Background(), tt.want[0]); !ok || err != nil {
+					t.Errorf("Exists(%s) = %v, %v", tt.want[0], ok, err)
+				}
+			}
+			if ok, _ := p.Exists(context.Background(), "inexistente"); ok {
+				t.Error("cluster inexistente encontrado")
+			}
+		})
+	}
+
+	t.Run("erro do comando", func(t *testing.T) {
+		runner := executil.NewFake()
+		runner.Missing("kind")
+		if _, err := newProvider(t, cluster.ProviderKind, runner).Exists(context.Background(), "girus"); err == nil {
+			t.Error("esperado erro")
+		}
+	})
+}
+
+func TestBYOProvider(t *testing.T) {
+	runner := executil.NewFake()
+	runner.On("kubectl config current-context", executil.Response{Output: "prod\n"})
+	runner.On("kubectl config get-contexts -o name", executil.Response{Output: "prod\n"})
+	p := newProvider(t, cluster.ProviderBYO, runner)
+	ctx := context.Background()
+
+	if err := p.Create(ctx, cluster.CreateOptions{Name: "girus"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := p.Create(ctx, cluster.CreateOptions{Name: "girus", Nodes: 3}); err == nil {
+		t.Error("esperado erro com opções de topologia")
+	}
+	if err := p.Delete(ctx, "prod", nil); err == nil {
+		t.Error("o provider byo não deveria remover clusters")
+	}
+	if err := p.LoadImage(ctx, "prod", "girus/backend:dev"); err == nil {
+		t.Error("o provider byo não deveria carregar imagens")
+	}
+	if p.KubeContext("prod") != "prod" {
+		t.Errorf("KubeContext = %q", p.KubeContext("prod"))
+	}
+
+	missing, err := cluster.NewProvider(cluster.ProviderBYO, runner, "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := missing.Create(ctx, cluster.CreateOptions{Name: "girus"}); err == nil {
+		t.Error("esperado erro para contexto inexistente")
+	}
+}
@@ -9,6 +9,13 @@ import (
 
 type Config struct {
 	Language string `yaml:"language"`
+	// Provider é o provider de cluster padrão (kind, k3d, minikube ou byo)
+	Provider string `yaml:"provider"`
+	// Kubeconfig, Context e Namespace selecionam o cluster e o namespace do
+	// GIRUS quando as flags --kubeconfig, --context e --namespace são omitidas
+	Kubeconfig string `yaml:"kubeconfig"`
+	Context    string `yaml:"context"`
+	Namespace  string `yaml:"namespace"`
 }
 
 var configPath string
@@ -0,0 +1,79 @@
+package common
+
+import (
+	"errors"
+	"net"
+	"os/exec"
+)
+
+// Códigos de saída do girus. Os valores são estáveis para que scripts e jobs de
+// CI possam tratar cada falha.
+const (
+	// ExitOK indica sucesso
+	ExitOK = 0
+	// ExitFailure é a falha sem código específico
+	ExitFailure = 1
+	// ExitUsage indica comando, flags ou argumentos inválidos
+	ExitUsage = 2
+	// ExitClusterNotFound indica que o cluster ou o namespace do GIRUS não existe
+	ExitClusterNotFound = 3
+	// ExitBackendUnhealthy indica que o backend não está pronto ou não reiniciou
+	ExitBackendUnhealthy = 4
+	// ExitLabNotFound indica que o laboratório não existe nos repositórios
+	ExitLabNotFound = 5
+	// ExitNetwork indica falha de rede ao acessar repositórios ou o GitHub
+	ExitNetwork = 6
+	// ExitDependencyMissing indica que um binário necessário (docker, podman,
+	// kind, k3d, minikube, kubectl) está ausente ou não está em execução
+	ExitDependencyMissing = 7
+	// ExitInteractionRequired indica que o comando precisava de uma resposta do
+	// usuário, mas a entrada padrão não é um terminal ou --non-interactive foi
+	// informado. Use --yes para confirmar sem interação.
+	ExitInteractionRequired = 8
+	// ExitVerificationFailed indica que o digest de um laboratório ou a
+	// assinatura do índice de um repositório não confere
+	ExitVerificationFailed = 9
+)
+
+// ExitError associa um código de saída a um erro
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// WithExitCode associa o código de saída ao erro. Retorna nil se err for nil.
+func WithExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Code: code, Err: err}
+}
+
+// ExitCode retorna o código de saída do erro: o código associado com
+// WithExitCode, ExitDependencyMissing para binários ausentes, ExitNetwork para
+// falhas de rede e ExitFailure para os demais erros
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return ExitDependencyMissing
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ExitNetwork
+	}
+	return ExitFailure
+}
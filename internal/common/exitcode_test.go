@@ -0,0 +1,36 @@
+package common_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+)
+
+func TestExitCode(t *testing.T) {
+	netErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"sucesso", nil, common.ExitOK},
+		{"genérico", errors.New("falha"), common.ExitFailure},
+		{"código associado", common.WithExitCode(common.ExitLabNotFound, errors.New("lab")), common.ExitLabNotFound},
+		{"código encadeado", fmt.Errorf("ERRO: %w", common.WithExitCode(common.ExitClusterNotFound, errors.New("cluster"))), common.ExitClusterNotFound},
+		{"binário ausente", fmt.Errorf("kind get clusters: %w", exec.ErrNotFound), common.ExitDependencyMissing},
+		{"rede", fmt.Errorf("erro ao acessar repositório: %w", netErr), common.ExitNetwork},
+	}
+	for _, tt := range tests {
+		if got := common.ExitCode(tt.err); got != tt.want {
+			t.Errorf("%s: ExitCode = %d, esperado %d", tt.name, got, tt.want)
+		}
+	}
+
+	if common.WithExitCode(common.ExitNetwork, nil) != nil {
+		t.Error("WithExitCode(nil) deve retornar nil")
+	}
+}
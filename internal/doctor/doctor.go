@@ -0,0 +1,303 @@
+// Package doctor diagnostica o ambiente em que o GIRUS é executado: engine de
+// containers, binários do provider e do kubectl, portas locais, limites do
+// sistema, acesso ao cluster e saúde do backend. Cada verificação resulta em
+// pass, warn ou fail, com uma dica de correção.
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/executil"
+	"github.com/badtuxx/girus-cli/internal/helpers"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+)
+
+// Status é o resultado de uma verificação
+type Status string
+
+// Resultados das verificações
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Check é o resultado de uma verificação do ambiente
+type Check struct {
+	// Name identifica a verificação (por exemplo, "container-engine")
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	// Hint é a sugestão de correção para avisos e falhas
+	Hint string `json:"hint,omitempty"`
+}
+
+// Options configura o diagnóstico
+type Options struct {
+	Runner executil.Runner
+	// Provider é o provider de cluster (kind, k3d, minikube ou byo). O byo usa
+	// um cluster existente e dispensa as verificações do engine e do provider.
+	Provider string
+	// ContainerEngine é o engine usado pelo provider (docker ou podman)
+	ContainerEngine string
+	// FrontendPort e BackendPort são as portas locais do girus connect
+	FrontendPort int
+	BackendPort  int
+	// NewClient cria o cliente do cluster usado na verificação do backend;
+	// vazio, usa k8s.NewKubernetesClient
+	NewClient func() (*k8s.KubernetesClient, error)
+}
+
+// Report é o resultado do diagnóstico
+type Report struct {
+	Checks []Check
+}
+
+// Count retorna o número de verificações com o status informado
+func (r Report) Count(status Status) int {
+	n := 0
+	for _, c := range r.Checks {
+		if c.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed indica se alguma verificação falhou
+func (r Report) Failed() bool {
+	return r.Count(StatusFail) > 0
+}
+
+// Run executa todas as verificações. As verificações do backend só são feitas
+// quando o cluster responde.
+func Run(ctx context.Context, opts Options) Report {
+	if opts.Runner == nil {
+		opts.Runner = executil.ExecRunner{}
+	}
+	if opts.NewClient == nil {
+		opts.NewClient = k8s.NewKubernetesClient
+	}
+
+	var checks []Check
+	if opts.Provider != "byo" {
+		checks = append(checks, containerEngine(ctx, opts), providerBinary(ctx, opts))
+	}
+	checks = append(checks, kubectl(ctx, opts))
+	checks = append(checks,
+		port(opts.FrontendPort, "frontend", fmt.Sprintf("http://localhost:%d", opts.FrontendPort), "--frontend-port"),
+		port(opts.BackendPort, "backend", fmt.Sprintf("http://localhost:%d/api/v1/health", opts.BackendPort), "--backend-port"),
+	)
+	checks = append(checks, systemChecks()...)
+
+	reachable := kubeContext(ctx, opts)
+	checks = append(checks, reachable)
+	if reachable.Status == StatusPass {
+		checks = append(checks, backend(ctx, opts))
+	}
+	return Report{Checks: checks}
+}
+
+// containerEngine verifica se o engine está instalado e respondendo
+func containerEngine(ctx context.Context, opts Options) Check {
+	engine := opts.ContainerEngine
+	if engine == "" {
+		engine = "docker"
+	}
+	check := Check{Name: "container-engine"}
+
+	format := "{{.ServerVersion}}"
+	if engine == "podman" {
+		format = "{{.Version.Version}}"
+	}
+	out, err := opts.Runner.Output(ctx, engine, "info", "--format", format)
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf(common.T("%s não encontrado no PATH", "%s no encontrado en el PATH"), engine)
+		check.Hint = engineInstallHint(engine)
+	case err != nil:
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf(common.T("%s instalado, mas o serviço não responde", "%s instalado, pero el servicio no responde"), engine)
+		check.Hint = engineStartHint(engine)
+	default:
+		check.Status = StatusPass
+		check.Message = fmt.Sprintf(common.T("%s %s em execução", "%s %s en ejecución"), engine, versionOrUnknown(string(out)))
+	}
+	return check
+}
+
+// engineInstallHint retorna as instruções de instalação do engine
+func engineInstallHint(engine string) string {
+	if engine == "podman" {
+		return common.T("Instale o Podman: https://podman.io/docs/installation", "Instale Podman: https://podman.io/docs/installation")
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return common.T("Instale o Docker com 'brew install colima docker' e inicie com 'colima start'", "Instale Docker con 'brew install colima docker' e inícielo con 'colima start'")
+	case "linux":
+		return common.T("Instale o Docker com 'curl -fsSL https://get.docker.com | bash'", "Instale Docker con 'curl -fsSL https://get.docker.com | bash'")
+	}
+	return common.T("Instale o Docker Desktop: https://www.docker.com/products/docker-desktop", "Instale Docker Desktop: https://www.docker.com/products/docker-desktop")
+}
+
+// engineStartHint retorna as instruções para iniciar o engine
+func engineStartHint(engine string) string {
+	switch {
+	case runtime.GOOS == "darwin" && engine == "podman":
+		return common.T("Inicie a máquina do Podman com 'podman machine start'", "Inicie la máquina de Podman con 'podman machine start'")
+	case runtime.GOOS == "darwin":
+		return common.T("Inicie o Colima com 'colima start' ou abra o Docker Desktop", "Inicie Colima con 'colima start' o abra Docker Desktop")
+	case runtime.GOOS == "linux":
+		return fmt.Sprintf(common.T("Inicie o serviço com 'sudo systemctl start %s' e verifique se o usuário tem permissão (grupo %s)", "Inicie el servicio con 'sudo systemctl start %s' y verifique si el usuario tiene permiso (grupo %s)"), engine, engine)
+	}
+	return fmt.Sprintf(common.T("Inicie o %s e execute o diagnóstico novamente", "Inicie %s y ejecute el diagnóstico nuevamente"), engine)
+}
+
+// providerBinary verifica se o binário do provider está instalado
+func providerBinary(ctx context.Context, opts Options) Check {
+	provider := opts.Provider
+	if provider == "" {
+		provider = "kind"
+	}
+	check := Check{Name: provider}
+
+	args := []string{"version"}
+	if provider == "minikube" {
+		args = append(args, "--short")
+	}
+	out, err := opts.Runner.Output(ctx, provider, args...)
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf(common.T("%s não encontrado no PATH", "%s no encontrado en el PATH"), provider)
+		check.Hint = providerInstallHint(provider)
+	case err != nil:
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf(common.T("erro ao executar '%s %s': %v", "error al ejecutar '%s %s': %v"), provider, strings.Join(args, " "), err)
+		check.Hint = fmt.Sprintf(common.T("Reinstale o %s", "Reinstale %s"), provider)
+	default:
+		check.Status = StatusPass
+		check.Message = fmt.Sprintf("%s %s", provider, versionOrUnknown(string(out)))
+	}
+	return check
+}
+
+// providerInstallHint retorna o endereço das instruções de instalação do provider
+func providerInstallHint(provider string) string {
+	switch provider {
+	case "k3d":
+		return common.T("Instale o k3d: https://k3d.io/#installation", "Instale k3d: https://k3d.io/#installation")
+	case "minikube":
+		return common.T("Instale o minikube: https://minikube.sigs.k8s.io/docs/start/", "Instale minikube: https://minikube.sigs.k8s.io/docs/start/")
+	}
+	return common.T("Instale o kind: https://kind.sigs.k8s.io/docs/user/quick-start/#installation", "Instale kind: https://kind.sigs.k8s.io/docs/user/quick-start/#installation")
+}
+
+// kubectl verifica se o kubectl está instalado
+func kubectl(ctx context.Context, opts Options) Check {
+	check := Check{Name: "kubectl"}
+	out, err := opts.Runner.Output(ctx, "kubectl", "version", "--client", "-o", "json")
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		check.Status = StatusFail
+		check.Message = common.T("kubectl não encontrado no PATH", "kubectl no encontrado en el PATH")
+		check.Hint = common.T("Instale o kubectl: https://kubernetes.io/docs/tasks/tools/", "Instale kubectl: https://kubernetes.io/docs/tasks/tools/")
+	case err != nil:
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf(common.T("erro ao executar 'kubectl version': %v", "error al ejecutar 'kubectl version': %v"), err)
+		check.Hint = common.T("Reinstale o kubectl: https://kubernetes.io/docs/tasks/tools/", "Reinstale kubectl: https://kubernetes.io/docs/tasks/tools/")
+	default:
+		var version struct {
+			ClientVersion struct {
+				GitVersion string `json:"gitVersion"`
+			} `json:"clientVersion"`
+		}
+		json.Unmarshal(out, &version)
+		check.Status = StatusPass
+		check.Message = "kubectl " + versionOrUnknown(version.ClientVersion.GitVersion)
+	}
+	return check
+}
+
+// port verifica se a porta local está livre ou já é usada pelo GIRUS
+func port(number int, component, url, flag string) Check {
+	check := Check{Name: fmt.Sprintf("port-%d", number)}
+	switch {
+	case !helpers.PortInUse(number):
+		check.Status = StatusPass
+		check.Message = fmt.Sprintf(common.T("porta %d (%s) livre", "puerto %d (%s) libre"), number, component)
+	case helpers.HTTPReachable(url):
+		check.Status = StatusPass
+		check.Message = fmt.Sprintf(common.T("porta %d em uso pelo %s do GIRUS", "puerto %d en uso por el %s de GIRUS"), number, component)
+	default:
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf(common.T("porta %d (%s) em uso por outro processo", "puerto %d (%s) en uso por otro proceso"), number, component)
+		check.Hint = fmt.Sprintf(common.T("Libere a porta ou use 'girus connect %s <porta>'", "Libere el puerto o use 'girus connect %s <puerto>'"), flag)
+	}
+	return check
+}
+
+// kubeContext verifica se o cluster do GIRUS responde
+func kubeContext(ctx context.Context, opts Options) Check {
+	check := Check{Name: "kube-context"}
+	target := common.T("contexto atual do kubeconfig", "contexto actual del kubeconfig")
+	if current := k8s.CurrentClientOptions().Context; current != "" {
+		target = fmt.Sprintf(common.T("contexto %s", "contexto %s"), current)
+	}
+
+	if _, err := opts.Runner.Output(ctx, "kubectl", k8s.KubectlArgs("get", "--raw", "/readyz")...); err != nil {
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf(common.T("cluster inacessível (%s)", "clúster inaccesible (%s)"), target)
+		check.Hint = common.T("Crie o cluster com 'girus create cluster' ou informe o cluster com --context/--kubeconfig", "Cree el clúster con 'girus create cluster' o indique el clúster con --context/--kubeconfig")
+		return check
+	}
+	check.Status = StatusPass
+	check.Message = fmt.Sprintf(common.T("cluster acessível (%s)", "clúster accesible (%s)"), target)
+	return check
+}
+
+// backend verifica se o GIRUS está instalado e se o backend responde em
+// /api/v1/health pelo proxy de serviços da API
+func backend(ctx context.Context, opts Options) Check {
+	check := Check{Name: "backend"}
+	namespace := k8s.Namespace()
+
+	out, err := opts.Runner.Output(ctx, "kubectl", k8s.KubectlArgs("get", "namespace", namespace, "--no-headers", "--ignore-not-found")...)
+	if err != nil || !strings.Contains(string(out), namespace) {
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf(common.T("GIRUS não instalado (namespace %s ausente)", "GIRUS no instalado (namespace %s ausente)"), namespace)
+		check.Hint = common.T("Instale com 'girus create cluster' ou 'girus install'", "Instale con 'girus create cluster' o 'girus install'")
+		return check
+	}
+
+	client, err := opts.NewClient()
+	if err != nil || !client.BackendHealthy(ctx, namespace) {
+		check.Status = StatusFail
+		check.Message = common.T("o backend não responde em /api/v1/health", "el backend no responde en /api/v1/health")
+		check.Hint = fmt.Sprintf(common.T("Verifique os pods com 'kubectl get pods -n %s' e os logs com 'kubectl logs -n %s deploy/girus-backend'", "Verifique los pods con 'kubectl get pods -n %s' y los logs con 'kubectl logs -n %s deploy/girus-backend'"), namespace, namespace)
+		return check
+	}
+	check.Status = StatusPass
+	check.Message = common.T("backend respondendo em /api/v1/health", "backend respondiendo en /api/v1/health")
+	return check
+}
+
+// versionPattern encontra a versão na saída dos comandos de versão
+var versionPattern = regexp.MustCompile(`v?\d+\.\d+(\.\d+)?\S*`)
+
+// versionOrUnknown retorna a primeira versão da saída ou "(versão desconhecida)"
+func versionOrUnknown(out string) string {
+	if version := versionPattern.FindString(out); version != "" {
+		return version
+	}
+	return common.T("(versão desconhecida)", "(versión desconocida)")
+}
@@ -0,0 +1,158 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/executil"
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/k8s/k8sfake"
+)
+
+// freePort retorna uma porta TCP local livre
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// clientFactory retorna uma fábrica que devolve sempre o cliente informado
+func clientFactory(client *k8s.KubernetesClient) func() (*k8s.KubernetesClient, error) {
+	return func() (*k8s.KubernetesClient, error) { return client, nil }
+}
+
+// find retorna a verificação pelo nome
+func find(t *testing.T, report Report, name string) Check {
+	t.Helper()
+	for _, c := range report.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("verificação %q ausente em %+v", name, report.Checks)
+	return Check{}
+}
+
+func TestRunHealthy(t *testing.T) {
+	f := executil.NewFake()
+	f.On("docker info", executil.Response{Output: "27.3.1\n"})
+	f.On("kind version", executil.Response{Output: "kind v0.27.0 go1.23.4 linux/amd64\n"})
+	f.On("kubectl version", executil.Response{Output: `{"clientVersion":{"gitVersion":"v1.33.1"}}`})
+	f.On("kubectl get namespace girus", executil.Response{Output: "girus   Active   2d\n"})
+	client, clientset, _ := k8sfake.NewClient()
+	k8sfake.BackendHealth(clientset, nil)
+
+	report := Run(context.Background(), Options{Runner: f, Provider: "kind", ContainerEngine: "docker", FrontendPort: freePort(t), BackendPort: freePort(t), NewClient: clientFactory(client)})
+
+	want := map[string]string{
+		"container-engine": "docker 27.3.1 em execução",
+		"kind":             "kind v0.27.0",
+		"kubectl":          "kubectl v1.33.1",
+		"kube-context":     "cluster acessível (contexto atual do kubeconfig)",
+		"backend":          "backend respondendo em /api/v1/health",
+	}
+	for name, message := range want {
+		c := find(t, report, name)
+		if c.Status != StatusPass || c.Message != message {
+			t.Errorf("%s = %s %q, esperado pass %q", name, c.Status, c.Message, message)
+		}
+	}
+	if f.Ran("kubectl exec") {
+		t.Errorf("a saúde do backend deve usar o proxy de serviços; comandos: %q", f.Commands())
+	}
+}
+
+func TestRunMissingBinaries(t *testing.T) {
+	f := executil.NewFake()
+	f.Missing("podman", "k3d", "kubectl")
+
+	report := Run(context.Background(), Options{Runner: f, Provider: "k3d", ContainerEngine: "podman", FrontendPort: freePort(t), BackendPort: freePort(t)})
+
+	for _, name := range []string{"container-engine", "k3d", "kubectl"} {
+		if c := find(t, report, name); c.Status != StatusFail || c.Hint == "" {
+			t.Errorf("%s = %+v, esperado fail com dica", name, c)
+		}
+	}
+	if c := find(t, report, "kube-context"); c.Status != StatusWarn {
+		t.Errorf("kube-context = %+v, esperado warn", c)
+	}
+	for _, c := range report.Checks {
+		if c.Name == "backend" {
+			t.Error("backend verificado sem acesso ao cluster")
+		}
+	}
+	if !report.Failed() || report.Count(StatusFail) != 3 {
+		t.Errorf("falhas = %d, esperado 3", report.Count(StatusFail))
+	}
+}
+
+func TestRunEngineStopped(t *testing.T) {
+	f := executil.NewFake()
+	f.On("docker info", executil.Response{Output: "Cannot connect to the Docker daemon", ExitCode: 1})
+
+	report := Run(context.Background(), Options{Runner: f, Provider: "kind", FrontendPort: freePort(t), BackendPort: freePort(t)})
+	if c := find(t, report, "container-engine"); c.Status != StatusFail || c.Message != "docker instalado, mas o serviço não responde" {
+		t.Errorf("container-engine = %+v", c)
+	}
+}
+
+func TestRunBYO(t *testing.T) {
+	f := executil.NewFake()
+	f.On("kubectl get namespace girus", executil.Response{Output: "girus   Active   2d\n"})
+	client, clientset, _ := k8sfake.NewClient()
+	k8sfake.BackendHealth(clientset, errors.New("503 Service Unavailable"))
+
+	report := Run(context.Background(), Options{Runner: f, Provider: "byo", FrontendPort: freePort(t), BackendPort: freePort(t), NewClient: clientFactory(client)})
+	if f.Ran("docker") || f.Ran("kind") {
+		t.Errorf("engine verificado no provider byo: %q", f.Commands())
+	}
+	if c := find(t, report, "backend"); c.Status != StatusFail || c.Hint == "" {
+		t.Errorf("backend = %+v, esperado fail com dica", c)
+	}
+}
+
+func TestPortInUse(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	busy := l.Addr().(*net.TCPAddr).Port
+
+	free := freePort(t)
+
+	report := Run(context.Background(), Options{Runner: executil.NewFake(), Provider: "byo", FrontendPort: busy, BackendPort: free})
+	if c := find(t, report, fmt.Sprintf("port-%d", busy)); c.Status != StatusWarn || c.Hint == "" {
+		t.Errorf("porta ocupada = %+v, esperado warn com dica", c)
+	}
+	if c := find(t, report, fmt.Sprintf("port-%d", free)); c.Status != StatusPass {
+		t.Errorf("porta livre = %+v, esperado pass", c)
+	}
+}
+
+func TestRunSpanish(t *testing.T) {
+	prev := common.Lang()
+	common.SetLanguage("es")
+	t.Cleanup(func() { common.SetLanguage(prev) })
+
+	f := executil.NewFake()
+	f.Missing("kubectl")
+	f.On("docker info", executil.Response{Output: "Cannot connect to the Docker daemon", ExitCode: 1})
+
+	report := Run(context.Background(), Options{Runner: f, Provider: "kind", FrontendPort: freePort(t), BackendPort: freePort(t)})
+	if c := find(t, report, "container-engine"); c.Message != "docker instalado, pero el servicio no responde" {
+		t.Errorf("container-engine = %+v, esperado a mensagem em espanhol", c)
+	}
+	if c := find(t, report, "kubectl"); c.Message != "kubectl no encontrado en el PATH" || !strings.HasPrefix(c.Hint, "Instale kubectl") {
+		t.Errorf("kubectl = %+v, esperado a mensagem em espanhol", c)
+	}
+}
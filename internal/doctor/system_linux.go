@@ -0,0 +1,132 @@
+//go:build linux
+
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+)
+
+// Arquivos e diretórios consultados nas verificações do sistema, alterados
+// nos testes
+var (
+	procSysFS  = "/proc/sys/fs"
+	cgroupRoot = "/sys/fs/cgroup"
+	// diskPaths são os diretórios de dados do engine; vale o primeiro existente
+	diskPaths = []string{"/var/lib/docker", "/var/lib/containers", "/"}
+)
+
+// Limites recomendados pelo kind (https://kind.sigs.k8s.io/docs/user/known-issues/)
+const (
+	minInotifyWatches   = 524288
+	minInotifyInstances = 512
+)
+
+// Espaço livre mínimo para as imagens dos nós e do GIRUS
+const (
+	diskWarnBytes = 10 << 30
+	diskFailBytes = 2 << 30
+)
+
+// systemChecks verifica os limites do sistema que afetam o kind
+func systemChecks() []Check {
+	return []Check{inotify(), cgroup(), disk()}
+}
+
+// inotify verifica os limites do inotify, que quando baixos fazem os pods do
+// kind falharem com "too many open files"
+func inotify() Check {
+	check := Check{Name: "inotify"}
+	watches, errWatches := readSysctl("inotify/max_user_watches")
+	instances, errInstances := readSysctl("inotify/max_user_instances")
+	if errWatches != nil || errInstances != nil {
+		check.Status = StatusWarn
+		check.Message = common.T("não foi possível ler os limites do inotify", "no fue posible leer los límites de inotify")
+		return check
+	}
+
+	check.Message = fmt.Sprintf("max_user_watches=%d, max_user_instances=%d", watches, instances)
+	var fixes []string
+	if watches < minInotifyWatches {
+		fixes = append(fixes, fmt.Sprintf("sudo sysctl fs.inotify.max_user_watches=%d", minInotifyWatches))
+	}
+	if instances < minInotifyInstances {
+		fixes = append(fixes, fmt.Sprintf("sudo sysctl fs.inotify.max_user_instances=%d", minInotifyInstances))
+	}
+	if len(fixes) == 0 {
+		check.Status = StatusPass
+		return check
+	}
+	check.Status = StatusWarn
+	check.Hint = fmt.Sprintf(common.T("Aumente os limites com '%s' (persista em /etc/sysctl.d/)", "Aumente los límites con '%s' (persista en /etc/sysctl.d/)"),
+		strings.Join(fixes, common.T("' e '", "' y '")))
+	return check
+}
+
+// readSysctl lê um valor numérico de /proc/sys/fs
+func readSysctl(name string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(procSysFS, name))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// cgroup verifica a versão do cgroup. Os nós do Kubernetes 1.31+ exigem
+// configuração extra para rodar com cgroup v1.
+func cgroup() Check {
+	check := Check{Name: "cgroup"}
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		check.Status = StatusPass
+		check.Message = "cgroup v2"
+		return check
+	}
+	check.Status = StatusWarn
+	check.Message = common.T("cgroup v1 (obsoleto no Kubernetes 1.31+)", "cgroup v1 (obsoleto en Kubernetes 1.31+)")
+	check.Hint = common.T("Habilite o cgroup v2 com o parâmetro de boot 'systemd.unified_cgroup_hierarchy=1' ou use um nó com --k8s-version anterior à v1.31",
+		"Habilite cgroup v2 con el parámetro de arranque 'systemd.unified_cgroup_hierarchy=1' o use un nodo con --k8s-version anterior a v1.31")
+	return check
+}
+
+// disk verifica o espaço livre no diretório de dados do engine
+func disk() Check {
+	check := Check{Name: "disk"}
+	path := "/"
+	for _, p := range diskPaths {
+		if _, err := os.Stat(p); err == nil {
+			path = p
+			break
+		}
+	}
+
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf(common.T("não foi possível verificar o espaço livre em %s: %v", "no fue posible verificar el espacio libre en %s: %v"), path, err)
+		return check
+	}
+	free := uint64(st.Bavail) * uint64(st.Bsize)
+	check.Status = diskStatus(free)
+	check.Message = fmt.Sprintf(common.T("%.1f GiB livres em %s", "%.1f GiB libres en %s"), float64(free)/(1<<30), path)
+	if check.Status != StatusPass {
+		check.Hint = fmt.Sprintf(common.T("Libere espaço em %s (recomendado: %d GiB livres), por exemplo com 'docker system prune'", "Libere espacio en %s (recomendado: %d GiB libres), por ejemplo con 'docker system prune'"), path, diskWarnBytes>>30)
+	}
+	return check
+}
+
+// diskStatus classifica o espaço livre em bytes
+func diskStatus(free uint64) Status {
+	switch {
+	case free < diskFailBytes:
+		return StatusFail
+	case free < diskWarnBytes:
+		return StatusWarn
+	}
+	return StatusPass
+}
@@ -0,0 +1,60 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeSystem aponta /proc/sys/fs e /sys/fs/cgroup para diretórios temporários
+func fakeSystem(t *testing.T, watches, instances string, cgroupV2 bool) {
+	t.Helper()
+	proc, cgroups := t.TempDir(), t.TempDir()
+	os.MkdirAll(filepath.Join(proc, "inotify"), 0755)
+	os.WriteFile(filepath.Join(proc, "inotify", "max_user_watches"), []byte(watches+"\n"), 0644)
+	os.WriteFile(filepath.Join(proc, "inotify", "max_user_instances"), []byte(instances+"\n"), 0644)
+	if cgroupV2 {
+		os.WriteFile(filepath.Join(cgroups, "cgroup.controllers"), []byte("cpu memory\n"), 0644)
+	}
+
+	prevProc, prevCgroup := procSysFS, cgroupRoot
+	t.Cleanup(func() { procSysFS, cgroupRoot = prevProc, prevCgroup })
+	procSysFS, cgroupRoot = proc, cgroups
+}
+
+func TestSystemChecks(t *testing.T) {
+	fakeSystem(t, "524288", "512", true)
+	if c := inotify(); c.Status != StatusPass {
+		t.Errorf("inotify = %+v, esperado pass", c)
+	}
+	if c := cgroup(); c.Status != StatusPass || c.Message != "cgroup v2" {
+		t.Errorf("cgroup = %+v, esperado v2", c)
+	}
+
+	fakeSystem(t, "8192", "128", false)
+	if c := inotify(); c.Status != StatusWarn || c.Hint == "" {
+		t.Errorf("inotify = %+v, esperado warn com dica", c)
+	}
+	if c := cgroup(); c.Status != StatusWarn {
+		t.Errorf("cgroup = %+v, esperado warn", c)
+	}
+}
+
+func TestDiskStatus(t *testing.T) {
+	tests := []struct {
+		free uint64
+		want Status
+	}{
+		{1 << 30, StatusFail},
+		{5 << 30, StatusWarn},
+		{50 << 30, StatusPass},
+	}
+	for _, tt := range tests {
+		if got := diskStatus(tt.free); got != tt.want {
+			t.Errorf("diskStatus(%d GiB) = %s, esperado %s", tt.free>>30, got, tt.want)
+		}
+	}
+	if c := disk(); c.Message == "" {
+		t.Errorf("disk = %+v", c)
+	}
+}
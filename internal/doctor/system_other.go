@@ -0,0 +1,9 @@
+//go:build !linux
+
+package doctor
+
+// systemChecks não verifica limites do sistema fora do Linux, onde o engine
+// roda em uma máquina virtual com configuração própria
+func systemChecks() []Check {
+	return nil
+}
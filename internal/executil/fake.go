@@ -0,0 +1,138 @@
+package executil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Response é o resultado roteirizado de um comando no Fake
+type Response struct {
+	// Output é o stdout do comando; em caso de falha vai para a mensagem de erro
+	Output string
+	// ExitCode diferente de zero faz o comando falhar com um *ExitError
+	ExitCode int
+	// Err, se definido, é retornado no lugar do *ExitError
+	Err error
+}
+
+// Fake é um Runner que registra os comandos executados e devolve respostas
+// roteirizadas com On. Comandos sem resposta roteirizada terminam com sucesso
+// e sem saída; binários marcados com Missing falham como se não estivessem no
+// PATH.
+type Fake struct {
+	mu        sync.Mutex
+	commands  []string
+	responses map[string]Response
+	missing   map[string]bool
+}
+
+// NewFake cria um Fake sem respostas roteirizadas
+func NewFake() *Fake {
+	return &Fake{responses: map[string]Response{}, missing: map[string]bool{}}
+}
+
+// On roteiriza a resposta de um comando, informado como o binário seguido dos
+// argumentos separados por espaços. O comando também vale como prefixo: "kind
+// create cluster" responde a "kind create cluster --name girus". Vence o
+// prefixo mais longo.
+func (f *Fake) On(command string, r Response) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[command] = r
+}
+
+// Missing faz os binários falharem como se não estivessem instalados
+func (f *Fake) Missing(binaries ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, b := range binaries {
+		f.missing[b] = true
+	}
+}
+
+// Commands retorna os comandos executados, na ordem
+func (f *Fake) Commands() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.commands...)
+}
+
+// Ran indica se algum comando executado começa com o comando informado
+func (f *Fake) Ran(command string) bool {
+	for _, c := range f.Commands() {
+		if matches(c, command) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run registra o comando e escreve a saída roteirizada em out
+func (f *Fake) Run(ctx context.Context, out io.Writer, name string, args ...string) error {
+	output, err := f.exec(name, args)
+	if out != nil {
+		io.WriteString(out, output)
+	}
+	return err
+}
+
+// Output registra o comando e retorna a saída roteirizada
+func (f *Fake) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	output, err := f.exec(name, args)
+	if err != nil && output != "" && !IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(output))
+	}
+	return []byte(output), err
+}
+
+// LookPath falha para os binários marcados com Missing
+func (f *Fake) LookPath(name string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.missing[name] {
+		return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+	}
+	return "/usr/bin/" + name, nil
+}
+
+// exec registra o comando e resolve a resposta roteirizada
+func (f *Fake) exec(name string, args []string) (string, error) {
+	command := strings.Join(append([]string{name}, args...), " ")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.commands = append(f.commands, command)
+
+	if f.missing[name] {
+		return "", fmt.Errorf("%s: %w", command, &exec.Error{Name: name, Err: exec.ErrNotFound})
+	}
+
+	best := ""
+	found := false
+	for key := range f.responses {
+		if matches(command, key) && len(key) >= len(best) {
+			best, found = key, true
+		}
+	}
+	if !found {
+		return "", nil
+	}
+	r := f.responses[best]
+	switch {
+	case r.Err != nil:
+		return r.Output, fmt.Errorf("%s: %w", command, r.Err)
+	case r.ExitCode != 0:
+		return r.Output, fmt.Errorf("%s: %w", command, &ExitError{Code: r.ExitCode})
+	}
+	return r.Output, nil
+}
+
+// matches indica se o comando é igual ao prefixo ou começa com ele seguido de
+// um espaço
+func matches(command, prefix string) bool {
+	return command == prefix || strings.HasPrefix(command, prefix+" ")
+}
@@ -0,0 +1,102 @@
+// Package executil executa os binários externos usados pelo GIRUS (kind, k3d,
+// minikube, kubectl, docker, podman). Os comandos recebem um Runner, e os
+// testes usam o Fake para verificar os comandos sem executá-los.
+package executil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// Runner executa comandos externos
+type Runner interface {
+	// Run executa o comando enviando stdout e stderr para out
+	Run(ctx context.Context, out io.Writer, name string, args ...string) error
+	// Output executa o comando e retorna o stdout
+	Output(ctx context.Context, name string, args ...string) ([]byte, error)
+	// LookPath procura o binário no PATH
+	LookPath(name string) (string, error)
+}
+
+// ExecRunner executa os comandos com os/exec
+type ExecRunner struct{}
+
+// Run executa o comando enviando stdout e stderr para out
+func (ExecRunner) Run(ctx context.Context, out io.Writer, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// Output executa o comando e retorna o stdout. Em caso de erro, o stderr é
+// incluído na mensagem.
+func (ExecRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return out, fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, msg)
+		}
+		return out, fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return out, nil
+}
+
+// LookPath procura o binário no PATH
+func (ExecRunner) LookPath(name string) (string, error) {
+	return exec.LookPath(name)
+}
+
+// ExitError é o erro de um comando que terminou com código de saída diferente
+// de zero, retornado pelo Fake
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// ExitCode retorna o código de saída do comando que falhou, 0 se err for nil
+// ou -1 se o comando não chegou a terminar (por exemplo, binário ausente)
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	var fakeErr *ExitError
+	if errors.As(err, &fakeErr) {
+		return fakeErr.Code
+	}
+	return -1
+}
+
+// IsNotFound indica se o comando falhou porque o binário não está no PATH
+func IsNotFound(err error) bool {
+	return errors.Is(err, exec.ErrNotFound)
+}
+
+// Lines separa a saída de um comando em linhas não vazias
+func Lines(out []byte) []string {
+	var result []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
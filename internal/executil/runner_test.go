@@ -0,0 +1,108 @@
+package executil_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/executil"
+)
+
+func TestFakeResponses(t *testing.T) {
+	f := executil.NewFake()
+	f.On("kind get clusters", executil.Response{Output: "girus\noutro\n"})
+	f.On("kind create cluster", executil.Response{Output: "ERROR: node(s) already exist", ExitCode: 1})
+	f.On("kind create cluster --name teste", executil.Response{Output: "ok"})
+	ctx := context.Background()
+
+	out, err := f.Output(ctx, "kind", "get", "clusters")
+	if err != nil || !reflect.DeepEqual(executil.Lines(out), []string{"girus", "outro"}) {
+		t.Errorf("Output = %q, %v", out, err)
+	}
+
+	var buf bytes.Buffer
+	err = f.Run(ctx, &buf, "kind", "create", "cluster", "--name", "girus")
+	if code := executil.ExitCode(err); code != 1 {
+		t.Errorf("ExitCode = %d (%v), esperado 1", code, err)
+	}
+	if buf.String() != "ERROR: node(s) already exist" {
+		t.Errorf("saída = %q", buf.String())
+	}
+
+	// O prefixo mais longo vence
+	if err := f.Run(ctx, &buf, "kind", "create", "cluster", "--name", "teste"); err != nil {
+		t.Errorf("Run: %v", err)
+	}
+
+	// Comandos sem resposta roteirizada terminam com sucesso
+	if out, err := f.Output(ctx, "kubectl", "version"); err != nil || len(out) != 0 {
+		t.Errorf("Output = %q, %v", out, err)
+	}
+
+	want := []string{
+		"kind get clusters",
+		"kind create cluster --name girus",
+		"kind create cluster --name teste",
+		"kubectl version",
+	}
+	if !reflect.DeepEqual(f.Commands(), want) {
+		t.Errorf("comandos = %q, esperado %q", f.Commands(), want)
+	}
+	if !f.Ran("kind create") || f.Ran("kind delete") || f.Ran("kind get cluster") {
+		t.Error("Ran não corresponde aos comandos executados")
+	}
+}
+
+func TestFakeOutputError(t *testing.T) {
+	f := executil.NewFake()
+	f.On("kubectl get pods", executil.Response{Output: "forbidden\n", ExitCode: 1})
+	f.On("kubectl top", executil.Response{Err: errors.New("metrics indisponível")})
+
+	_, err := f.Output(context.Background(), "kubectl", "get", "pods")
+	if err == nil || !strings.Contains(err.Error(), "forbidden") || executil.ExitCode(err) != 1 {
+		t.Errorf("erro = %v", err)
+	}
+	_, err = f.Output(context.Background(), "kubectl", "top", "nodes")
+	if err == nil || executil.ExitCode(err) != -1 {
+		t.Errorf("erro = %v", err)
+	}
+}
+
+func TestFakeMissing(t *testing.T) {
+	f := executil.NewFake()
+	f.Missing("podman")
+
+	if _, err := f.LookPath("podman"); !executil.IsNotFound(err) {
+		t.Errorf("LookPath = %v, esperado binário ausente", err)
+	}
+	if _, err := f.LookPath("docker"); err != nil {
+		t.Errorf("LookPath(docker) = %v", err)
+	}
+	err := f.Run(context.Background(), nil, "podman", "info")
+	if !executil.IsNotFound(err) || executil.ExitCode(err) != -1 {
+		t.Errorf("Run = %v, esperado binário ausente", err)
+	}
+}
+
+func TestExecRunner(t *testing.T) {
+	var r executil.ExecRunner
+	ctx := context.Background()
+
+	if _, err := r.Output(ctx, "girus-binario-inexistente"); !executil.IsNotFound(err) {
+		t.Errorf("esperado binário ausente, obtido %v", err)
+	}
+	if _, err := r.LookPath("sh"); err != nil {
+		t.Skip("sh indisponível")
+	}
+	out, err := r.Output(ctx, "sh", "-c", "echo ok")
+	if err != nil || strings.TrimSpace(string(out)) != "ok" {
+		t.Errorf("Output = %q, %v", out, err)
+	}
+	err = r.Run(ctx, nil, "sh", "-c", "exit 3")
+	if code := executil.ExitCode(err); code != 3 {
+		t.Errorf("ExitCode = %d (%v), esperado 3", code, err)
+	}
+}
@@ -1,11 +1,11 @@
 package helpers
 
 import (
-	"bytes"
 	"fmt"
+	"net"
+	"net/http"
 	"os/exec"
 	"runtime"
-	"strings"
 	"time"
 
 	"github.com/schollz/progressbar/v3"
@@ -22,10 +22,19 @@ type ProgressBarConfig struct {
 	SpinnerType      int
 }
 
-// portInUse verifica se uma porta está em uso
+// PortInUse verifica se uma porta TCP local está em uso tentando abri-la, sem
+// depender do lsof. O loopback é testado à parte porque, em alguns sistemas,
+// abrir a porta em todas as interfaces não conflita com um processo que
+// escuta apenas em 127.0.0.1.
 func PortInUse(port int) bool {
-	checkCmd := exec.Command("lsof", "-i", fmt.Sprintf(":%d", port))
-	return checkCmd.Run() == nil
+	for _, address := range []string{fmt.Sprintf(":%d", port), fmt.Sprintf("127.0.0.1:%d", port)} {
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			return true
+		}
+		listener.Close()
+	}
+	return false
 }
 
 // openBrowser abre o navegador com a URL especificada
@@ -46,60 +55,31 @@ func OpenBrowser(url string) error {
 	return cmd.Start()
 }
 
-// checkPortForwardNeeded verifica se o port-forward para o backend precisa ser reconfigurado
+// checkPortForwardNeeded verifica se o port-forward do backend ou do frontend precisa ser reconfigurado
 func CheckPortForwardNeeded() bool {
-	backendNeeded := false
-	frontendNeeded := false
-
-	// Verificar se a porta 8080 (backend) está em uso
-	backendPortCmd := exec.Command("lsof", "-i", ":8080")
-	if backendPortCmd.Run() != nil {
-		// Porta 8080 não está em uso, precisamos de port-forward
-		backendNeeded = true
-	} else {
-		// Porta está em uso, mas precisamos verificar se é o kubectl port-forward e se está funcional
-		// Verificar se o processo é kubectl port-forward
-		backendProcessCmd := exec.Command("sh", "-c", "ps -eo pid,cmd | grep 'kubectl port-forward' | grep '8080' | grep -v grep")
-		if backendProcessCmd.Run() != nil {
-			// Não encontrou processo de port-forward ativo ou válido
-			backendNeeded = true
-		} else {
-			// Verificar se a conexão com o backend está funcionando
-			backendHealthCmd := exec.Command("curl", "-s", "--head", "--max-time", "2", "http://localhost:8080/api/v1/health")
-			backendNeeded = backendHealthCmd.Run() != nil // Retorna true (precisa de port-forward) se o comando falhar
-		}
-	}
-
-	// Verificar se a porta 8000 (frontend) está em uso
-	frontendPortCmd := exec.Command("lsof", "-i", ":8000")
-	if frontendPortCmd.Run() != nil {
-		// Porta 8000 não está em uso, precisamos de port-forward
-		frontendNeeded = true
-	} else {
-		// Porta está em uso, mas precisamos verificar se é o kubectl port-forward e se está funcional
-		// Verificar se o processo é kubectl port-forward
-		frontendProcessCmd := exec.Command("sh", "-c", "ps -eo pid,cmd | grep 'kubectl port-forward' | grep '8000' | grep -v grep")
-		if frontendProcessCmd.Run() != nil {
-			// Não encontrou processo de port-forward ativo ou válido
-			frontendNeeded = true
-		} else {
-			// Verificar se a conexão com o frontend está funcionando
-			frontendCheckCmd := exec.Command("curl", "-s", "--max-time", "2", "-o", "/dev/null", "-w", "%{http_code}", "http://localhost:8000")
-			var out bytes.Buffer
-			frontendCheckCmd.Stdout = &out
-			if frontendCheckCmd.Run() != nil {
-				frontendNeeded = true
-			} else {
-				statusCode := strings.TrimSpace(out.String())
-				frontendNeeded = (statusCode != "200" && statusCode != "301" && statusCode != "302")
-			}
-		}
-	}
+	backendNeeded := !HTTPReachable("http://localhost:8080/api/v1/health")
+	frontendNeeded := !HTTPReachable("http://localhost:8000")
 
 	// Se qualquer um dos serviços precisar de port-forward, retorne true
 	return backendNeeded || frontendNeeded
 }
 
+// HTTPReachable verifica se a URL responde com sucesso (2xx) ou redirecionamento (3xx)
+func HTTPReachable(url string) bool {
+	client := &http.Client{
+		Timeout: 2 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode >= 200 && resp.StatusCode < 400
+}
+
 func CreateProgressBar(config ProgressBarConfig) *progressbar.ProgressBar {
 	return progressbar.NewOptions(
 		config.Total,
@@ -0,0 +1,208 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+)
+
+// FieldManager é o gerenciador de campos usado pelo GIRUS no server-side apply
+const FieldManager = "girus"
+
+// ApplyAction indica o que aconteceu com um objeto ao ser aplicado
+type ApplyAction string
+
+const (
+	ApplyCreated    ApplyAction = "created"
+	ApplyConfigured ApplyAction = "configured"
+	ApplyUnchanged  ApplyAction = "unchanged"
+)
+
+// ApplyResult é o resultado da aplicação de um objeto
+type ApplyResult struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Action    ApplyAction
+}
+
+// String formata o resultado como o kubectl (ex.: configmap/girus-config created)
+func (r ApplyResult) String() string {
+	return fmt.Sprintf("%s/%s %s", strings.ToLower(r.Kind), r.Name, r.Action)
+}
+
+// ApplyError descreve a falha ao aplicar um objeto específico
+type ApplyError struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Err       error
+}
+
+func (e *ApplyError) Error() string {
+	target := strings.ToLower(e.Kind) + "/" + e.Name
+	if e.Namespace != "" {
+		target += " (namespace " + e.Namespace + ")"
+	}
+	return fmt.Sprintf("falha ao aplicar %s: %v", target, e.Err)
+}
+
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
+// Applier aplica manifestos no cluster usando server-side apply, sem depender do kubectl
+type Applier struct {
+	client dynamic.Interface
+	mapper meta.RESTMapper
+
+	// Namespace usado para objetos namespaced sem namespace definido
+	Namespace string
+	// FieldManager identifica o GIRUS como dono dos campos aplicados
+	FieldManager string
+	// Force assume a posse de campos em conflito com outros gerenciadores
+	Force bool
+}
+
+// NewApplier cria um Applier a partir de um cliente dinâmico e de um RESTMapper
+func NewApplier(client dynamic.Interface, mapper meta.RESTMapper) *Applier {
+	return &Applier{
+		client:       client,
+		mapper:       mapper,
+		Namespace:    metav1.NamespaceDefault,
+		FieldManager: FieldManager,
+		Force:        true,
+	}
+}
+
+// Apply aplica todos os documentos de um YAML (multi-documento). Todos os objetos
+// são processados; as falhas são agregadas no erro retornado.
+func (a *Applier) Apply(ctx context.Context, data []byte) ([]ApplyResult, error) {
+	objs, err := DecodeManifests(data)
+	if err != nil {
+		return nil, err
+	}
+	return a.ApplyObjects(ctx, objs)
+}
+
+// ApplyObjects aplica os objetos na ordem em que foram informados
+func (a *Applier) ApplyObjects(ctx context.Context, objs []*unstructured.Unstructured) ([]ApplyResult, error) {
+	var results []ApplyResult
+	var errs []error
+	for _, obj := range objs {
+		result, err := a.applyObject(ctx, obj)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, errors.Join(errs...)
+}
+
+func (a *Applier) applyObject(ctx context.Context, obj *unstructured.Unstructured) (ApplyResult, error) {
+	gvk := obj.GroupVersionKind()
+	result := ApplyResult{Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	fail := func(err error) (ApplyResult, error) {
+		return result, &ApplyError{Kind: result.Kind, Namespace: result.Namespace, Name: result.Name, Err: err}
+	}
+
+	if result.Name == "" {
+		return fail(fmt.Errorf("metadata.name é obrigatório"))
+	}
+
+	mapping, err := a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fail(fmt.Errorf("tipo %s desconhecido no cluster: %w", gvk.String(), err))
+	}
+
+	var resource dynamic.ResourceInterface
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if result.Namespace == "" {
+			result.Namespace = a.Namespace
+			obj.SetNamespace(a.Namespace)
+		}
+		resource = a.client.Resource(mapping.Resource).Namespace(result.Namespace)
+	} else {
+		result.Namespace = ""
+		resource = a.client.Resource(mapping.Resource)
+	}
+
+	existing, err := resource.Get(ctx, result.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fail(err)
+	}
+	created := apierrors.IsNotFound(err)
+
+	applied, err := resource.Apply(ctx, result.Name, obj, metav1.ApplyOptions{FieldManager: a.FieldManager, Force: a.Force})
+	if err != nil {
+		return fail(err)
+	}
+
+	switch {
+	case created:
+		result.Action = ApplyCreated
+	case sameContent(existing, applied):
+		result.Action = ApplyUnchanged
+	default:
+		result.Action = ApplyConfigured
+	}
+	return result, nil
+}
+
+// sameContent compara dois objetos ignorando os campos mantidos pelo servidor
+func sameContent(a, b *unstructured.Unstructured) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return reflect.DeepEqual(withoutServerFields(a), withoutServerFields(b))
+}
+
+func withoutServerFields(obj *unstructured.Unstructured) map[string]interface{} {
+	c := obj.DeepCopy().Object
+	delete(c, "status")
+	unstructured.RemoveNestedField(c, "metadata", "managedFields")
+	unstructured.RemoveNestedField(c, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(c, "metadata", "generation")
+	unstructured.RemoveNestedField(c, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(c, "metadata", "uid")
+	return c
+}
+
+// DecodeManifests converte um YAML multi-documento em objetos, ignorando
+// documentos vazios
+func DecodeManifests(data []byte) ([]*unstructured.Unstructured, error) {
+	dec := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+
+	var objs []*unstructured.Unstructured
+	for i := 1; ; i++ {
+		var content map[string]interface{}
+		if err := dec.Decode(&content); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("erro ao interpretar o documento %d: %w", i, err)
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: content}
+		if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+			return nil, fmt.Errorf("documento %d: apiVersion e kind são obrigatórios", i)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
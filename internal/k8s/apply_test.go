@@ -0,0 +1,116 @@
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/k8s/k8sfake"
+	"github.com/badtuxx/girus-cli/internal/templates"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// newFakeApplier cria um Applier sobre o cliente dinâmico fake
+func newFakeApplier(t *testing.T) (*k8s.Applier, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	client, mapper := newFakeDynamic(t)
+	return k8s.NewApplier(client, mapper), client
+}
+
+// newFakeDynamic cria o cliente dinâmico fake e o RESTMapper dos tipos usados
+// nos manifestos (ver k8sfake.NewDynamic)
+func newFakeDynamic(t *testing.T) (*dynamicfake.FakeDynamicClient, meta.RESTMapper) {
+	t.Helper()
+	return k8sfake.NewDynamic()
+}
+
+func countActions(results []k8s.ApplyResult) map[k8s.ApplyAction]int {
+	counts := make(map[k8s.ApplyAction]int)
+	for _, r := range results {
+		counts[r.Action]++
+	}
+	return counts
+}
+
+func TestApplyEmbeddedManifests(t *testing.T) {
+	applier, _ := newFakeApplier(t)
+	ctx := context.Background()
+
+	deployment, err := templates.ManifestFS.ReadFile("manifests/defaultDeployment.yaml")
+	if err != nil {
+		t.Fatalf("erro ao ler o deployment embutido: %v", err)
+	}
+	template, err := templates.ManifestFS.ReadFile("manifests/lab_01_linux_processamento-texto.yaml")
+	if err != nil {
+		t.Fatalf("erro ao ler o template embutido: %v", err)
+	}
+	data := append(append(deployment, []byte("\n---\n")...), template...)
+
+	results, err := applier.Apply(ctx, data)
+	if err != nil {
+		t.Fatalf("erro ao aplicar: %v", err)
+	}
+	objs, _ := k8s.DecodeManifests(data)
+	if counts := countActions(results); counts[k8s.ApplyCreated] != len(objs) {
+		t.Fatalf("esperados %d objetos criados, obtido %v", len(objs), results)
+	}
+
+	// Aplicar de novo não deve alterar nada
+	results, err = applier.Apply(ctx, data)
+	if err != nil {
+		t.Fatalf("erro ao reaplicar: %v", err)
+	}
+	if counts := countActions(results); counts[k8s.ApplyUnchanged] != len(objs) {
+		t.Errorf("esperados %d objetos inalterados, obtido %v", len(objs), results)
+	}
+}
+
+func TestApplyConfiguredAndErrors(t *testing.T) {
+	applier, client := newFakeApplier(t)
+	ctx := context.Background()
+
+	original := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: girus-config
+data:
+  chave: valor
+`)
+	if _, err := applier.Apply(ctx, original); err != nil {
+		t.Fatalf("erro ao aplicar: %v", err)
+	}
+
+	// O namespace padrão do Applier é usado quando o objeto não define um
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	if _, err := client.Tracker().Get(gvr, "default", "girus-config"); err != nil {
+		t.Fatalf("ConfigMap não criado no namespace default: %v", err)
+	}
+
+	changed := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: girus-config
+data:
+  chave: outro-valor
+---
+apiVersion: example.com/v1
+kind: Desconhecido
+metadata:
+  name: teste
+`)
+	results, err := applier.Apply(ctx, changed)
+	if len(results) != 1 || results[0].Action != k8s.ApplyConfigured {
+		t.Fatalf("esperado configmap configurado, obtido %v (%v)", results, err)
+	}
+	if got := results[0].String(); got != "configmap/girus-config configured" {
+		t.Errorf("formato inesperado: %s", got)
+	}
+
+	var applyErr *k8s.ApplyError
+	if !errors.As(err, &applyErr) || applyErr.Kind != "Desconhecido" || applyErr.Name != "teste" {
+		t.Errorf("esperado ApplyError para o tipo desconhecido, obtido %v", err)
+	}
+}
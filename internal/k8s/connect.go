@@ -0,0 +1,315 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/helpers"
+	"github.com/fatih/color"
+)
+
+// ConnectCommand é o subcomando do girus que mantém os port-forwards. Ele é
+// iniciado em segundo plano por StartConnect e SetupPortForward.
+const ConnectCommand = "connect"
+
+// ConnectBackgroundFlag é a flag oculta que marca o girus connect iniciado em
+// segundo plano por StartConnect
+const ConnectBackgroundFlag = "background"
+
+// ConnectPaths são os arquivos do girus connect em ~/.girus
+type ConnectPaths struct {
+	PIDFile string
+	LogFile string
+	Socket  string
+}
+
+// ConnectFiles retorna os caminhos do arquivo de PID, do log e do socket de
+// controle do girus connect
+func ConnectFiles() (ConnectPaths, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ConnectPaths{}, err
+	}
+	dir := filepath.Join(home, ".girus")
+	return ConnectPaths{
+		PIDFile: filepath.Join(dir, "connect.pid"),
+		LogFile: filepath.Join(dir, "connect.log"),
+		Socket:  filepath.Join(dir, "connect.sock"),
+	}, nil
+}
+
+// ConnectStatus é o estado informado pelo socket de controle do girus connect
+type ConnectStatus struct {
+	PID       int                `json:"pid"`
+	Namespace string             `json:"namespace"`
+	Address   string             `json:"address"`
+	StartedAt time.Time          `json:"startedAt"`
+	Forwards  []PortForwardState `json:"forwards"`
+}
+
+// Connected indica se todos os encaminhamentos estão ativos
+func (s *ConnectStatus) Connected() bool {
+	for _, f := range s.Forwards {
+		if !f.Connected {
+			return false
+		}
+	}
+	return len(s.Forwards) > 0
+}
+
+// Forward retorna o estado do encaminhamento de um serviço
+func (s *ConnectStatus) Forward(service string) (PortForwardState, bool) {
+	for _, f := range s.Forwards {
+		if f.Service == service {
+			return f, true
+		}
+	}
+	return PortForwardState{}, false
+}
+
+// ServeConnect mantém os port-forwards e atende o socket de controle até o
+// contexto ser cancelado ou um pedido de desconexão chegar pelo socket
+func ServeConnect(ctx context.Context, pf *PortForwarder) error {
+	paths, err := ConnectFiles()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(paths.Socket), 0755); err != nil {
+		return err
+	}
+	if status, _ := QueryConnect(); status != nil {
+		return fmt.Errorf("o girus connect já está em execução (PID %d)", status.PID)
+	}
+
+	// Um socket restante de uma execução interrompida impede o Listen
+	_ = os.Remove(paths.Socket)
+	ln, err := net.Listen("unix", paths.Socket)
+	if err != nil {
+		return fmt.Errorf("falha ao abrir o socket de controle %s: %w", paths.Socket, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	started := time.Now()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ConnectStatus{
+			PID:       os.Getpid(),
+			Namespace: pf.Namespace,
+			Address:   pf.Address,
+			StartedAt: started,
+			Forwards:  pf.States(),
+		})
+	})
+	mux.HandleFunc("/disconnect", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		cancel()
+	})
+	srv := &http.Server{Handler: mux}
+	go func() { _ = srv.Serve(ln) }()
+
+	if err := os.WriteFile(paths.PIDFile, []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		srv.Close()
+		return fmt.Errorf("erro ao gravar o PID do girus connect: %v", err)
+	}
+	defer func() {
+		shutdownCtx, done := context.WithTimeout(context.Background(), 2*time.Second)
+		defer done()
+		_ = srv.Shutdown(shutdownCtx)
+		_ = os.Remove(paths.Socket)
+		_ = os.Remove(paths.PIDFile)
+	}()
+
+	return pf.Run(ctx)
+}
+
+// connectHTTPClient cria um cliente HTTP que fala com o socket de controle
+func connectHTTPClient(socket string) *http.Client {
+	return &http.Client{
+		Timeout: 2 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+}
+
+// QueryConnect consulta o girus connect em execução. Retorna nil, sem erro,
+// quando nenhum está em execução.
+func QueryConnect() (*ConnectStatus, error) {
+	paths, err := ConnectFiles()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(paths.Socket); err != nil {
+		return nil, nil
+	}
+
+	resp, err := connectHTTPClient(paths.Socket).Get("http://girus-connect/status")
+	if err != nil {
+		// Socket órfão de uma execução interrompida
+		return nil, nil
+	}
+	defer resp.Body.Close()
+
+	var status ConnectStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("resposta inválida do girus connect: %w", err)
+	}
+	return &status, nil
+}
+
+// Disconnect encerra o girus connect em execução e espera as portas locais
+// serem liberadas. Retorna false se nenhum estava em execução.
+func Disconnect() (bool, error) {
+	paths, err := ConnectFiles()
+	if err != nil {
+		return false, err
+	}
+
+	status, err := QueryConnect()
+	if err != nil {
+		return false, err
+	}
+	if status != nil {
+		resp, err := connectHTTPClient(paths.Socket).Post("http://girus-connect/disconnect", "", nil)
+		if err != nil {
+			return false, fmt.Errorf("falha ao encerrar o girus connect: %w", err)
+		}
+		resp.Body.Close()
+		for _, f := range status.Forwards {
+			waitPortFree(f.LocalPort, 5*time.Second)
+		}
+		return true, nil
+	}
+
+	// Sem socket de controle não há como confirmar que o PID registrado ainda é
+	// do girus connect; apenas o arquivo de PID órfão é removido
+	_ = os.Remove(paths.PIDFile)
+	return false, nil
+}
+
+// StartConnect inicia o girus connect em segundo plano com os argumentos
+// informados e espera os port-forwards ficarem ativos
+func StartConnect(args []string, timeout time.Duration) (*ConnectStatus, error) {
+	paths, err := ConnectFiles()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(paths.LogFile), 0755); err != nil {
+		return nil, err
+	}
+	logOut, err := os.OpenFile(paths.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir o log do girus connect: %v", err)
+	}
+	defer logOut.Close()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao localizar o executável do girus: %v", err)
+	}
+
+	// O processo filho acessa o mesmo kubeconfig e contexto do processo atual
+	cmd := exec.Command(exe, append(append([]string{ConnectCommand, "--" + ConnectBackgroundFlag}, clientOptions.Flags()...), args...)...)
+	cmd.Stdout = logOut
+	cmd.Stderr = logOut
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("erro ao iniciar o girus connect: %v", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+
+	deadline := time.After(timeout)
+	for {
+		select {
+		case <-exited:
+			return nil, fmt.Errorf("o girus connect terminou inesperadamente (log em %s)", paths.LogFile)
+		case <-deadline:
+			status, _ := QueryConnect()
+			return status, fmt.Errorf("tempo esgotado aguardando os port-forwards (log em %s)", paths.LogFile)
+		case <-time.After(500 * time.Millisecond):
+			if status, _ := QueryConnect(); status != nil && status.Connected() {
+				return status, nil
+			}
+		}
+	}
+}
+
+// SetupPortForward configura port-forward para os serviços do Girus, iniciando
+// o girus connect em segundo plano
+func SetupPortForward(namespace string) error {
+	// Criar formatador de cores
+	green := color.New(color.FgGreen).SprintFunc()
+	magenta := color.New(color.FgMagenta).SprintFunc()
+
+	// Encerrar o girus connect anterior para começar limpo
+	fmt.Println("   Limpando port-forwards existentes...")
+	if _, err := Disconnect(); err != nil {
+		return err
+	}
+
+	fmt.Println("   Configurando port-forward para o backend (" + magenta("8080") + ") e o frontend (" + magenta("8000") + ")...")
+	if _, err := StartConnect([]string{"--namespace", namespace}, 60*time.Second); err != nil {
+		return err
+	}
+
+	// Verificar conectividade do backend
+	fmt.Println("   Verificando conectividade do backend...")
+	if !waitHTTP("http://localhost:8080/api/v1/health", 5) {
+		return fmt.Errorf("não foi possível conectar ao backend após várias tentativas")
+	}
+	fmt.Printf("   %s %s conectado com sucesso!\n", green("SUCESSO:"), magenta("Backend"))
+
+	// Verificar se o frontend está acessível
+	fmt.Println("   Verificando conectividade do frontend...")
+	if !waitHTTP("http://localhost:8000", 5) {
+		return fmt.Errorf("não foi possível conectar ao frontend após várias tentativas")
+	}
+	fmt.Printf("   %s %s conectado com sucesso!\n", green("SUCESSO:"), magenta("Frontend"))
+
+	return nil
+}
+
+// waitHTTP tenta acessar a URL até obter uma resposta 2xx ou 3xx
+func waitHTTP(url string, attempts int) bool {
+	for i := 0; i < attempts; i++ {
+		if helpers.HTTPReachable(url) {
+			return true
+		}
+		time.Sleep(1 * time.Second)
+	}
+	return false
+}
+
+// waitPortFree espera até a porta local poder ser usada novamente
+func waitPortFree(port int, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err == nil {
+			l.Close()
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
@@ -0,0 +1,84 @@
+package k8s_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/k8s"
+)
+
+func TestConnectStatus(t *testing.T) {
+	status := &k8s.ConnectStatus{Forwards: []k8s.PortForwardState{
+		{PortForward: k8s.DefaultPortForwards[0], Connected: true, Pod: "girus-backend-1"},
+		{PortForward: k8s.DefaultPortForwards[1]},
+	}}
+	if status.Connected() {
+		t.Error("não deveria estar conectado com o frontend pendente")
+	}
+	status.Forwards[1].Connected = true
+	if !status.Connected() {
+		t.Error("esperado conectado")
+	}
+
+	pf, ok := status.Forward("girus-frontend")
+	if !ok || pf.LocalPort != 8000 {
+		t.Errorf("Forward(girus-frontend) = %+v, %v", pf, ok)
+	}
+	if _, ok := status.Forward("inexistente"); ok {
+		t.Error("serviço inexistente encontrado")
+	}
+	if (&k8s.ConnectStatus{}).Connected() {
+		t.Error("sem encaminhamentos não há conexão")
+	}
+}
+
+func TestConnectWithoutDaemon(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths, err := k8s.ConnectFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(paths.Socket), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Socket órfão de uma execução interrompida
+	if err := os.WriteFile(paths.Socket, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if status, err := k8s.QueryConnect(); status != nil || err != nil {
+		t.Errorf("QueryConnect = %+v, %v", status, err)
+	}
+
+	// Sem socket de controle o PID registrado (aqui, o do próprio teste) não
+	// recebe sinais; apenas o arquivo órfão é removido
+	if err := os.WriteFile(paths.PIDFile, []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if stopped, err := k8s.Disconnect(); stopped || err != nil {
+		t.Errorf("Disconnect = %v, %v", stopped, err)
+	}
+	if _, err := os.Stat(paths.PIDFile); !os.IsNotExist(err) {
+		t.Error("arquivo de PID deveria ter sido removido")
+	}
+}
+
+func TestServeConnectCleansUp(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	client, _ := newFakeClient()
+	pf := client.NewPortForwarder("girus", "127.0.0.1", k8s.DefaultPortForwards...)
+
+	// Sem configuração do cluster o port-forward falha e o socket é removido
+	if err := k8s.ServeConnect(context.Background(), pf); err == nil {
+		t.Fatal("esperado erro sem configuração do cluster")
+	}
+	paths, _ := k8s.ConnectFiles()
+	for _, f := range []string{paths.Socket, paths.PIDFile} {
+		if _, err := os.Stat(f); !os.IsNotExist(err) {
+			t.Errorf("%s não foi removido", f)
+		}
+	}
+}
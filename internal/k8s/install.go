@@ -0,0 +1,303 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// Labels aplicadas em todos os objetos criados pelo girus install. O
+// girus uninstall remove apenas os objetos com essas labels.
+const (
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	ManagedByValue = "girus-cli"
+	InstanceLabel  = "girus.linuxtips.io/instance"
+)
+
+// ApplyDeleted indica que o objeto foi removido pelo girus uninstall
+const ApplyDeleted ApplyAction = "deleted"
+
+// InstallSelector retorna o seletor de labels dos objetos de uma instalação
+func InstallSelector(namespace string) string {
+	return ManagedByLabel + "=" + ManagedByValue + "," + InstanceLabel + "=" + namespace
+}
+
+// PrepareInstall adapta os objetos dos manifestos embutidos para o namespace da
+// instalação (ver RetargetNamespace) e aplica as labels de rastreamento
+func PrepareInstall(objs []*unstructured.Unstructured, namespace string) {
+	RetargetNamespace(objs, namespace)
+	for _, obj := range objs {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[ManagedByLabel] = ManagedByValue
+		labels[InstanceLabel] = namespace
+		obj.SetLabels(labels)
+	}
+}
+
+// RetargetNamespace move os objetos do namespace girus para outro namespace:
+// troca o namespace (inclusive nos subjects dos bindings) e acrescenta o
+// namespace aos nomes dos objetos de escopo de cluster, para que instalações
+// em namespaces diferentes não colidam
+func RetargetNamespace(objs []*unstructured.Unstructured, namespace string) {
+	for _, obj := range objs {
+		kind := obj.GetKind()
+		switch {
+		case kind == "Namespace":
+			if obj.GetName() == DefaultNamespace {
+				obj.SetName(namespace)
+			}
+		case obj.GetNamespace() == DefaultNamespace || (obj.GetNamespace() == "" && !clusterScopedKind(kind)):
+			obj.SetNamespace(namespace)
+		}
+
+		if namespace != DefaultNamespace && (kind == "ClusterRole" || kind == "ClusterRoleBinding") && strings.HasPrefix(obj.GetName(), DefaultNamespace+"-") {
+			obj.SetName(obj.GetName() + "-" + namespace)
+		}
+
+		if subjects, ok, _ := unstructured.NestedSlice(obj.Object, "subjects"); ok {
+			for _, s := range subjects {
+				if subject, isMap := s.(map[string]interface{}); isMap && subject["namespace"] == DefaultNamespace {
+					subject["namespace"] = namespace
+				}
+			}
+			_ = unstructured.SetNestedSlice(obj.Object, subjects, "subjects")
+		}
+		if kind == "ClusterRoleBinding" && namespace != DefaultNamespace {
+			ref, _, _ := unstructured.NestedString(obj.Object, "roleRef", "name")
+			refKind, _, _ := unstructured.NestedString(obj.Object, "roleRef", "kind")
+			if refKind == "ClusterRole" && strings.HasPrefix(ref, DefaultNamespace+"-") {
+				_ = unstructured.SetNestedField(obj.Object, ref+"-"+namespace, "roleRef", "name")
+			}
+		}
+	}
+}
+
+// clusterScopedKind indica os tipos de escopo de cluster usados pelos manifestos
+func clusterScopedKind(kind string) bool {
+	switch kind {
+	case "Namespace", "ClusterRole", "ClusterRoleBinding":
+		return true
+	}
+	return false
+}
+
+// PermissionError lista as permissões que faltam ao usuário do kubeconfig
+type PermissionError struct {
+	Denied []string
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("permissões insuficientes no cluster:\n  - %s", strings.Join(e.Denied, "\n  - "))
+}
+
+// resourceAccess é uma permissão verificada com SelfSubjectAccessReview
+type resourceAccess struct {
+	Verb      string
+	Group     string
+	Resource  string
+	Namespace string
+}
+
+func (a resourceAccess) String() string {
+	resource := a.Resource
+	if a.Group != "" {
+		resource += "." + a.Group
+	}
+	if a.Namespace != "" {
+		return fmt.Sprintf("%s %s (namespace %s)", a.Verb, resource, a.Namespace)
+	}
+	return fmt.Sprintf("%s %s", a.Verb, resource)
+}
+
+// CheckPermissions verifica com SelfSubjectAccessReviews se o usuário pode
+// executar os verbos sobre os tipos dos objetos, retornando um
+// *PermissionError com as permissões negadas
+func (k *KubernetesClient) CheckPermissions(ctx context.Context, objs []*unstructured.Unstructured, verbs ...string) error {
+	seen := map[resourceAccess]bool{}
+	var checks []resourceAccess
+	for _, obj := range objs {
+		mapping, err := k.mapper.RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
+		if err != nil {
+			return fmt.Errorf("tipo %s desconhecido no cluster: %w", obj.GroupVersionKind(), err)
+		}
+		namespace := ""
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			namespace = obj.GetNamespace()
+		}
+		for _, verb := range verbs {
+			access := resourceAccess{Verb: verb, Group: mapping.Resource.Group, Resource: mapping.Resource.Resource, Namespace: namespace}
+			if !seen[access] {
+				seen[access] = true
+				checks = append(checks, access)
+			}
+		}
+	}
+
+	denied, err := k.deniedAccess(ctx, checks)
+	if err != nil {
+		return err
+	}
+	if len(denied) > 0 {
+		return &PermissionError{Denied: denied}
+	}
+	return nil
+}
+
+// deniedAccess verifica as permissões com SelfSubjectAccessReviews e retorna
+// as negadas
+func (k *KubernetesClient) deniedAccess(ctx context.Context, checks []resourceAccess) ([]string, error) {
+	var denied []string
+	for _, access := range checks {
+		review, err := k.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Verb:      access.Verb,
+					Group:     access.Group,
+					Resource:  access.Resource,
+					Namespace: access.Namespace,
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("falha ao verificar a permissão %s: %w", access, err)
+		}
+		if !review.Status.Allowed {
+			denied = append(denied, access.String())
+		}
+	}
+	return denied, nil
+}
+
+// Install aplica os manifestos no namespace informado com as labels de
+// rastreamento, depois de verificar as permissões necessárias. Namespaces que
+// já existiam sem as labels do GIRUS não são alterados, para que o girus
+// uninstall não os remova.
+func (k *KubernetesClient) Install(ctx context.Context, namespace string, manifests ...[]byte) ([]ApplyResult, error) {
+	objs, err := decodeAll(manifests)
+	if err != nil {
+		return nil, err
+	}
+	PrepareInstall(objs, namespace)
+
+	var install []*unstructured.Unstructured
+	for _, obj := range objs {
+		if obj.GetKind() == "Namespace" {
+			existing, err := k.clientset.CoreV1().Namespaces().Get(ctx, obj.GetName(), metav1.GetOptions{})
+			if err == nil && existing.Labels[InstanceLabel] == "" {
+				continue
+			}
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("falha ao buscar o namespace %s: %w", obj.GetName(), err)
+			}
+		}
+		install = append(install, obj)
+	}
+
+	if err := k.CheckPermissions(ctx, install, "get", "create", "patch"); err != nil {
+		return nil, err
+	}
+
+	applier := k.Applier()
+	applier.Namespace = namespace
+	return applier.ApplyObjects(ctx, install)
+}
+
+// Uninstall remove os objetos de uma instalação, identificados pelas labels de
+// rastreamento. Os manifestos informam os tipos e namespaces procurados; os
+// namespaces criados pela instalação são removidos por último.
+func (k *KubernetesClient) Uninstall(ctx context.Context, namespace string, manifests ...[]byte) ([]ApplyResult, error) {
+	objs, err := decodeAll(manifests)
+	if err != nil {
+		return nil, err
+	}
+	PrepareInstall(objs, namespace)
+
+	if err := k.CheckPermissions(ctx, objs, "list", "delete"); err != nil {
+		return nil, err
+	}
+
+	// Tipos e namespaces onde procurar os objetos da instalação
+	type target struct {
+		kind      string
+		resource  schema.GroupVersionResource
+		namespace string
+	}
+	seen := map[target]bool{}
+	var targets, namespaces []target
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		mapping, err := k.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, fmt.Errorf("tipo %s desconhecido no cluster: %w", gvk, err)
+		}
+		t := target{kind: gvk.Kind, resource: mapping.Resource}
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			t.namespace = obj.GetNamespace()
+		}
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		if gvk.Kind == "Namespace" {
+			namespaces = append(namespaces, t)
+		} else {
+			targets = append(targets, t)
+		}
+	}
+
+	var results []ApplyResult
+	var errs []error
+	for _, t := range append(targets, namespaces...) {
+		var ri dynamic.ResourceInterface = k.dynamic.Resource(t.resource)
+		if t.namespace != "" {
+			ri = k.dynamic.Resource(t.resource).Namespace(t.namespace)
+		}
+
+		list, err := ri.List(ctx, metav1.ListOptions{LabelSelector: InstallSelector(namespace)})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("falha ao listar %s: %w", t.resource.Resource, err))
+			continue
+		}
+		sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].GetName() < list.Items[j].GetName() })
+
+		for _, item := range list.Items {
+			policy := metav1.DeletePropagationBackground
+			err := ri.Delete(ctx, item.GetName(), metav1.DeleteOptions{PropagationPolicy: &policy})
+			if err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, &ApplyError{Kind: t.kind, Namespace: t.namespace, Name: item.GetName(), Err: err})
+				continue
+			}
+			results = append(results, ApplyResult{Kind: t.kind, Namespace: t.namespace, Name: item.GetName(), Action: ApplyDeleted})
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// decodeAll interpreta os manifestos, na ordem informada
+func decodeAll(manifests [][]byte) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	for _, data := range manifests {
+		decoded, err := DecodeManifests(data)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, decoded...)
+	}
+	return objs, nil
+}
@@ -0,0 +1,186 @@
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/k8s/k8sfake"
+	"github.com/badtuxx/girus-cli/internal/templates"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newFakeInstallClient cria um cliente com clientset e cliente dinâmico fake. As
+// SelfSubjectAccessReviews são permitidas, exceto para os recursos em denied
+// (no formato verbo/recurso).
+func newFakeInstallClient(t *testing.T, denied ...string) (*k8s.KubernetesClient, *fake.Clientset, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	clientset := fake.NewSimpleClientset()
+	k8sfake.AllowAccessReviews(clientset, denied...)
+	dynamicClient, mapper := newFakeDynamic(t)
+	return k8s.NewKubernetesClientFromClients(clientset, dynamicClient, mapper), clientset, dynamicClient
+}
+
+func embeddedInstall(t *testing.T) [][]byte {
+	t.Helper()
+	deployment, err := templates.GetManifest("defaultDeployment.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	labTemplate, err := templates.GetManifest("lab_01_linux_processamento-texto.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return [][]byte{deployment, labTemplate}
+}
+
+var (
+	namespacesResource   = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	configMapsResource   = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	clusterRolesResource = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}
+	bindingsResource     = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}
+)
+
+func TestPrepareInstall(t *testing.T) {
+	objs, err := k8s.DecodeManifests(embeddedInstall(t)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	k8s.PrepareInstall(objs, "treinamento")
+
+	for _, obj := range objs {
+		if obj.GetNamespace() == k8s.DefaultNamespace || (obj.GetKind() == "Namespace" && obj.GetName() == k8s.DefaultNamespace) {
+			t.Errorf("%s/%s ainda usa o namespace girus", obj.GetKind(), obj.GetName())
+		}
+		labels := obj.GetLabels()
+		if labels[k8s.ManagedByLabel] != k8s.ManagedByValue || labels[k8s.InstanceLabel] != "treinamento" {
+			t.Errorf("%s/%s sem labels de rastreamento: %v", obj.GetKind(), obj.GetName(), labels)
+		}
+
+		switch obj.GetName() {
+		case "girus-cluster-rolebinding-treinamento":
+			subjects := obj.Object["subjects"].([]interface{})
+			if ns := subjects[0].(map[string]interface{})["namespace"]; ns != "treinamento" {
+				t.Errorf("subject no namespace %v", ns)
+			}
+			if ref := obj.Object["roleRef"].(map[string]interface{})["name"]; ref != "girus-cluster-role-treinamento" {
+				t.Errorf("roleRef = %v", ref)
+			}
+		case "girus-cluster-role", "girus-cluster-rolebinding":
+			t.Errorf("%s/%s sem o sufixo do namespace", obj.GetKind(), obj.GetName())
+		}
+	}
+}
+
+func TestInstallAndUninstall(t *testing.T) {
+	client, _, dynamicClient := newFakeInstallClient(t)
+	ctx := context.Background()
+	manifests := embeddedInstall(t)
+
+	results, err := client.Install(ctx, "treinamento", manifests...)
+	if err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if counts := countActions(results); counts[k8s.ApplyCreated] != len(results) || len(results) == 0 {
+		t.Errorf("ações = %v", counts)
+	}
+	cm, err := dynamicClient.Resource(configMapsResource).Namespace("treinamento").Get(ctx, "linux-processamento-texto-lab", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("template de laboratório não instalado: %v", err)
+	}
+	if cm.GetLabels()["app"] != "girus-lab-template" {
+		t.Errorf("labels originais perdidas: %v", cm.GetLabels())
+	}
+
+	// Objeto sem as labels da instalação não é removido
+	externo := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "externo", "namespace": "treinamento"},
+	}}
+	if _, err := dynamicClient.Resource(configMapsResource).Namespace("treinamento").Create(ctx, externo, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := client.Uninstall(ctx, "treinamento", manifests...)
+	if err != nil {
+		t.Fatalf("Uninstall: %v", err)
+	}
+	if len(removed) != len(results) {
+		t.Errorf("removidos %d objetos, instalados %d", len(removed), len(results))
+	}
+	if last := removed[len(removed)-1]; last.Kind != "Namespace" {
+		t.Errorf("namespaces devem ser removidos por último, último: %s", last)
+	}
+	if _, err := dynamicClient.Resource(configMapsResource).Namespace("treinamento").Get(ctx, "externo", metav1.GetOptions{}); err != nil {
+		t.Errorf("objeto sem labels foi removido: %v", err)
+	}
+	for _, r := range []schema.GroupVersionResource{clusterRolesResource, bindingsResource, namespacesResource} {
+		list, err := dynamicClient.Resource(r).List(ctx, metav1.ListOptions{LabelSelector: k8s.InstallSelector("treinamento")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list.Items) > 0 {
+			t.Errorf("%s restantes: %d", r.Resource, len(list.Items))
+		}
+	}
+}
+
+func TestInstallKeepsExistingNamespace(t *testing.T) {
+	client, clientset, dynamicClient := newFakeInstallClient(t)
+	ctx := context.Background()
+	existing := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "compartilhado"}}
+	if _, err := clientset.CoreV1().Namespaces().Create(ctx, existing, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := client.Install(ctx, "compartilhado", embeddedInstall(t)...)
+	if err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	for _, r := range results {
+		if r.Kind == "Namespace" && r.Name == "compartilhado" {
+			t.Error("namespace existente não deveria ser aplicado")
+		}
+	}
+
+	removed, err := client.Uninstall(ctx, "compartilhado", embeddedInstall(t)...)
+	if err != nil {
+		t.Fatalf("Uninstall: %v", err)
+	}
+	for _, r := range removed {
+		if r.Kind == "Namespace" && r.Name == "compartilhado" {
+			t.Error("namespace existente não deveria ser removido")
+		}
+	}
+	list, _ := dynamicClient.Resource(configMapsResource).Namespace("compartilhado").List(ctx, metav1.ListOptions{})
+	if len(list.Items) != 0 {
+		t.Errorf("configmaps restantes: %d", len(list.Items))
+	}
+}
+
+func TestInstallPermissionDenied(t *testing.T) {
+	client, _, dynamicClient := newFakeInstallClient(t, "create/clusterrolebindings", "patch/deployments")
+
+	_, err := client.Install(context.Background(), "girus", embeddedInstall(t)...)
+	var permErr *k8s.PermissionError
+	if !errors.As(err, &permErr) {
+		t.Fatalf("esperado *PermissionError, obtido %v", err)
+	}
+	want := []string{"create clusterrolebindings.rbac.authorization.k8s.io", "patch deployments.apps (namespace girus)"}
+	if strings.Join(permErr.Denied, ";") != strings.Join(want, ";") {
+		t.Errorf("negadas = %q, esperado %q", permErr.Denied, want)
+	}
+	for _, action := range dynamicClient.Actions() {
+		if action.GetVerb() == "patch" {
+			t.Fatalf("nada deveria ser aplicado sem permissão: %v", action)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Package k8sfake cria clientes do GIRUS sobre os clientes fake do client-go,
+// permitindo testar os comandos sem um cluster
+package k8sfake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+// NewDynamic cria o cliente dinâmico fake e o RESTMapper dos tipos usados nos
+// manifestos. O tracker do fake não implementa apply para objetos não
+// estruturados, então um reactor simula o servidor: cria o objeto se ele não
+// existir ou o substitui pela configuração aplicada.
+func NewDynamic() (*dynamicfake.FakeDynamicClient, meta.RESTMapper) {
+	client := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+	client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return true, nil, fmt.Errorf("esperado server-side apply, obtido patch %s", patch.GetPatchType())
+		}
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal(patch.GetPatch(), &obj.Object); err != nil {
+			return true, nil, err
+		}
+		tracker := client.Tracker()
+		if _, err := tracker.Get(patch.GetResource(), patch.GetNamespace(), patch.GetName()); apierrors.IsNotFound(err) {
+			return true, obj, tracker.Create(patch.GetResource(), obj, patch.GetNamespace())
+		}
+		return true, obj, tracker.Update(patch.GetResource(), obj, patch.GetNamespace())
+	})
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	namespaced := []schema.GroupVersionKind{
+		{Version: "v1", Kind: "ConfigMap"},
+		{Version: "v1", Kind: "Service"},
+		{Version: "v1", Kind: "ServiceAccount"},
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"},
+	}
+	cluster := []schema.GroupVersionKind{
+		{Version: "v1", Kind: "Namespace"},
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"},
+		{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService"},
+	}
+	for _, gvk := range namespaced {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	for _, gvk := range cluster {
+		mapper.Add(gvk, meta.RESTScopeRoot)
+	}
+
+	return client, mapper
+}
+
+// AllowAccessReviews responde às SelfSubjectAccessReviews do clientset
+// permitindo todos os acessos, exceto os recursos em denied (no formato
+// verbo/recurso)
+func AllowAccessReviews(clientset *fake.Clientset, denied ...string) {
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = true
+		for _, d := range denied {
+			if d == attrs.Verb+"/"+attrs.Resource {
+				review.Status.Allowed = false
+			}
+		}
+		return true, review, nil
+	})
+}
+
+// BackendHealth responde às requisições ao /api/v1/health do backend feitas
+// pelo proxy de serviços do clientset; err faz o endpoint falhar
+func BackendHealth(clientset *fake.Clientset, err error) {
+	clientset.PrependProxyReactor("services", func(k8stesting.Action) (bool, restclient.ResponseWrapper, error) {
+		return true, healthResponse{err: err}, nil
+	})
+}
+
+// healthResponse é a resposta do endpoint de saúde do backend
+type healthResponse struct{ err error }
+
+func (r healthResponse) DoRaw(context.Context) ([]byte, error) {
+	return []byte(`{"status":"ok"}`), r.err
+}
+
+func (r healthResponse) Stream(context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(`{"status":"ok"}`)), r.err
+}
+
+// NewClient cria um KubernetesClient com clientset, cliente dinâmico e cliente
+// de métricas fake. Os objetos informados são criados no clientset, as
+// SelfSubjectAccessReviews são permitidas e as métricas começam vazias e podem
+// ser substituídas com WithMetrics.
+func NewClient(objects ...runtime.Object) (*k8s.KubernetesClient, *fake.Clientset, *dynamicfake.FakeDynamicClient) {
+	clientset := fake.NewSimpleClientset(objects...)
+	AllowAccessReviews(clientset)
+	dynamicClient, mapper := NewDynamic()
+	client := k8s.NewKubernetesClientFromClients(clientset, dynamicClient, mapper).WithMetrics(metricsfake.NewSimpleClientset())
+	return client, clientset, dynamicClient
+}
@@ -0,0 +1,145 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// Anotações de procedência gravadas nos ConfigMaps dos laboratórios instalados
+// a partir de um repositório
+const (
+	AnnotationLabRepository  = "girus.linuxtips.io/repository"
+	AnnotationLabID          = "girus.linuxtips.io/lab-id"
+	AnnotationLabVersion     = "girus.linuxtips.io/version"
+	AnnotationLabDigest      = "girus.linuxtips.io/digest"
+	AnnotationLabSource      = "girus.linuxtips.io/source"
+	AnnotationLabInstalledAt = "girus.linuxtips.io/installed-at"
+)
+
+// configMapsResource é o recurso dos ConfigMaps, lidos pelo cliente dinâmico
+// usado também para aplicar os laboratórios
+var configMapsResource = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+// LabProvenance registra de onde um laboratório instalado veio
+type LabProvenance struct {
+	Repository string `json:"repository"`
+	LabID      string `json:"id"`
+	Version    string `json:"version"`
+	// Digest é o sha256 do manifesto baixado, no formato sha256:<hex>
+	Digest      string    `json:"digest"`
+	Source      string    `json:"source"`
+	InstalledAt time.Time `json:"installedAt"`
+}
+
+// Annotations retorna as anotações que registram a procedência no ConfigMap
+func (p *LabProvenance) Annotations() map[string]string {
+	return map[string]string{
+		AnnotationLabRepository:  p.Repository,
+		AnnotationLabID:          p.LabID,
+		AnnotationLabVersion:     p.Version,
+		AnnotationLabDigest:      p.Digest,
+		AnnotationLabSource:      p.Source,
+		AnnotationLabInstalledAt: p.InstalledAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// provenanceFromAnnotations lê a procedência das anotações do ConfigMap,
+// retornando nil para laboratórios que não vieram de um repositório
+func provenanceFromAnnotations(annotations map[string]string) *LabProvenance {
+	if annotations[AnnotationLabRepository] == "" {
+		return nil
+	}
+	installedAt, _ := time.Parse(time.RFC3339, annotations[AnnotationLabInstalledAt])
+	return &LabProvenance{
+		Repository:  annotations[AnnotationLabRepository],
+		LabID:       annotations[AnnotationLabID],
+		Version:     annotations[AnnotationLabVersion],
+		Digest:      annotations[AnnotationLabDigest],
+		Source:      annotations[AnnotationLabSource],
+		InstalledAt: installedAt,
+	}
+}
+
+// InstalledLab é um template de laboratório aplicado no cluster
+type InstalledLab struct {
+	ConfigMap string
+	// Name e Title vêm do lab.yaml do ConfigMap
+	Name  string
+	Title string
+	// Provenance é nil quando o laboratório não foi instalado de um repositório
+	Provenance *LabProvenance
+}
+
+// ApplyLab aplica os manifestos de um laboratório como Apply, gravando a
+// procedência nos ConfigMaps de template quando provenance não é nil
+func (k *KubernetesClient) ApplyLab(ctx context.Context, data []byte, provenance *LabProvenance) ([]ApplyResult, error) {
+	objs, err := DecodeManifests(data)
+	if err != nil {
+		return nil, err
+	}
+	RetargetNamespace(objs, Namespace())
+
+	if provenance != nil {
+		for _, obj := range objs {
+			if obj.GetKind() != "ConfigMap" || obj.GetLabels()["app"] != "girus-lab-template" {
+				continue
+			}
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			for key, value := range provenance.Annotations() {
+				annotations[key] = value
+			}
+			obj.SetAnnotations(annotations)
+		}
+	}
+
+	applier := k.Applier()
+	applier.Namespace = Namespace()
+	return applier.ApplyObjects(ctx, objs)
+}
+
+// InstalledLabs lista os templates de laboratório do namespace, ordenados pelo
+// nome do ConfigMap
+func (k *KubernetesClient) InstalledLabs(ctx context.Context, namespace string) ([]InstalledLab, error) {
+	configMaps, err := k.dynamic.Resource(configMapsResource).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: LabTemplateSelector})
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar os laboratórios instalados: %w", err)
+	}
+
+	labs := []InstalledLab{}
+	for _, cm := range configMaps.Items {
+		var template struct {
+			Name  string `json:"name"`
+			Title string `json:"title"`
+		}
+		manifest, _, _ := unstructured.NestedString(cm.Object, "data", "lab.yaml")
+		_ = yaml.Unmarshal([]byte(manifest), &template)
+		labs = append(labs, InstalledLab{
+			ConfigMap:  cm.GetName(),
+			Name:       template.Name,
+			Title:      template.Title,
+			Provenance: provenanceFromAnnotations(cm.GetAnnotations()),
+		})
+	}
+	sort.Slice(labs, func(i, j int) bool { return labs[i].ConfigMap < labs[j].ConfigMap })
+	return labs, nil
+}
+
+// DeleteLab remove o ConfigMap de um template de laboratório
+func (k *KubernetesClient) DeleteLab(ctx context.Context, namespace, configMap string) error {
+	err := k.dynamic.Resource(configMapsResource).Namespace(namespace).Delete(ctx, configMap, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("falha ao remover o ConfigMap %s: %w", configMap, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/k8s/k8sfake"
+)
+
+const labManifests = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: linux-lab
+  namespace: girus
+  labels:
+    app: girus-lab-template
+data:
+  lab.yaml: |
+    name: linux-basico
+    title: Linux Básico
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: linux-extra
+  namespace: girus
+data:
+  script.sh: echo ok
+`
+
+func TestApplyLabProvenance(t *testing.T) {
+	client, _, _ := k8sfake.NewClient()
+	ctx := context.Background()
+	installedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	provenance := &k8s.LabProvenance{
+		Repository:  "oficial",
+		LabID:       "linux-basico",
+		Version:     "1.2.0",
+		Digest:      "sha256:abc",
+		Source:      "https://exemplo.com/linux/lab.yaml",
+		InstalledAt: installedAt,
+	}
+
+	if _, err := client.ApplyLab(ctx, []byte(labManifests), provenance); err != nil {
+		t.Fatalf("ApplyLab: %v", err)
+	}
+	if _, err := client.ApplyLab(ctx, []byte(labManifests), nil); err != nil {
+		t.Fatalf("ApplyLab sem procedência: %v", err)
+	}
+
+	// Apenas o template é listado, sem procedência após a reaplicação sem ela
+	labs, err := client.InstalledLabs(ctx, "girus")
+	if err != nil {
+		t.Fatalf("InstalledLabs: %v", err)
+	}
+	if len(labs) != 1 || labs[0].ConfigMap != "linux-lab" || labs[0].Name != "linux-basico" || labs[0].Title != "Linux Básico" || labs[0].Provenance != nil {
+		t.Fatalf("labs = %+v", labs)
+	}
+
+	if _, err := client.ApplyLab(ctx, []byte(labManifests), provenance); err != nil {
+		t.Fatal(err)
+	}
+	labs, err = client.InstalledLabs(ctx, "girus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := labs[0].Provenance; got == nil || *got != *provenance {
+		t.Errorf("procedência = %+v, esperado %+v", got, provenance)
+	}
+
+	if err := client.DeleteLab(ctx, "girus", "linux-lab"); err != nil {
+		t.Fatalf("DeleteLab: %v", err)
+	}
+	if err := client.DeleteLab(ctx, "girus", "linux-lab"); err != nil {
+		t.Errorf("DeleteLab de um ConfigMap inexistente: %v", err)
+	}
+	if labs, _ := client.InstalledLabs(ctx, "girus"); len(labs) != 0 {
+		t.Errorf("labs após remoção = %+v", labs)
+	}
+}
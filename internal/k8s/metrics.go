@@ -0,0 +1,288 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrMetricsUnavailable indica que a API metrics.k8s.io não responde, em geral
+// porque o metrics-server não está instalado ou ainda não ficou pronto
+var ErrMetricsUnavailable = errors.New("API de métricas (metrics.k8s.io) indisponível: o metrics-server não está instalado ou ainda não está pronto")
+
+// MetricsServerVersion é a versão do metrics-server instalada pelo girus status
+const MetricsServerVersion = "v0.8.0"
+
+// MetricsServerManifestURL é o manifesto oficial de instalação do
+// metrics-server, fixado em MetricsServerVersion
+var MetricsServerManifestURL = "https://github.com/kubernetes-sigs/metrics-server/releases/download/" + MetricsServerVersion + "/components.yaml"
+
+// LimitWarnPercent é a porcentagem do limite a partir da qual o uso de um pod
+// de laboratório é destacado
+const LimitWarnPercent = 90
+
+// LabConfig é a configuração dos pods de laboratório definida na chave
+// config.yaml do ConfigMap girus-config
+type LabConfig struct {
+	// PodNamePrefix e ContainerName identificam os pods de laboratório criados
+	// pelo backend
+	PodNamePrefix string
+	ContainerName string
+	Resources     ResourceConfig
+}
+
+// defaultLabConfig são os valores usados pelo backend quando o girus-config não
+// os define
+var defaultLabConfig = LabConfig{PodNamePrefix: "lab", ContainerName: "linux-lab"}
+
+// NodeUsage é o consumo de CPU e memória de um nó
+type NodeUsage struct {
+	Name string
+	// CPU em milicores
+	CPU            int64
+	CPUAllocatable int64
+	// Memória em bytes
+	Memory            int64
+	MemoryAllocatable int64
+}
+
+// PodUsage é o consumo de CPU (milicores) e memória (bytes) de um pod
+type PodUsage struct {
+	Namespace string
+	Name      string
+	// Lab indica um pod de laboratório
+	Lab    bool
+	CPU    int64
+	Memory int64
+	// CPULimit e MemoryLimit são os limites do girus-config para os pods de
+	// laboratório; zero quando não há limite definido
+	CPULimit    int64
+	MemoryLimit int64
+}
+
+// NearLimit indica se o pod atingiu LimitWarnPercent de algum dos limites
+func (p PodUsage) NearLimit() bool {
+	return (p.CPULimit > 0 && p.CPU*100 >= p.CPULimit*LimitWarnPercent) ||
+		(p.MemoryLimit > 0 && p.Memory*100 >= p.MemoryLimit*LimitWarnPercent)
+}
+
+// ResourceReport é o consumo de recursos do cluster medido pelo metrics-server
+type ResourceReport struct {
+	Nodes []NodeUsage
+	// Pods traz os pods do namespace do GIRUS e os pods de laboratório de
+	// todos os namespaces
+	Pods []PodUsage
+	Lab  LabConfig
+}
+
+// LabConfig lê a configuração dos pods de laboratório do ConfigMap
+// girus-config. Sem o ConfigMap, os valores padrão do backend são retornados.
+func (k *KubernetesClient) LabConfig(ctx context.Context, namespace string) (LabConfig, error) {
+	cfg := defaultLabConfig
+	cm, err := k.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, "girus-config", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("falha ao ler o ConfigMap girus-config: %w", err)
+	}
+
+	var config struct {
+		Lab struct {
+			PodNamePrefix string `json:"podNamePrefix"`
+			ContainerName string `json:"containerName"`
+			Resources     struct {
+				CPURequest    string `json:"cpuRequest"`
+				CPULimit      string `json:"cpuLimit"`
+				MemoryRequest string `json:"memoryRequest"`
+				MemoryLimit   string `json:"memoryLimit"`
+			} `json:"resources"`
+		} `json:"lab"`
+	}
+	if err := yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &config); err != nil {
+		return cfg, fmt.Errorf("erro ao interpretar o config.yaml do girus-config: %w", err)
+	}
+	if config.Lab.PodNamePrefix != "" {
+		cfg.PodNamePrefix = config.Lab.PodNamePrefix
+	}
+	if config.Lab.ContainerName != "" {
+		cfg.ContainerName = config.Lab.ContainerName
+	}
+	res := config.Lab.Resources
+	cfg.Resources = ResourceConfig{CPURequest: res.CPURequest, CPULimit: res.CPULimit, MemoryRequest: res.MemoryRequest, MemoryLimit: res.MemoryLimit}
+	return cfg, nil
+}
+
+// ResourceReport consulta a API metrics.k8s.io e retorna o consumo dos nós, dos
+// pods do namespace do GIRUS e dos pods de laboratório, comparando os pods de
+// laboratório com os limites do girus-config. Sem o metrics-server o erro
+// retornado é ErrMetricsUnavailable.
+func (k *KubernetesClient) ResourceReport(ctx context.Context, namespace string) (*ResourceReport, error) {
+	if k.metrics == nil {
+		return nil, ErrMetricsUnavailable
+	}
+
+	nodeMetrics, err := k.metrics.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, metricsError(err)
+	}
+	podMetrics, err := k.metrics.MetricsV1beta1().PodMetricses(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, metricsError(err)
+	}
+
+	labConfig, err := k.LabConfig(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	report := &ResourceReport{Lab: labConfig}
+
+	allocatable := map[string]corev1.ResourceList{}
+	if nodes, err := k.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{}); err == nil {
+		for _, node := range nodes.Items {
+			allocatable[node.Name] = node.Status.Allocatable
+		}
+	}
+	for _, m := range nodeMetrics.Items {
+		capacity := allocatable[m.Name]
+		report.Nodes = append(report.Nodes, NodeUsage{
+			Name:              m.Name,
+			CPU:               m.Usage.Cpu().MilliValue(),
+			Memory:            m.Usage.Memory().Value(),
+			CPUAllocatable:    capacity.Cpu().MilliValue(),
+			MemoryAllocatable: capacity.Memory().Value(),
+		})
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].Name < report.Nodes[j].Name })
+
+	cpuLimit := quantityOrZero(labConfig.Resources.CPULimit)
+	memoryLimit := quantityOrZero(labConfig.Resources.MemoryLimit)
+	for _, m := range podMetrics.Items {
+		lab := labConfig.isLabPod(m)
+		if !lab && m.Namespace != namespace {
+			continue
+		}
+		usage := PodUsage{Namespace: m.Namespace, Name: m.Name, Lab: lab}
+		for _, c := range m.Containers {
+			usage.CPU += c.Usage.Cpu().MilliValue()
+			usage.Memory += c.Usage.Memory().Value()
+		}
+		if lab {
+			usage.CPULimit, usage.MemoryLimit = cpuLimit.MilliValue(), memoryLimit.Value()
+		}
+		report.Pods = append(report.Pods, usage)
+	}
+	sort.Slice(report.Pods, func(i, j int) bool {
+		if report.Pods[i].Namespace != report.Pods[j].Namespace {
+			return report.Pods[i].Namespace < report.Pods[j].Namespace
+		}
+		return report.Pods[i].Name < report.Pods[j].Name
+	})
+	return report, nil
+}
+
+// isLabPod identifica os pods de laboratório pelo nome do container ou pelo
+// prefixo do nome do pod definidos no girus-config
+func (c LabConfig) isLabPod(m metricsv1beta1.PodMetrics) bool {
+	if c.PodNamePrefix != "" && strings.HasPrefix(m.Name, c.PodNamePrefix+"-") {
+		return true
+	}
+	for _, container := range m.Containers {
+		if container.Name == c.ContainerName {
+			return true
+		}
+	}
+	return false
+}
+
+// metricsError converte as respostas de API ausente (404) ou indisponível (503)
+// em ErrMetricsUnavailable
+func metricsError(err error) error {
+	if apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) {
+		return fmt.Errorf("%w (%v)", ErrMetricsUnavailable, err)
+	}
+	return fmt.Errorf("falha ao consultar a API de métricas: %w", err)
+}
+
+func quantityOrZero(value string) resource.Quantity {
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}
+	}
+	return q
+}
+
+// InstallMetricsServer baixa o manifesto oficial do metrics-server e o aplica
+// no cluster. Com insecureTLS o metrics-server aceita os certificados
+// autoassinados dos kubelets, como nos clusters locais do kind, k3d e
+// minikube.
+func (k *KubernetesClient) InstallMetricsServer(ctx context.Context, insecureTLS bool) ([]ApplyResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, MetricsServerManifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao baixar o manifesto do metrics-server: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("erro HTTP %d ao baixar o manifesto do metrics-server", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o manifesto do metrics-server: %w", err)
+	}
+
+	objs, err := DecodeManifests(data)
+	if err != nil {
+		return nil, fmt.Errorf("manifesto do metrics-server inválido: %w", err)
+	}
+	if insecureTLS {
+		for _, obj := range objs {
+			if obj.GetKind() == "Deployment" && obj.GetName() == "metrics-server" {
+				if err := addContainerArg(obj, "--kubelet-insecure-tls"); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return k.Applier().ApplyObjects(ctx, objs)
+}
+
+// addContainerArg acrescenta o argumento ao primeiro container do Deployment
+func addContainerArg(obj *unstructured.Unstructured, arg string) error {
+	containers, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if err != nil || !found || len(containers) == 0 {
+		return fmt.Errorf("deployment %s sem containers", obj.GetName())
+	}
+	container, ok := containers[0].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("deployment %s com container inválido", obj.GetName())
+	}
+	args, _, _ := unstructured.NestedStringSlice(container, "args")
+	for _, a := range args {
+		if a == arg {
+			return nil
+		}
+	}
+	if err := unstructured.SetNestedStringSlice(container, append(args, arg), "args"); err != nil {
+		return err
+	}
+	containers[0] = container
+	return unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+}
@@ -0,0 +1,168 @@
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/k8s/k8sfake"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+func usage(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)}
+}
+
+func podMetrics(namespace, name, container, cpu, memory string) metricsv1beta1.PodMetrics {
+	return metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Containers: []metricsv1beta1.ContainerMetrics{{Name: container, Usage: usage(cpu, memory)}},
+	}
+}
+
+// fakeMetrics cria o cliente de métricas fake. O tracker do fake registra as
+// métricas com um nome de recurso diferente do usado na listagem, então as
+// listas são respondidas por reactors.
+func fakeMetrics(nodes []metricsv1beta1.NodeMetrics, pods []metricsv1beta1.PodMetrics) *metricsfake.Clientset {
+	metrics := metricsfake.NewSimpleClientset()
+	metrics.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &metricsv1beta1.NodeMetricsList{Items: nodes}, nil
+	})
+	metrics.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &metricsv1beta1.PodMetricsList{Items: pods}, nil
+	})
+	return metrics
+}
+
+func TestResourceReport(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "girus-control-plane"},
+		Status:     corev1.NodeStatus{Allocatable: usage("4", "8Gi")},
+	}
+	config := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "girus-config", Namespace: "girus"},
+		Data: map[string]string{"config.yaml": `lab:
+  podNamePrefix: "lab"
+  containerName: "linux-lab"
+  resources:
+    cpuLimit: "500m"
+    memoryLimit: "256Mi"
+`},
+	}
+	client, _, _ := k8sfake.NewClient(node, config)
+	client.WithMetrics(fakeMetrics(
+		[]metricsv1beta1.NodeMetrics{{ObjectMeta: metav1.ObjectMeta{Name: "girus-control-plane"}, Usage: usage("1500m", "2Gi")}},
+		[]metricsv1beta1.PodMetrics{
+			podMetrics("girus", "girus-backend-1", "backend", "20m", "64Mi"),
+			podMetrics("aluno-1", "lab-linux-abc", "linux-lab", "480m", "100Mi"),
+			podMetrics("aluno-2", "terminal", "linux-lab", "10m", "32Mi"),
+			podMetrics("kube-system", "coredns-1", "coredns", "5m", "20Mi"),
+		},
+	))
+
+	report, err := client.ResourceReport(context.Background(), "girus")
+	if err != nil {
+		t.Fatalf("ResourceReport: %v", err)
+	}
+
+	wantNode := k8s.NodeUsage{Name: "girus-control-plane", CPU: 1500, CPUAllocatable: 4000, Memory: 2 << 30, MemoryAllocatable: 8 << 30}
+	if len(report.Nodes) != 1 || report.Nodes[0] != wantNode {
+		t.Errorf("Nodes = %+v, esperado %+v", report.Nodes, wantNode)
+	}
+
+	wantPods := []k8s.PodUsage{
+		{Namespace: "aluno-1", Name: "lab-linux-abc", Lab: true, CPU: 480, Memory: 100 << 20, CPULimit: 500, MemoryLimit: 256 << 20},
+		{Namespace: "aluno-2", Name: "terminal", Lab: true, CPU: 10, Memory: 32 << 20, CPULimit: 500, MemoryLimit: 256 << 20},
+		{Namespace: "girus", Name: "girus-backend-1", CPU: 20, Memory: 64 << 20},
+	}
+	if len(report.Pods) != len(wantPods) {
+		t.Fatalf("Pods = %+v", report.Pods)
+	}
+	for i, want := range wantPods {
+		if report.Pods[i] != want {
+			t.Errorf("Pods[%d] = %+v, esperado %+v", i, report.Pods[i], want)
+		}
+	}
+	if !report.Pods[0].NearLimit() || report.Pods[1].NearLimit() {
+		t.Errorf("NearLimit incorreto: %+v", report.Pods)
+	}
+	if report.Lab.Resources.CPULimit != "500m" {
+		t.Errorf("Lab = %+v", report.Lab)
+	}
+}
+
+func TestResourceReportWithoutMetricsServer(t *testing.T) {
+	metrics := metricsfake.NewSimpleClientset()
+	metrics.PrependReactor("list", "*", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "metrics.k8s.io", Resource: "nodes"}, "")
+	})
+	client, _, _ := k8sfake.NewClient()
+	client.WithMetrics(metrics)
+
+	_, err := client.ResourceReport(context.Background(), "girus")
+	if !errors.Is(err, k8s.ErrMetricsUnavailable) {
+		t.Fatalf("erro = %v, esperado ErrMetricsUnavailable", err)
+	}
+}
+
+func TestInstallMetricsServer(t *testing.T) {
+	manifest := `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: metrics-server
+  namespace: kube-system
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: metrics-server
+  namespace: kube-system
+spec:
+  template:
+    spec:
+      containers:
+        - name: metrics-server
+          image: registry.k8s.io/metrics-server/metrics-server:v0.8.0
+          args:
+            - --secure-port=10250
+`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(manifest))
+	}))
+	defer server.Close()
+	prev := k8s.MetricsServerManifestURL
+	k8s.MetricsServerManifestURL = server.URL
+	defer func() { k8s.MetricsServerManifestURL = prev }()
+
+	client, _, dynamicClient := k8sfake.NewClient()
+	results, err := client.InstallMetricsServer(context.Background(), true)
+	if err != nil {
+		t.Fatalf("InstallMetricsServer: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("resultados = %v", results)
+	}
+
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	obj, err := dynamicClient.Tracker().Get(gvr, "kube-system", "metrics-server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	containers, _, _ := unstructured.NestedSlice(obj.(*unstructured.Unstructured).Object, "spec", "template", "spec", "containers")
+	args, _, _ := unstructured.NestedStringSlice(containers[0].(map[string]interface{}), "args")
+	if len(args) != 2 || args[1] != "--kubelet-insecure-tls" {
+		t.Errorf("args = %v", args)
+	}
+}
@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// DefaultNamespace é o namespace usado pelos manifestos embutidos do GIRUS
+const DefaultNamespace = "girus"
+
+// ClientOptions seleciona o cluster e o namespace usados pelo GIRUS. Campos
+// vazios usam o kubeconfig padrão (variável KUBECONFIG ou ~/.kube/config), o
+// contexto atual do kubeconfig e o namespace girus.
+type ClientOptions struct {
+	Kubeconfig string
+	Context    string
+	Namespace  string
+}
+
+var clientOptions ClientOptions
+
+// SetClientOptions define as opções usadas pelos clientes criados com
+// NewKubernetesClient e pelos comandos do kubectl montados com KubectlArgs
+func SetClientOptions(opts ClientOptions) {
+	clientOptions = opts
+}
+
+// CurrentClientOptions retorna as opções definidas com SetClientOptions
+func CurrentClientOptions() ClientOptions {
+	return clientOptions
+}
+
+// Namespace retorna o namespace do GIRUS
+func Namespace() string {
+	if clientOptions.Namespace != "" {
+		return clientOptions.Namespace
+	}
+	return DefaultNamespace
+}
+
+// Flags retorna as flags --kubeconfig e --context equivalentes às opções,
+// aceitas tanto pelo kubectl quanto pelo girus
+func (o ClientOptions) Flags() []string {
+	var flags []string
+	if o.Kubeconfig != "" {
+		flags = append(flags, "--kubeconfig", o.Kubeconfig)
+	}
+	if o.Context != "" {
+		flags = append(flags, "--context", o.Context)
+	}
+	return flags
+}
+
+// KubectlArgs prefixa os argumentos do kubectl com o kubeconfig e o contexto
+// do GIRUS, para que o kubectl acesse o mesmo cluster sem depender (nem
+// alterar) o contexto atual do usuário
+func KubectlArgs(args ...string) []string {
+	return append(clientOptions.Flags(), args...)
+}
+
+// restConfig carrega a configuração do cluster a partir do kubeconfig
+func (o ClientOptions) restConfig() (*rest.Config, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = o.Kubeconfig
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: o.Context}).ClientConfig()
+}
+
+// ContextName retorna o nome do contexto do kubeconfig usado pelo GIRUS: o
+// contexto das opções ou, sem ele, o contexto atual do kubeconfig
+func (o ClientOptions) ContextName() string {
+	if o.Context != "" {
+		return o.Context
+	}
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = o.Kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return ""
+	}
+	return config.CurrentContext
+}
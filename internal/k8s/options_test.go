@@ -0,0 +1,117 @@
+package k8s_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/templates"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// setClientOptions define as opções do cliente durante o teste
+func setClientOptions(t *testing.T, opts k8s.ClientOptions) {
+	t.Helper()
+	previous := k8s.CurrentClientOptions()
+	k8s.SetClientOptions(opts)
+	t.Cleanup(func() { k8s.SetClientOptions(previous) })
+}
+
+func TestClientOptionsDefaults(t *testing.T) {
+	setClientOptions(t, k8s.ClientOptions{})
+
+	if ns := k8s.Namespace(); ns != k8s.DefaultNamespace {
+		t.Errorf("Namespace = %q, esperado %q", ns, k8s.DefaultNamespace)
+	}
+	if args := k8s.KubectlArgs("get", "pods"); !reflect.DeepEqual(args, []string{"get", "pods"}) {
+		t.Errorf("KubectlArgs = %q", args)
+	}
+}
+
+func TestKubectlArgs(t *testing.T) {
+	setClientOptions(t, k8s.ClientOptions{Kubeconfig: "/tmp/kubeconfig", Context: "producao", Namespace: "treinamento"})
+
+	want := []string{"--kubeconfig", "/tmp/kubeconfig", "--context", "producao", "get", "pods", "-n", "treinamento"}
+	if args := k8s.KubectlArgs("get", "pods", "-n", k8s.Namespace()); !reflect.DeepEqual(args, want) {
+		t.Errorf("KubectlArgs = %q, esperado %q", args, want)
+	}
+}
+
+func TestNewKubernetesClientUsesContext(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	data := `apiVersion: v1
+kind: Config
+current-context: atual
+clusters:
+- name: atual
+  cluster:
+    server: https://atual.example:6443
+- name: producao
+  cluster:
+    server: https://producao.example:6443
+contexts:
+- name: atual
+  context:
+    cluster: atual
+- name: producao
+  context:
+    cluster: producao
+`
+	if err := os.WriteFile(kubeconfig, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	setClientOptions(t, k8s.ClientOptions{Kubeconfig: kubeconfig, Context: "producao"})
+	if _, err := k8s.NewKubernetesClient(); err != nil {
+		t.Fatalf("NewKubernetesClient: %v", err)
+	}
+
+	if name := (k8s.ClientOptions{Kubeconfig: kubeconfig}).ContextName(); name != "atual" {
+		t.Errorf("ContextName = %q, esperado o contexto atual", name)
+	}
+	if name := k8s.CurrentClientOptions().ContextName(); name != "producao" {
+		t.Errorf("ContextName = %q, esperado producao", name)
+	}
+
+	setClientOptions(t, k8s.ClientOptions{Kubeconfig: kubeconfig, Context: "inexistente"})
+	if _, err := k8s.NewKubernetesClient(); err == nil {
+		t.Error("esperado erro para contexto inexistente")
+	}
+
+	// O kubeconfig do usuário não é alterado
+	after, err := os.ReadFile(kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != data {
+		t.Error("o kubeconfig foi alterado")
+	}
+}
+
+func TestApplyUsesNamespace(t *testing.T) {
+	setClientOptions(t, k8s.ClientOptions{Namespace: "treinamento"})
+	dynamicClient, mapper := newFakeDynamic(t)
+	client := k8s.NewKubernetesClientFromClients(fake.NewSimpleClientset(), dynamicClient, mapper)
+	ctx := context.Background()
+
+	deployment, err := templates.GetManifest("defaultDeployment.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Apply(ctx, deployment); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	if _, err := dynamicClient.Resource(deployments).Namespace("treinamento").Get(ctx, "girus-backend", metav1.GetOptions{}); err != nil {
+		t.Errorf("backend não aplicado no namespace treinamento: %v", err)
+	}
+	if _, err := dynamicClient.Resource(deployments).Namespace(k8s.DefaultNamespace).Get(ctx, "girus-backend", metav1.GetOptions{}); err == nil {
+		t.Error("backend aplicado no namespace girus")
+	}
+}
@@ -0,0 +1,328 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForward descreve o encaminhamento de uma porta local para a porta de um serviço
+type PortForward struct {
+	Service    string `json:"service"`
+	LocalPort  int    `json:"localPort"`
+	RemotePort int    `json:"remotePort"`
+}
+
+func (f PortForward) String() string {
+	return fmt.Sprintf("svc/%s %d:%d", f.Service, f.LocalPort, f.RemotePort)
+}
+
+// DefaultPortForwards são os encaminhamentos usados para acessar o GIRUS
+var DefaultPortForwards = []PortForward{
+	{Service: "girus-backend", LocalPort: 8080, RemotePort: 8080},
+	{Service: "girus-frontend", LocalPort: 8000, RemotePort: 80},
+}
+
+// DefaultPortForwardAddress é o endereço local padrão dos port-forwards
+const DefaultPortForwardAddress = "0.0.0.0"
+
+// PortForwardState é o estado atual de um encaminhamento
+type PortForwardState struct {
+	PortForward
+	Connected bool   `json:"connected"`
+	Pod       string `json:"pod,omitempty"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// PortForwarder mantém port-forwards para serviços do cluster usando o protocolo
+// SPDY do client-go. Quando a conexão com o pod cai (por exemplo, quando o pod é
+// reiniciado) o encaminhamento é refeito para um pod pronto do serviço.
+type PortForwarder struct {
+	Namespace string
+	Address   string
+	Forwards  []PortForward
+	// RetryInterval é o intervalo entre as tentativas de reconexão
+	RetryInterval time.Duration
+	// Logf recebe as mensagens de conexão e reconexão; nil descarta as mensagens
+	Logf func(format string, args ...interface{})
+
+	client    *KubernetesClient
+	mu        sync.Mutex
+	states    map[string]*PortForwardState
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+// NewPortForwarder cria um PortForwarder para os encaminhamentos informados
+func (k *KubernetesClient) NewPortForwarder(namespace, address string, forwards ...PortForward) *PortForwarder {
+	if address == "" {
+		address = DefaultPortForwardAddress
+	}
+	states := map[string]*PortForwardState{}
+	for _, f := range forwards {
+		states[f.String()] = &PortForwardState{PortForward: f}
+	}
+	return &PortForwarder{
+		Namespace:     namespace,
+		Address:       address,
+		Forwards:      forwards,
+		RetryInterval: 2 * time.Second,
+		client:        k,
+		states:        states,
+		ready:         make(chan struct{}),
+	}
+}
+
+// Ready é fechado quando todos os encaminhamentos estiverem ativos pela primeira vez
+func (pf *PortForwarder) Ready() <-chan struct{} {
+	return pf.ready
+}
+
+// States retorna uma cópia do estado de cada encaminhamento
+func (pf *PortForwarder) States() []PortForwardState {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
+	states := make([]PortForwardState, 0, len(pf.Forwards))
+	for _, f := range pf.Forwards {
+		states = append(states, *pf.states[f.String()])
+	}
+	return states
+}
+
+// Run mantém os encaminhamentos até o contexto ser cancelado, quando as portas
+// locais são liberadas e Run retorna
+func (pf *PortForwarder) Run(ctx context.Context) error {
+	if pf.client.config == nil {
+		return fmt.Errorf("port-forward requer um cliente com configuração do cluster")
+	}
+
+	var wg sync.WaitGroup
+	for _, f := range pf.Forwards {
+		wg.Add(1)
+		go func(f PortForward) {
+			defer wg.Done()
+			pf.keep(ctx, f)
+		}(f)
+	}
+	wg.Wait()
+	return nil
+}
+
+// keep refaz o encaminhamento até o contexto ser cancelado
+func (pf *PortForwarder) keep(ctx context.Context, f PortForward) {
+	for {
+		err := pf.forward(ctx, f)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			pf.logf("%s: %v", f, err)
+		} else {
+			pf.logf("%s: conexão com o pod encerrada, reconectando", f)
+		}
+		pf.setState(f, func(s *PortForwardState) {
+			s.Connected = false
+			if err != nil {
+				s.LastError = err.Error()
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pf.RetryInterval):
+		}
+	}
+}
+
+// forward encaminha a porta para um pod do serviço até a conexão cair, o pod
+// ser removido ou o contexto ser cancelado
+func (pf *PortForwarder) forward(ctx context.Context, f PortForward) error {
+	pod, port, err := pf.client.ResolveServicePod(ctx, pf.Namespace, f.Service, f.RemotePort)
+	if err != nil {
+		return err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(pf.client.config)
+	if err != nil {
+		return err
+	}
+	req := pf.client.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(pf.Namespace).Name(pod).SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	var stopOnce sync.Once
+	stop := func() { stopOnce.Do(func() { close(stopCh) }) }
+	defer stop()
+
+	fw, err := portforward.NewOnAddresses(dialer, []string{pf.Address}, []string{fmt.Sprintf("%d:%d", f.LocalPort, port)}, stopCh, readyCh, io.Discard, logWriter{pf, f})
+	if err != nil {
+		return err
+	}
+
+	// Encerra o encaminhamento com o contexto ou quando o pod for removido
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		pf.waitPodGone(watchCtx, pod)
+		stop()
+	}()
+	go func() {
+		select {
+		case <-readyCh:
+			pf.setState(f, func(s *PortForwardState) {
+				s.Connected, s.Pod, s.LastError = true, pod, ""
+			})
+			pf.logf("%s: encaminhando para o pod %s", f, pod)
+			pf.checkReady()
+		case <-watchCtx.Done():
+		}
+	}()
+
+	return fw.ForwardPorts()
+}
+
+// waitPodGone retorna quando o pod for removido ou o contexto terminar
+func (pf *PortForwarder) waitPodGone(ctx context.Context, pod string) {
+	w, err := pf.client.clientset.CoreV1().Pods(pf.Namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", pod).String(),
+	})
+	if err != nil {
+		<-ctx.Done()
+		return
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				<-ctx.Done()
+				return
+			}
+			if p, isPod := event.Object.(*corev1.Pod); isPod && (event.Type == watch.Deleted || p.DeletionTimestamp != nil) {
+				return
+			}
+		}
+	}
+}
+
+func (pf *PortForwarder) setState(f PortForward, update func(*PortForwardState)) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	update(pf.states[f.String()])
+}
+
+func (pf *PortForwarder) checkReady() {
+	for _, s := range pf.States() {
+		if !s.Connected {
+			return
+		}
+	}
+	pf.readyOnce.Do(func() { close(pf.ready) })
+}
+
+func (pf *PortForwarder) logf(format string, args ...interface{}) {
+	if pf.Logf != nil {
+		pf.Logf(format, args...)
+	}
+}
+
+// logWriter repassa a saída de erro do port-forward para o Logf
+type logWriter struct {
+	pf *PortForwarder
+	f  PortForward
+}
+
+func (w logWriter) Write(p []byte) (int, error) {
+	if msg := strings.TrimSpace(string(p)); msg != "" {
+		w.pf.logf("%s: %s", w.f, msg)
+	}
+	return len(p), nil
+}
+
+// ResolveServicePod escolhe um pod pronto por trás do serviço e retorna a porta
+// do container correspondente à porta do serviço
+func (k *KubernetesClient) ResolveServicePod(ctx context.Context, namespace, service string, servicePort int) (string, int, error) {
+	svc, err := k.clientset.CoreV1().Services(namespace).Get(ctx, service, metav1.GetOptions{})
+	if err != nil {
+		return "", 0, fmt.Errorf("falha ao buscar o serviço %s no namespace %s: %w", service, namespace, err)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return "", 0, fmt.Errorf("o serviço %s não possui seletor de pods", service)
+	}
+
+	var target *corev1.ServicePort
+	for i := range svc.Spec.Ports {
+		if int(svc.Spec.Ports[i].Port) == servicePort {
+			target = &svc.Spec.Ports[i]
+			break
+		}
+	}
+	if target == nil {
+		return "", 0, fmt.Errorf("o serviço %s não expõe a porta %d", service, servicePort)
+	}
+
+	pods, err := k.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("falha ao listar os pods do serviço %s: %w", service, err)
+	}
+
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning || !isPodReady(pod) {
+			continue
+		}
+		port, ok := containerPort(pod, *target)
+		if !ok {
+			return "", 0, fmt.Errorf("porta %s do serviço %s não encontrada no pod %s", target.TargetPort.String(), service, pod.Name)
+		}
+		return pod.Name, port, nil
+	}
+	return "", 0, fmt.Errorf("nenhum pod pronto para o serviço %s", service)
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// containerPort traduz a targetPort do serviço (número ou nome) para a porta do container
+func containerPort(pod corev1.Pod, sp corev1.ServicePort) (int, bool) {
+	if sp.TargetPort.StrVal == "" {
+		if sp.TargetPort.IntVal != 0 {
+			return int(sp.TargetPort.IntVal), true
+		}
+		return int(sp.Port), true
+	}
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == sp.TargetPort.StrVal {
+				return int(p.ContainerPort), true
+			}
+		}
+	}
+	return 0, false
+}
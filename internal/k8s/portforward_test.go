@@ -0,0 +1,101 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func frontendService(targetPort intstr.IntOrString) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "girus-frontend", Namespace: "girus"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "girus-frontend"},
+			Ports:    []corev1.ServicePort{{Port: 80, TargetPort: targetPort}},
+		},
+	}
+}
+
+func frontendPod(name string, ready bool) *corev1.Pod {
+	pod := componentPod("girus-frontend", ready)
+	pod.Name = name
+	pod.Spec.Containers[0].Ports = []corev1.ContainerPort{{Name: "http", ContainerPort: 8081}}
+	return pod
+}
+
+func TestResolveServicePod(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   intstr.IntOrString
+		pods     []*corev1.Pod
+		wantPod  string
+		wantPort int
+		wantErr  bool
+	}{
+		{name: "porta numérica", target: intstr.FromInt32(80), pods: []*corev1.Pod{frontendPod("frontend-1", true)}, wantPod: "frontend-1", wantPort: 80},
+		{name: "porta nomeada", target: intstr.FromString("http"), pods: []*corev1.Pod{frontendPod("frontend-1", true)}, wantPod: "frontend-1", wantPort: 8081},
+		{name: "sem targetPort", pods: []*corev1.Pod{frontendPod("frontend-1", true)}, wantPod: "frontend-1", wantPort: 80},
+		{name: "ignora pods não prontos", target: intstr.FromInt32(80), pods: []*corev1.Pod{frontendPod("frontend-1", false), frontendPod("frontend-2", true)}, wantPod: "frontend-2", wantPort: 80},
+		{name: "nenhum pod pronto", target: intstr.FromInt32(80), pods: []*corev1.Pod{frontendPod("frontend-1", false)}, wantErr: true},
+		{name: "porta nomeada inexistente", target: intstr.FromString("web"), pods: []*corev1.Pod{frontendPod("frontend-1", true)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, clientset := newFakeClient(frontendService(tt.target))
+			for _, pod := range tt.pods {
+				if _, err := clientset.CoreV1().Pods("girus").Create(context.Background(), pod, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			pod, port, err := client.ResolveServicePod(context.Background(), "girus", "girus-frontend", 80)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("esperado erro, obtido pod %s porta %d", pod, port)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveServicePod: %v", err)
+			}
+			if pod != tt.wantPod || port != tt.wantPort {
+				t.Errorf("obtido %s:%d, esperado %s:%d", pod, port, tt.wantPod, tt.wantPort)
+			}
+		})
+	}
+
+	t.Run("porta não exposta pelo serviço", func(t *testing.T) {
+		client, _ := newFakeClient(frontendService(intstr.FromInt32(80)), frontendPod("frontend-1", true))
+		if _, _, err := client.ResolveServicePod(context.Background(), "girus", "girus-frontend", 8000); err == nil {
+			t.Error("esperado erro para porta não exposta")
+		}
+	})
+}
+
+func TestPortForwarderStates(t *testing.T) {
+	client, _ := newFakeClient()
+	pf := client.NewPortForwarder("girus", "", k8s.DefaultPortForwards...)
+
+	if pf.Address != k8s.DefaultPortForwardAddress {
+		t.Errorf("endereço = %q", pf.Address)
+	}
+	states := pf.States()
+	if len(states) != len(k8s.DefaultPortForwards) {
+		t.Fatalf("estados = %+v", states)
+	}
+	for i, s := range states {
+		if s.PortForward != k8s.DefaultPortForwards[i] || s.Connected {
+			t.Errorf("estado inicial inesperado: %+v", s)
+		}
+	}
+
+	// Sem configuração do cluster não há como abrir a conexão SPDY
+	if err := pf.Run(context.Background()); err == nil {
+		t.Error("esperado erro sem configuração do cluster")
+	}
+}
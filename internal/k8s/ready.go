@@ -0,0 +1,391 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/schollz/progressbar/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/ptr"
+)
+
+// GirusComponents são os componentes (label app) esperados por WaitForPodsReady
+var GirusComponents = []string{"girus-backend", "girus-frontend"}
+
+// Limites do diagnóstico exibido quando os pods não ficam prontos
+const (
+	diagnosticEvents   = 3
+	diagnosticLogLines = 10
+)
+
+// ComponentStatus descreve o estado de prontidão de um componente do GIRUS
+type ComponentStatus struct {
+	Name      string
+	Ready     bool
+	Pod       string
+	Container string
+	Reason    string
+	Message   string
+	// Events e Logs são preenchidos apenas no diagnóstico de tempo esgotado
+	Events []string
+	Logs   []string
+}
+
+// Summary descreve o estado do componente em uma linha
+func (s ComponentStatus) Summary() string {
+	if s.Ready {
+		return "Pronto"
+	}
+	summary := s.Reason
+	if s.Pod != "" {
+		summary += fmt.Sprintf(" (pod %s", s.Pod)
+		if s.Container != "" {
+			summary += fmt.Sprintf(", container %s", s.Container)
+		}
+		summary += ")"
+	}
+	if s.Message != "" {
+		summary += ": " + s.Message
+	}
+	return summary
+}
+
+// PodsNotReadyError indica que os componentes não ficaram prontos dentro do
+// tempo limite e traz o diagnóstico de cada um deles
+type PodsNotReadyError struct {
+	Namespace  string
+	Components []ComponentStatus
+}
+
+func (e *PodsNotReadyError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "tempo esgotado aguardando os pods do namespace %s ficarem prontos", e.Namespace)
+	for _, c := range e.Components {
+		if c.Ready {
+			continue
+		}
+		fmt.Fprintf(&b, "\n  %s: %s", c.Name, c.Summary())
+		for _, ev := range c.Events {
+			fmt.Fprintf(&b, "\n    evento: %s", ev)
+		}
+		if len(c.Logs) > 0 {
+			fmt.Fprintf(&b, "\n    últimas linhas do log:")
+			for _, line := range c.Logs {
+				fmt.Fprintf(&b, "\n      %s", line)
+			}
+		}
+	}
+	return b.String()
+}
+
+// WaitForPodsReady espera até que os pods do Girus (backend e frontend) estejam
+// prontos e a aplicação responda. A espera pode ser interrompida pelo contexto.
+func WaitForPodsReady(ctx context.Context, namespace string, timeout time.Duration) error {
+	// Criar formatadores de cores
+	green := color.New(color.FgGreen).SprintFunc()
+	magenta := color.New(color.FgMagenta).SprintFunc()
+
+	client, err := NewKubernetesClient()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\nAguardando os pods do Girus inicializarem...")
+
+	bar := progressbar.NewOptions(100,
+		progressbar.OptionSetDescription("Inicializando Girus..."),
+		progressbar.OptionSetWidth(80),
+		progressbar.OptionShowBytes(false),
+		progressbar.OptionSetPredictTime(false),
+		progressbar.OptionThrottle(65*time.Millisecond),
+		progressbar.OptionSetRenderBlankState(true),
+		progressbar.OptionSpinnerType(14),
+		progressbar.OptionFullWidth(),
+	)
+	defer bar.Finish()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				bar.Add(1)
+			}
+		}
+	}()
+
+	if err := client.WaitForComponentsReady(ctx, namespace, GirusComponents...); err != nil {
+		return err
+	}
+
+	// Os pods estão prontos, falta a aplicação responder
+	for {
+		if client.BackendHealthy(ctx, namespace) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("tempo esgotado aguardando a aplicação do Girus responder")
+			}
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	bar.Finish()
+	fmt.Printf("\n%s %s: Pronto\n", green("SUCESSO:"), magenta("Backend"))
+	fmt.Printf("%s %s: Pronto\n", green("SUCESSO:"), magenta("Frontend"))
+	fmt.Printf("%s %s: Respondendo\n", green("SUCESSO:"), magenta("Aplicação"))
+	return nil
+}
+
+// BackendHealthy consulta o endpoint de saúde do backend pelo proxy de serviços
+// da API do Kubernetes, sem depender de NodePort, port-forward ou kubectl
+func (k *KubernetesClient) BackendHealthy(ctx context.Context, namespace string) bool {
+	resp := k.clientset.CoreV1().Services(namespace).ProxyGet("http", "girus-backend", "8080", "/api/v1/health", nil)
+	if resp == nil {
+		return false
+	}
+	_, err := resp.DoRaw(ctx)
+	return err == nil
+}
+
+// WaitForComponentsReady acompanha os pods do namespace com um watch até que
+// cada componente (identificado pela label app) tenha um pod pronto. Quando o
+// prazo do contexto expira, retorna um *PodsNotReadyError com os motivos de
+// espera dos containers, os eventos de Warning recentes e as últimas linhas
+// do log de cada componente pendente.
+func (k *KubernetesClient) WaitForComponentsReady(ctx context.Context, namespace string, components ...string) error {
+	pods := k.clientset.CoreV1().Pods(namespace)
+	state := map[string]*corev1.Pod{}
+
+	for {
+		list, err := pods.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if ctx.Err() != nil {
+				return k.podsNotReady(ctx, namespace, components, state)
+			}
+			return fmt.Errorf("falha ao listar os pods do namespace %s: %w", namespace, err)
+		}
+		state = map[string]*corev1.Pod{}
+		for i := range list.Items {
+			state[list.Items[i].Name] = &list.Items[i]
+		}
+		if componentsReady(state, components) {
+			return nil
+		}
+
+		w, err := pods.Watch(ctx, metav1.ListOptions{ResourceVersion: list.ResourceVersion})
+		if err != nil {
+			if ctx.Err() != nil {
+				return k.podsNotReady(ctx, namespace, components, state)
+			}
+			return fmt.Errorf("falha ao acompanhar os pods do namespace %s: %w", namespace, err)
+		}
+
+		done, err := followPods(ctx, w, state, components)
+		w.Stop()
+		if done {
+			return nil
+		}
+		if err != nil {
+			return k.podsNotReady(ctx, namespace, components, state)
+		}
+		// O watch foi encerrado pelo servidor: recomeça a partir do estado atual
+	}
+}
+
+// followPods aplica os eventos do watch ao estado até todos os componentes
+// ficarem prontos, o contexto terminar (err != nil) ou o watch ser encerrado
+func followPods(ctx context.Context, w watch.Interface, state map[string]*corev1.Pod, components []string) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case event, ok := <-w.ResultChan():
+			if !ok || event.Type == watch.Error {
+				return false, nil
+			}
+			pod, ok := event.Object.(*corev1.Pod)
+			if !ok {
+				continue
+			}
+			if event.Type == watch.Deleted {
+				delete(state, pod.Name)
+			} else {
+				state[pod.Name] = pod
+			}
+			if componentsReady(state, components) {
+				return true, nil
+			}
+		}
+	}
+}
+
+func componentsReady(state map[string]*corev1.Pod, components []string) bool {
+	for _, name := range components {
+		if !ComponentStatusOf(name, podsOf(state, name)).Ready {
+			return false
+		}
+	}
+	return true
+}
+
+// podsOf retorna os pods ativos de um componente, do mais novo para o mais antigo
+func podsOf(state map[string]*corev1.Pod, component string) []corev1.Pod {
+	var pods []corev1.Pod
+	for _, pod := range state {
+		if pod.Labels["app"] == component && pod.DeletionTimestamp == nil {
+			pods = append(pods, *pod)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[j].CreationTimestamp.Before(&pods[i].CreationTimestamp)
+	})
+	return pods
+}
+
+// ComponentStatusOf avalia o estado de um componente a partir dos seus pods. O
+// componente está pronto quando algum dos pods tem a condição Ready.
+func ComponentStatusOf(name string, pods []corev1.Pod) ComponentStatus {
+	status := ComponentStatus{Name: name}
+	if len(pods) == 0 {
+		status.Reason = "Pod ainda não criado"
+		return status
+	}
+
+	for _, pod := range pods {
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+				status.Ready = true
+				status.Pod = pod.Name
+				return status
+			}
+		}
+	}
+
+	if rerr := DiagnosePods(pods); rerr != nil {
+		status.Pod, status.Container, status.Reason, status.Message = rerr.Pod, rerr.Container, rerr.Reason, rerr.Message
+		return status
+	}
+
+	// Nenhuma falha conhecida: descreve o pod mais recente
+	pod := pods[0]
+	status.Pod = pod.Name
+	for _, cs := range append(append([]corev1.ContainerStatus(nil), pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			status.Container, status.Reason, status.Message = cs.Name, cs.State.Waiting.Reason, cs.State.Waiting.Message
+			return status
+		}
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		status.Reason = fmt.Sprintf("Status: %s", orDefault(string(pod.Status.Phase), "Pending"))
+		return status
+	}
+	status.Reason = "Containers inicializando"
+	return status
+}
+
+// podsNotReady monta o erro de espera. Se o contexto foi cancelado (Ctrl-C) o
+// erro do contexto é retornado sem diagnóstico.
+func (k *KubernetesClient) podsNotReady(ctx context.Context, namespace string, components []string, state map[string]*corev1.Pod) error {
+	if ctx.Err() != context.DeadlineExceeded {
+		return ctx.Err()
+	}
+
+	// O contexto original já expirou: o diagnóstico usa um prazo próprio
+	diagCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	nerr := &PodsNotReadyError{Namespace: namespace}
+	for _, name := range components {
+		status := ComponentStatusOf(name, podsOf(state, name))
+		if !status.Ready && status.Pod != "" {
+			status.Events = k.warningEvents(diagCtx, namespace, status.Pod)
+			status.Logs = k.lastLogLines(diagCtx, namespace, state[status.Pod], status)
+		}
+		nerr.Components = append(nerr.Components, status)
+	}
+	return nerr
+}
+
+// warningEvents retorna os eventos de Warning mais recentes de um pod
+func (k *KubernetesClient) warningEvents(ctx context.Context, namespace, podName string) []string {
+	list, err := k.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{"involvedObject.name": podName, "type": corev1.EventTypeWarning}.String(),
+	})
+	if err != nil {
+		return nil
+	}
+
+	var events []corev1.Event
+	for _, ev := range list.Items {
+		if ev.InvolvedObject.Name == podName && ev.Type == corev1.EventTypeWarning {
+			events = append(events, ev)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+	if len(events) > diagnosticEvents {
+		events = events[len(events)-diagnosticEvents:]
+	}
+
+	var lines []string
+	for _, ev := range events {
+		lines = append(lines, fmt.Sprintf("%s: %s", ev.Reason, strings.TrimSpace(ev.Message)))
+	}
+	return lines
+}
+
+func eventTime(ev corev1.Event) time.Time {
+	if !ev.LastTimestamp.IsZero() {
+		return ev.LastTimestamp.Time
+	}
+	if !ev.EventTime.IsZero() {
+		return ev.EventTime.Time
+	}
+	return ev.CreationTimestamp.Time
+}
+
+// lastLogLines retorna as últimas linhas do log do container diagnosticado. No
+// CrashLoopBackOff o log da execução anterior é o que explica a falha.
+func (k *KubernetesClient) lastLogLines(ctx context.Context, namespace string, pod *corev1.Pod, status ComponentStatus) []string {
+	if pod == nil {
+		return nil
+	}
+	container := status.Container
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+
+	opts := &corev1.PodLogOptions{
+		Container: container,
+		TailLines: ptr.To(int64(diagnosticLogLines)),
+		Previous:  status.Reason == "CrashLoopBackOff",
+	}
+	raw, err := k.clientset.CoreV1().Pods(namespace).GetLogs(pod.Name, opts).DoRaw(ctx)
+	if err != nil {
+		return nil
+	}
+
+	text := strings.TrimSpace(string(raw))
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
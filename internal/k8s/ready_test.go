@@ -0,0 +1,173 @@
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	restclient "k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func componentPod(app string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: app + "-1", Namespace: "girus", Labels: map[string]string{"app": app}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: strings.TrimPrefix(app, "girus-")}}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestWaitForComponentsReady(t *testing.T) {
+	t.Run("prontos", func(t *testing.T) {
+		client, _ := newFakeClient(componentPod("girus-backend", true), componentPod("girus-frontend", true))
+		if err := client.WaitForComponentsReady(context.Background(), "girus", k8s.GirusComponents...); err != nil {
+			t.Fatalf("WaitForComponentsReady: %v", err)
+		}
+	})
+
+	t.Run("acompanha o watch", func(t *testing.T) {
+		client, clientset := newFakeClient(componentPod("girus-backend", true))
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_, _ = clientset.CoreV1().Pods("girus").Create(context.Background(), componentPod("girus-frontend", true), metav1.CreateOptions{})
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.WaitForComponentsReady(ctx, "girus", k8s.GirusComponents...); err != nil {
+			t.Fatalf("WaitForComponentsReady: %v", err)
+		}
+	})
+
+	t.Run("tempo esgotado com diagnóstico", func(t *testing.T) {
+		backend := componentPod("girus-backend", false)
+		backend.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name: "backend",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+				Reason:  "ImagePullBackOff",
+				Message: `Back-off pulling image "linuxtips/girus-backend:nope"`,
+			}},
+		}}
+		event := &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "girus-backend-1.1", Namespace: "girus"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "girus-backend-1", Namespace: "girus"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "Failed",
+			Message:        "Failed to pull image",
+		}
+		client, _ := newFakeClient(backend, componentPod("girus-frontend", true), event)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		err := client.WaitForComponentsReady(ctx, "girus", k8s.GirusComponents...)
+
+		var nerr *k8s.PodsNotReadyError
+		if !errors.As(err, &nerr) {
+			t.Fatalf("esperado *PodsNotReadyError, obtido %v", err)
+		}
+		if len(nerr.Components) != 2 || nerr.Components[0].Ready || !nerr.Components[1].Ready {
+			t.Fatalf("componentes inesperados: %+v", nerr.Components)
+		}
+		backendStatus := nerr.Components[0]
+		if backendStatus.Reason != "ImagePullBackOff" || backendStatus.Container != "backend" {
+			t.Errorf("diagnóstico inesperado: %+v", backendStatus)
+		}
+		if len(backendStatus.Events) != 1 || backendStatus.Events[0] != "Failed: Failed to pull image" {
+			t.Errorf("eventos = %v", backendStatus.Events)
+		}
+		if len(backendStatus.Logs) == 0 {
+			t.Error("esperadas as últimas linhas do log")
+		}
+		if !strings.Contains(err.Error(), "girus-backend:nope") || strings.Contains(err.Error(), "girus-frontend:") {
+			t.Errorf("mensagem inesperada: %v", err)
+		}
+	})
+
+	t.Run("cancelado", func(t *testing.T) {
+		client, _ := newFakeClient()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			cancel()
+		}()
+		if err := client.WaitForComponentsReady(ctx, "girus", k8s.GirusComponents...); !errors.Is(err, context.Canceled) {
+			t.Fatalf("esperado context.Canceled, obtido %v", err)
+		}
+	})
+}
+
+func TestComponentStatusOf(t *testing.T) {
+	if s := k8s.ComponentStatusOf("girus-backend", nil); s.Ready || s.Reason != "Pod ainda não criado" {
+		t.Errorf("sem pods: %+v", s)
+	}
+
+	creating := *componentPod("girus-frontend", false)
+	creating.Status.Phase = corev1.PodPending
+	creating.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "frontend",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+	}}
+	if s := k8s.ComponentStatusOf("girus-frontend", []corev1.Pod{creating}); s.Ready || s.Reason != "ContainerCreating" {
+		t.Errorf("criando: %+v", s)
+	}
+
+	running := *componentPod("girus-frontend", false)
+	if s := k8s.ComponentStatusOf("girus-frontend", []corev1.Pod{running}); s.Reason != "Containers inicializando" {
+		t.Errorf("inicializando: %+v", s)
+	}
+
+	if s := k8s.ComponentStatusOf("girus-frontend", []corev1.Pod{creating, *componentPod("girus-frontend", true)}); !s.Ready {
+		t.Errorf("esperado pronto com um pod Ready: %+v", s)
+	}
+}
+
+// healthResponse é a resposta do proxy de serviços usada nos testes
+type healthResponse struct{ err error }
+
+func (r healthResponse) DoRaw(context.Context) ([]byte, error) {
+	return []byte(`{"status":"ok"}`), r.err
+}
+
+func (r healthResponse) Stream(context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(`{"status":"ok"}`)), r.err
+}
+
+func TestBackendHealthy(t *testing.T) {
+	client, clientset := newFakeClient()
+	if client.BackendHealthy(context.Background(), "girus") {
+		t.Error("esperado não saudável sem resposta do proxy")
+	}
+
+	var got k8stesting.ProxyGetAction
+	var respErr error
+	clientset.PrependProxyReactor("services", func(action k8stesting.Action) (bool, restclient.ResponseWrapper, error) {
+		got = action.(k8stesting.ProxyGetAction)
+		return true, healthResponse{err: respErr}, nil
+	})
+	if !client.BackendHealthy(context.Background(), "girus") {
+		t.Error("esperado saudável")
+	}
+	if got.GetName() != "girus-backend" || got.GetPort() != "8080" || got.GetPath() != "/api/v1/health" || got.GetNamespace() != "girus" {
+		t.Errorf("proxy = %s:%s%s no namespace %s", got.GetName(), got.GetPort(), got.GetPath(), got.GetNamespace())
+	}
+
+	respErr = errors.New("503")
+	if client.BackendHealthy(context.Background(), "girus") {
+		t.Error("esperado não saudável com erro do endpoint")
+	}
+}
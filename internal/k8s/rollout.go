@@ -0,0 +1,328 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// RestartedAtAnnotation é a anotação usada pelo kubectl rollout restart
+const RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// RolloutCheckInterval define de quanto em quanto tempo os pods do rollout são
+// inspecionados em busca de falhas
+var RolloutCheckInterval = 2 * time.Second
+
+// Motivos de espera de um container que indicam que o rollout não vai progredir
+var failedWaitingReasons = map[string]bool{
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CrashLoopBackOff":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// RolloutError descreve por que o rollout de um deployment falhou, indicando o
+// pod e o container responsáveis quando possível
+type RolloutError struct {
+	Namespace  string
+	Deployment string
+	Pod        string
+	Container  string
+	Reason     string
+	Message    string
+}
+
+func (e *RolloutError) Error() string {
+	msg := fmt.Sprintf("rollout do deployment %s/%s falhou: %s", e.Namespace, e.Deployment, e.Reason)
+	if e.Pod != "" {
+		msg += fmt.Sprintf(" (pod %s", e.Pod)
+		if e.Container != "" {
+			msg += fmt.Sprintf(", container %s", e.Container)
+		}
+		msg += ")"
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// RestartDeployment reinicia os pods de um deployment atualizando a anotação
+// restartedAt do template, como o kubectl rollout restart
+func (k *KubernetesClient) RestartDeployment(ctx context.Context, namespace, name string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						RestartedAtAnnotation: time.Now().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = k.clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{FieldManager: FieldManager})
+	if err != nil {
+		return fmt.Errorf("falha ao reiniciar o deployment %s no namespace %s: %w", name, namespace, err)
+	}
+	return nil
+}
+
+// WaitForRollout espera o rollout do deployment terminar, como o kubectl rollout
+// status. Falhas dos pods (ImagePullBackOff, CrashLoopBackOff etc.) e o prazo de
+// progresso excedido interrompem a espera com um *RolloutError. O tempo máximo
+// de espera é definido pelo contexto.
+func (k *KubernetesClient) WaitForRollout(ctx context.Context, namespace, name string) error {
+	deployments := k.clientset.AppsV1().Deployments(namespace)
+	ticker := time.NewTicker(RolloutCheckInterval)
+	defer ticker.Stop()
+
+	status := "aguardando o início do rollout"
+	for {
+		deploy, err := deployments.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if ctx.Err() != nil {
+				return rolloutTimeout(ctx, namespace, name, status)
+			}
+			return fmt.Errorf("falha ao buscar o deployment %s no namespace %s: %w", name, namespace, err)
+		}
+
+		w, err := deployments.Watch(ctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+			ResourceVersion: deploy.ResourceVersion,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return rolloutTimeout(ctx, namespace, name, status)
+			}
+			return fmt.Errorf("falha ao acompanhar o deployment %s no namespace %s: %w", name, namespace, err)
+		}
+
+		done, err := k.followRollout(ctx, w, deploy, ticker.C, &status)
+		w.Stop()
+		if done || err != nil {
+			return err
+		}
+		// O watch foi encerrado pelo servidor: recomeça a partir do estado atual
+	}
+}
+
+// followRollout processa os eventos do watch até o rollout terminar, falhar ou
+// o watch ser encerrado (done == false e err == nil)
+func (k *KubernetesClient) followRollout(ctx context.Context, w watch.Interface, deploy *appsv1.Deployment, tick <-chan time.Time, status *string) (bool, error) {
+	check := func(d *appsv1.Deployment) (bool, error) {
+		done, msg, err := RolloutStatus(d)
+		*status = msg
+		if done || err != nil {
+			return done, err
+		}
+		return false, k.diagnoseRollout(ctx, d)
+	}
+
+	if done, err := check(deploy); done || err != nil {
+		return done, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, rolloutTimeout(ctx, deploy.Namespace, deploy.Name, *status)
+		case <-tick:
+			if err := k.diagnoseRollout(ctx, deploy); err != nil {
+				return true, err
+			}
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return false, nil
+			}
+			switch event.Type {
+			case watch.Deleted:
+				return true, fmt.Errorf("o deployment %s foi removido durante o rollout", deploy.Name)
+			case watch.Error:
+				return false, nil
+			}
+			d, ok := event.Object.(*appsv1.Deployment)
+			if !ok || d.Name != deploy.Name {
+				continue
+			}
+			deploy = d
+			if done, err := check(deploy); done || err != nil {
+				return true, err
+			}
+		}
+	}
+}
+
+// RolloutStatus avalia o status de um deployment com as mesmas regras do kubectl
+// rollout status. Retorna se o rollout terminou e uma descrição do progresso.
+func RolloutStatus(d *appsv1.Deployment) (bool, string, error) {
+	if d.Generation > d.Status.ObservedGeneration {
+		return false, "aguardando o controlador observar a nova versão do deployment", nil
+	}
+
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Reason == "ProgressDeadlineExceeded" {
+			return false, "", &RolloutError{Namespace: d.Namespace, Deployment: d.Name, Reason: c.Reason, Message: c.Message}
+		}
+	}
+
+	desired := int32(1)
+	if d.Spec.Replicas != nil {
+		desired = *d.Spec.Replicas
+	}
+	st := d.Status
+	switch {
+	case st.UpdatedReplicas < desired:
+		return false, fmt.Sprintf("%d de %d réplicas atualizadas", st.UpdatedReplicas, desired), nil
+	case st.Replicas > st.UpdatedReplicas:
+		return false, fmt.Sprintf("%d réplicas antigas aguardando finalização", st.Replicas-st.UpdatedReplicas), nil
+	case st.AvailableReplicas < st.UpdatedReplicas:
+		return false, fmt.Sprintf("%d de %d réplicas atualizadas disponíveis", st.AvailableReplicas, st.UpdatedReplicas), nil
+	}
+	return true, "rollout concluído", nil
+}
+
+// diagnoseRollout procura falhas no ReplicaSet mais recente do deployment e nos
+// seus pods. Erros ao consultar o cluster são ignorados, pois o rollout continua
+// sendo acompanhado pelo watch.
+func (k *KubernetesClient) diagnoseRollout(ctx context.Context, d *appsv1.Deployment) error {
+	selector, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+	if err != nil || selector.Empty() {
+		return nil
+	}
+
+	rs := k.newReplicaSet(ctx, d, selector)
+	if rs != nil {
+		for _, c := range rs.Status.Conditions {
+			if c.Type == appsv1.ReplicaSetReplicaFailure && c.Status == corev1.ConditionTrue {
+				return &RolloutError{Namespace: d.Namespace, Deployment: d.Name, Reason: c.Reason, Message: c.Message}
+			}
+		}
+		// Considera apenas os pods da versão nova
+		if hash := rs.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; hash != "" {
+			req, err := labels.NewRequirement(appsv1.DefaultDeploymentUniqueLabelKey, "=", []string{hash})
+			if err == nil {
+				selector = selector.Add(*req)
+			}
+		}
+	}
+
+	pods, err := k.clientset.CoreV1().Pods(d.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil
+	}
+	if rerr := DiagnosePods(pods.Items); rerr != nil {
+		rerr.Namespace = d.Namespace
+		rerr.Deployment = d.Name
+		return rerr
+	}
+	return nil
+}
+
+// newReplicaSet retorna o ReplicaSet da revisão atual do deployment, se existir
+func (k *KubernetesClient) newReplicaSet(ctx context.Context, d *appsv1.Deployment, selector labels.Selector) *appsv1.ReplicaSet {
+	list, err := k.clientset.AppsV1().ReplicaSets(d.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil
+	}
+
+	const revisionAnnotation = "deployment.kubernetes.io/revision"
+	revision := d.Annotations[revisionAnnotation]
+	for i := range list.Items {
+		rs := &list.Items[i]
+		owner := metav1.GetControllerOf(rs)
+		if owner == nil || owner.Kind != "Deployment" || owner.Name != d.Name {
+			continue
+		}
+		if revision != "" && rs.Annotations[revisionAnnotation] == revision {
+			return rs
+		}
+	}
+	return nil
+}
+
+// DiagnosePods retorna a primeira falha que impede os pods de ficarem prontos,
+// ou nil se nenhum pod está em um estado de erro conhecido
+func DiagnosePods(pods []corev1.Pod) *RolloutError {
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodFailed {
+			return &RolloutError{Pod: pod.Name, Reason: orDefault(pod.Status.Reason, "PodFailed"), Message: pod.Status.Message}
+		}
+
+		statuses := append(append([]corev1.ContainerStatus(nil), pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, cs := range statuses {
+			waiting := cs.State.Waiting
+			if waiting == nil || !failedWaitingReasons[waiting.Reason] {
+				continue
+			}
+			message := waiting.Message
+			// No CrashLoopBackOff a causa está no término anterior do container
+			if term := cs.LastTerminationState.Terminated; term != nil && waiting.Reason == "CrashLoopBackOff" {
+				detail := fmt.Sprintf("último término com código %d", term.ExitCode)
+				if term.Reason != "" {
+					detail += " (" + term.Reason + ")"
+				}
+				if m := strings.TrimSpace(term.Message); m != "" {
+					detail += ": " + m
+				}
+				message = detail
+			}
+			return &RolloutError{Pod: pod.Name, Container: cs.Name, Reason: waiting.Reason, Message: message}
+		}
+	}
+	return nil
+}
+
+func rolloutTimeout(ctx context.Context, namespace, name, status string) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("tempo esgotado aguardando o rollout do deployment %s/%s: %s", namespace, name, status)
+	}
+	return ctx.Err()
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// RestartDeploymentAndWait reinicia o deployment e espera o rollout terminar,
+// limitando a espera a timeout
+func (k *KubernetesClient) RestartDeploymentAndWait(ctx context.Context, namespace, name string, timeout time.Duration) error {
+	if err := k.RestartDeployment(ctx, namespace, name); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return k.WaitForRollout(ctx, namespace, name)
+}
+
+// BackendRolloutTimeout é o tempo máximo de espera pelo reinício do backend
+const BackendRolloutTimeout = 60 * time.Second
+
+// RestartBackend reinicia o backend do GIRUS, que só carrega os templates de
+// laboratório na inicialização, e espera o rollout terminar
+func (k *KubernetesClient) RestartBackend(ctx context.Context) error {
+	return k.RestartDeploymentAndWait(ctx, Namespace(), "girus-backend", BackendRolloutTimeout)
+}
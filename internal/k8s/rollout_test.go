@@ -0,0 +1,198 @@
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func backendDeployment(generation, observed int64, updated, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "girus-backend",
+			Namespace:   "girus",
+			Generation:  generation,
+			Annotations: map[string]string{"deployment.kubernetes.io/revision": "2"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(1)),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "girus-backend"}},
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: observed,
+			Replicas:           updated,
+			UpdatedReplicas:    updated,
+			AvailableReplicas:  available,
+		},
+	}
+}
+
+func newFakeClient(objects ...runtime.Object) (*k8s.KubernetesClient, *fake.Clientset) {
+	clientset := fake.NewSimpleClientset(objects...)
+	return k8s.NewKubernetesClientFromClients(clientset, nil, nil), clientset
+}
+
+func TestRestartDeployment(t *testing.T) {
+	client, clientset := newFakeClient(backendDeployment(1, 1, 1, 1))
+
+	if err := client.RestartDeployment(context.Background(), "girus", "girus-backend"); err != nil {
+		t.Fatalf("RestartDeployment: %v", err)
+	}
+
+	d, err := clientset.AppsV1().Deployments("girus").Get(context.Background(), "girus-backend", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Spec.Template.Annotations[k8s.RestartedAtAnnotation] == "" {
+		t.Errorf("anotação %s não definida: %v", k8s.RestartedAtAnnotation, d.Spec.Template.Annotations)
+	}
+
+	if err := client.RestartDeployment(context.Background(), "girus", "inexistente"); err == nil {
+		t.Error("esperado erro para deployment inexistente")
+	}
+}
+
+func TestWaitForRollout(t *testing.T) {
+	t.Run("concluído", func(t *testing.T) {
+		client, _ := newFakeClient(backendDeployment(2, 2, 1, 1))
+		if err := client.WaitForRollout(context.Background(), "girus", "girus-backend"); err != nil {
+			t.Fatalf("WaitForRollout: %v", err)
+		}
+	})
+
+	t.Run("acompanha o watch", func(t *testing.T) {
+		client, clientset := newFakeClient(backendDeployment(2, 1, 0, 0))
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_, _ = clientset.AppsV1().Deployments("girus").UpdateStatus(context.Background(), backendDeployment(2, 2, 1, 1), metav1.UpdateOptions{})
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.WaitForRollout(ctx, "girus", "girus-backend"); err != nil {
+			t.Fatalf("WaitForRollout: %v", err)
+		}
+	})
+
+	t.Run("tempo esgotado", func(t *testing.T) {
+		client, _ := newFakeClient(backendDeployment(2, 2, 1, 0))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		err := client.WaitForRollout(ctx, "girus", "girus-backend")
+		if err == nil || !strings.Contains(err.Error(), "0 de 1 réplicas atualizadas disponíveis") {
+			t.Fatalf("esperado erro de tempo esgotado com o progresso, obtido %v", err)
+		}
+	})
+
+	t.Run("prazo de progresso excedido", func(t *testing.T) {
+		d := backendDeployment(2, 2, 0, 0)
+		d.Status.Conditions = []appsv1.DeploymentCondition{{
+			Type:    appsv1.DeploymentProgressing,
+			Status:  corev1.ConditionFalse,
+			Reason:  "ProgressDeadlineExceeded",
+			Message: `ReplicaSet "girus-backend-abc" has timed out progressing.`,
+		}}
+		client, _ := newFakeClient(d)
+
+		var rerr *k8s.RolloutError
+		if err := client.WaitForRollout(context.Background(), "girus", "girus-backend"); !errors.As(err, &rerr) {
+			t.Fatalf("esperado *RolloutError, obtido %v", err)
+		}
+		if rerr.Reason != "ProgressDeadlineExceeded" {
+			t.Errorf("motivo = %q", rerr.Reason)
+		}
+	})
+
+	t.Run("pod com falha", func(t *testing.T) {
+		rs := &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "girus-backend-new",
+				Namespace:       "girus",
+				Labels:          map[string]string{"app": "girus-backend", "pod-template-hash": "new"},
+				Annotations:     map[string]string{"deployment.kubernetes.io/revision": "2"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "girus-backend", Controller: ptr.To(true)}},
+			},
+		}
+		oldPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "girus-backend-old-1", Namespace: "girus", Labels: map[string]string{"app": "girus-backend", "pod-template-hash": "old"}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "backend",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}}},
+		}
+		newPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "girus-backend-new-1", Namespace: "girus", Labels: map[string]string{"app": "girus-backend", "pod-template-hash": "new"}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "backend",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "ImagePullBackOff",
+					Message: `Back-off pulling image "linuxtips/girus-backend:nope"`,
+				}},
+			}}},
+		}
+		client, _ := newFakeClient(backendDeployment(2, 2, 1, 0), rs, oldPod, newPod)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		var rerr *k8s.RolloutError
+		err := client.WaitForRollout(ctx, "girus", "girus-backend")
+		if !errors.As(err, &rerr) {
+			t.Fatalf("esperado *RolloutError, obtido %v", err)
+		}
+		if rerr.Pod != "girus-backend-new-1" || rerr.Container != "backend" || rerr.Reason != "ImagePullBackOff" {
+			t.Errorf("diagnóstico inesperado: %+v", rerr)
+		}
+		if !strings.Contains(err.Error(), "girus-backend:nope") {
+			t.Errorf("mensagem sem o detalhe do pod: %v", err)
+		}
+	})
+}
+
+func TestDiagnosePods(t *testing.T) {
+	crashing := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "girus-backend-1"},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "backend",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 10s"}},
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 1,
+				Reason:   "Error",
+				Message:  "panic: config inválida",
+			}},
+		}}},
+	}
+	starting := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "girus-frontend-1"},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "frontend",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+		}}},
+	}
+
+	if rerr := k8s.DiagnosePods([]corev1.Pod{starting}); rerr != nil {
+		t.Errorf("ContainerCreating não deveria ser uma falha: %v", rerr)
+	}
+
+	rerr := k8s.DiagnosePods([]corev1.Pod{starting, crashing})
+	if rerr == nil {
+		t.Fatal("esperado diagnóstico de CrashLoopBackOff")
+	}
+	if rerr.Pod != "girus-backend-1" || rerr.Reason != "CrashLoopBackOff" {
+		t.Errorf("diagnóstico inesperado: %+v", rerr)
+	}
+	if want := "último término com código 1 (Error): panic: config inválida"; rerr.Message != want {
+		t.Errorf("mensagem = %q, esperado %q", rerr.Message, want)
+	}
+}
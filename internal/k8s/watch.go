@@ -0,0 +1,399 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/yaml"
+)
+
+// LabTemplateSelector seleciona os ConfigMaps dos templates de laboratório
+const LabTemplateSelector = "app=girus-lab-template"
+
+// Limites do StatusWatcher
+const (
+	// watchEvents é o número de eventos de Warning mantidos no Snapshot
+	watchEvents = 5
+	// watchSyncTimeout é o tempo máximo para preencher o cache inicial
+	watchSyncTimeout = 30 * time.Second
+)
+
+// Snapshot é o estado do GIRUS observado pelo StatusWatcher
+type Snapshot struct {
+	// Components traz o estado do backend e do frontend
+	Components []ComponentStatus
+	Pods       []PodSnapshot
+	Services   []ServiceSnapshot
+	Labs       []LabSnapshot
+	Nodes      []NodeSnapshot
+	// NodeAccess indica se o usuário pode acompanhar os nós e os pods de
+	// todos os namespaces; sem ela Nodes fica vazio
+	NodeAccess bool
+	// Events são os eventos de Warning mais recentes do namespace, do mais
+	// antigo para o mais novo
+	Events []EventSnapshot
+}
+
+// PodSnapshot descreve um pod do namespace do GIRUS
+type PodSnapshot struct {
+	Name string
+	// Ready é o número de containers prontos, no formato "1/1"
+	Ready string
+	// Status é a fase do pod ou o motivo de espera de um container, como no
+	// kubectl get pods
+	Status   string
+	Restarts int32
+	Node     string
+	Created  time.Time
+}
+
+// ServiceSnapshot descreve um serviço do namespace do GIRUS
+type ServiceSnapshot struct {
+	Name      string
+	Type      string
+	ClusterIP string
+	Ports     string
+	// Endpoints é o número de endereços prontos atrás do serviço
+	Endpoints int
+}
+
+// LabSnapshot descreve um template de laboratório instalado
+type LabSnapshot struct {
+	Name  string
+	Title string
+}
+
+// NodeSnapshot descreve os recursos de um nó. Os valores requisitados somam os
+// requests dos pods ativos no nó, em todos os namespaces.
+type NodeSnapshot struct {
+	Name  string
+	Ready bool
+	// CPU em milicores
+	CPURequested   int64
+	CPUAllocatable int64
+	// Memória em bytes
+	MemoryRequested   int64
+	MemoryAllocatable int64
+}
+
+// EventSnapshot descreve um evento de Warning
+type EventSnapshot struct {
+	Time time.Time
+	// Object é o objeto do evento, no formato "Pod/girus-backend-abc"
+	Object  string
+	Reason  string
+	Message string
+	Count   int32
+}
+
+// StatusWatcher acompanha o namespace do GIRUS e os nós do cluster com os
+// informers do client-go, mantendo um cache local atualizado pelos watches da
+// API em vez de consultas repetidas
+type StatusWatcher struct {
+	namespace string
+	factories []informers.SharedInformerFactory
+	synced    []cache.InformerSynced
+	changes   chan struct{}
+
+	pods       corelisters.PodLister
+	services   corelisters.ServiceLister
+	slices     discoverylisters.EndpointSliceLister
+	configMaps corelisters.ConfigMapLister
+	events     corelisters.EventLister
+	// nodes e clusterPods ficam nil quando o usuário não pode acompanhar os
+	// nós e os pods de todos os namespaces
+	nodes       corelisters.NodeLister
+	clusterPods corelisters.PodLister
+}
+
+// watchAccess retorna as permissões de list e watch sobre os recursos
+func watchAccess(namespace, group string, resources ...string) []resourceAccess {
+	var checks []resourceAccess
+	for _, resource := range resources {
+		for _, verb := range []string{"list", "watch"} {
+			checks = append(checks, resourceAccess{Verb: verb, Group: group, Resource: resource, Namespace: namespace})
+		}
+	}
+	return checks
+}
+
+// WatchStatus cria o StatusWatcher do namespace. As permissões são verificadas
+// com SelfSubjectAccessReviews: sem acesso aos recursos do namespace retorna um
+// *PermissionError; sem acesso aos nós e aos pods de todos os namespaces os
+// recursos dos nós não são acompanhados. Os watches começam com Start.
+func (k *KubernetesClient) WatchStatus(ctx context.Context, namespace string) (*StatusWatcher, error) {
+	checks := watchAccess(namespace, "", "pods", "services", "configmaps", "events")
+	checks = append(checks, watchAccess(namespace, "discovery.k8s.io", "endpointslices")...)
+	denied, err := k.deniedAccess(ctx, checks)
+	if err != nil {
+		return nil, err
+	}
+	if len(denied) > 0 {
+		return nil, &PermissionError{Denied: denied}
+	}
+	clusterDenied, err := k.deniedAccess(ctx, watchAccess("", "", "nodes", "pods"))
+	if err != nil {
+		return nil, err
+	}
+
+	local := informers.NewSharedInformerFactoryWithOptions(k.clientset, 0, informers.WithNamespace(namespace))
+	w := &StatusWatcher{
+		namespace: namespace,
+		factories: []informers.SharedInformerFactory{local},
+		changes:   make(chan struct{}, 1),
+	}
+
+	pods := local.Core().V1().Pods()
+	services := local.Core().V1().Services()
+	slices := local.Discovery().V1().EndpointSlices()
+	configMaps := local.Core().V1().ConfigMaps()
+	events := local.Core().V1().Events()
+
+	w.pods, w.services, w.slices = pods.Lister(), services.Lister(), slices.Lister()
+	w.configMaps, w.events = configMaps.Lister(), events.Lister()
+	watched := []cache.SharedIndexInformer{
+		pods.Informer(), services.Informer(), slices.Informer(),
+		configMaps.Informer(), events.Informer(),
+	}
+
+	// Os pods de todos os namespaces são necessários para somar os requests
+	// de cada nó
+	if len(clusterDenied) == 0 {
+		cluster := informers.NewSharedInformerFactory(k.clientset, 0)
+		w.factories = append(w.factories, cluster)
+		nodes := cluster.Core().V1().Nodes()
+		clusterPods := cluster.Core().V1().Pods()
+		w.nodes, w.clusterPods = nodes.Lister(), clusterPods.Lister()
+		watched = append(watched, nodes.Informer(), clusterPods.Informer())
+	}
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { w.notify() },
+		UpdateFunc: func(interface{}, interface{}) { w.notify() },
+		DeleteFunc: func(interface{}) { w.notify() },
+	}
+	for _, informer := range watched {
+		informer.AddEventHandler(handler)
+		w.synced = append(w.synced, informer.HasSynced)
+	}
+	return w, nil
+}
+
+// Start inicia os watches e espera o cache ser preenchido. Os watches são
+// encerrados quando o contexto termina.
+func (w *StatusWatcher) Start(ctx context.Context) error {
+	for _, f := range w.factories {
+		f.Start(ctx.Done())
+	}
+	go func() {
+		<-ctx.Done()
+		for _, f := range w.factories {
+			f.Shutdown()
+		}
+	}()
+
+	syncCtx, cancel := context.WithTimeout(ctx, watchSyncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), w.synced...) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("tempo esgotado sincronizando o estado do namespace %s", w.namespace)
+	}
+	return nil
+}
+
+// Changes sinaliza que algum objeto observado mudou. Mudanças seguidas são
+// agrupadas em um único sinal.
+func (w *StatusWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *StatusWatcher) notify() {
+	select {
+	case w.changes <- struct{}{}:
+	default:
+	}
+}
+
+// Snapshot retorna o estado atual do cache
+func (w *StatusWatcher) Snapshot() Snapshot {
+	var snap Snapshot
+
+	pods, _ := w.pods.List(labels.Everything())
+	state := map[string]*corev1.Pod{}
+	for _, pod := range pods {
+		state[pod.Name] = pod
+		snap.Pods = append(snap.Pods, podSnapshot(pod))
+	}
+	sort.Slice(snap.Pods, func(i, j int) bool { return snap.Pods[i].Name < snap.Pods[j].Name })
+	for _, name := range GirusComponents {
+		snap.Components = append(snap.Components, ComponentStatusOf(name, podsOf(state, name)))
+	}
+
+	slices, _ := w.slices.List(labels.Everything())
+	services, _ := w.services.List(labels.Everything())
+	for _, svc := range services {
+		snap.Services = append(snap.Services, serviceSnapshot(svc, slices))
+	}
+	sort.Slice(snap.Services, func(i, j int) bool { return snap.Services[i].Name < snap.Services[j].Name })
+
+	selector, _ := labels.Parse(LabTemplateSelector)
+	configMaps, _ := w.configMaps.List(selector)
+	for _, cm := range configMaps {
+		snap.Labs = append(snap.Labs, labSnapshot(cm))
+	}
+	sort.Slice(snap.Labs, func(i, j int) bool { return snap.Labs[i].Name < snap.Labs[j].Name })
+
+	if w.nodes != nil {
+		snap.NodeAccess = true
+		allPods, _ := w.clusterPods.List(labels.Everything())
+		nodes, _ := w.nodes.List(labels.Everything())
+		for _, node := range nodes {
+			snap.Nodes = append(snap.Nodes, nodeSnapshot(node, allPods))
+		}
+		sort.Slice(snap.Nodes, func(i, j int) bool { return snap.Nodes[i].Name < snap.Nodes[j].Name })
+	}
+
+	events, _ := w.events.List(labels.Everything())
+	snap.Events = recentWarnings(events, watchEvents)
+	return snap
+}
+
+// podSnapshot resume o pod como o kubectl get pods
+func podSnapshot(pod *corev1.Pod) PodSnapshot {
+	snap := PodSnapshot{
+		Name:    pod.Name,
+		Status:  orDefault(string(pod.Status.Phase), "Pending"),
+		Node:    pod.Spec.NodeName,
+		Created: pod.CreationTimestamp.Time,
+	}
+
+	ready := 0
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			ready++
+		}
+		snap.Restarts += cs.RestartCount
+		switch {
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "":
+			snap.Status = cs.State.Waiting.Reason
+		case cs.State.Terminated != nil && cs.State.Terminated.Reason != "" && pod.Status.Phase == corev1.PodRunning:
+			snap.Status = cs.State.Terminated.Reason
+		}
+	}
+	snap.Ready = fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers))
+
+	if pod.DeletionTimestamp != nil {
+		snap.Status = "Terminating"
+	}
+	return snap
+}
+
+// serviceSnapshot resume o serviço, contando os endereços prontos das suas
+// EndpointSlices
+func serviceSnapshot(svc *corev1.Service, slices []*discoveryv1.EndpointSlice) ServiceSnapshot {
+	var ports []string
+	for _, p := range svc.Spec.Ports {
+		port := fmt.Sprintf("%d", p.Port)
+		if p.NodePort != 0 {
+			port += fmt.Sprintf(":%d", p.NodePort)
+		}
+		ports = append(ports, port)
+	}
+
+	snap := ServiceSnapshot{
+		Name:      svc.Name,
+		Type:      string(svc.Spec.Type),
+		ClusterIP: svc.Spec.ClusterIP,
+		Ports:     strings.Join(ports, ","),
+	}
+	for _, slice := range slices {
+		if slice.Labels[discoveryv1.LabelServiceName] != svc.Name {
+			continue
+		}
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				snap.Endpoints += len(ep.Addresses)
+			}
+		}
+	}
+	return snap
+}
+
+// labSnapshot lê o nome e o título do lab.yaml do template. Templates sem
+// lab.yaml válido usam o nome do ConfigMap.
+func labSnapshot(cm *corev1.ConfigMap) LabSnapshot {
+	var lab struct {
+		Name  string `json:"name"`
+		Title string `json:"title"`
+	}
+	if err := yaml.Unmarshal([]byte(cm.Data["lab.yaml"]), &lab); err != nil || lab.Name == "" {
+		return LabSnapshot{Name: cm.Name}
+	}
+	return LabSnapshot{Name: lab.Name, Title: lab.Title}
+}
+
+// nodeSnapshot soma os requests dos pods ativos no nó
+func nodeSnapshot(node *corev1.Node, pods []*corev1.Pod) NodeSnapshot {
+	snap := NodeSnapshot{
+		Name:              node.Name,
+		CPUAllocatable:    node.Status.Allocatable.Cpu().MilliValue(),
+		MemoryAllocatable: node.Status.Allocatable.Memory().Value(),
+	}
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			snap.Ready = c.Status == corev1.ConditionTrue
+		}
+	}
+
+	for _, pod := range pods {
+		if pod.Spec.NodeName != node.Name || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, c := range pod.Spec.Containers {
+			snap.CPURequested += c.Resources.Requests.Cpu().MilliValue()
+			snap.MemoryRequested += c.Resources.Requests.Memory().Value()
+		}
+	}
+	return snap
+}
+
+// recentWarnings retorna os últimos eventos de Warning, do mais antigo para o
+// mais novo
+func recentWarnings(events []*corev1.Event, limit int) []EventSnapshot {
+	var warnings []*corev1.Event
+	for _, ev := range events {
+		if ev.Type == corev1.EventTypeWarning {
+			warnings = append(warnings, ev)
+		}
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		return eventTime(*warnings[i]).Before(eventTime(*warnings[j]))
+	})
+	if len(warnings) > limit {
+		warnings = warnings[len(warnings)-limit:]
+	}
+
+	var snaps []EventSnapshot
+	for _, ev := range warnings {
+		snaps = append(snaps, EventSnapshot{
+			Time:    eventTime(*ev),
+			Object:  fmt.Sprintf("%s/%s", ev.InvolvedObject.Kind, ev.InvolvedObject.Name),
+			Reason:  ev.Reason,
+			Message: strings.TrimSpace(ev.Message),
+			Count:   ev.Count,
+		})
+	}
+	return snaps
+}
@@ -0,0 +1,180 @@
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/k8s"
+	"github.com/badtuxx/girus-cli/internal/k8s/k8sfake"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStatusWatcher(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "girus-control-plane"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	backend := componentPod("girus-backend", true)
+	backend.Spec.NodeName = node.Name
+	backend.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("250m"),
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	}
+	backend.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "backend", Ready: true, RestartCount: 2}}
+	// Pods de outros namespaces contam nos requests do nó, mas não na lista
+	labPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "lab", Namespace: "lab-linux"},
+		Spec: corev1.PodSpec{NodeName: node.Name, Containers: []corev1.Container{{
+			Name:      "lab",
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")}},
+		}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "girus-backend", Namespace: "girus"},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeClusterIP,
+			ClusterIP: "10.96.0.10",
+			Ports:     []corev1.ServicePort{{Port: 8080}},
+		},
+	}
+	ready, notReady := true, false
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "girus-backend-abc",
+			Namespace: "girus",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "girus-backend"},
+		},
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.244.0.5"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+			{Addresses: []string{"10.244.0.6"}, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+		},
+	}
+	template := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "linux-lab", Namespace: "girus", Labels: map[string]string{"app": "girus-lab-template"}},
+		Data:       map[string]string{"lab.yaml": "name: linux-basico\ntitle: Linux Básico\n"},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "ev-1", Namespace: "girus"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "girus-frontend-1"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container\n",
+		LastTimestamp:  metav1.NewTime(time.Now()),
+	}
+	normal := event.DeepCopy()
+	normal.Name, normal.Type, normal.Reason = "ev-2", corev1.EventTypeNormal, "Pulled"
+
+	client, clientset := newFakeClient(node, backend, labPod, service, slice, template, event, normal)
+	k8sfake.AllowAccessReviews(clientset)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	watcher, err := client.WatchStatus(ctx, "girus")
+	if err != nil {
+		t.Fatalf("WatchStatus: %v", err)
+	}
+	if err := watcher.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	snap := watcher.Snapshot()
+	if len(snap.Pods) != 1 || snap.Pods[0].Ready != "1/1" || snap.Pods[0].Restarts != 2 || snap.Pods[0].Status != "Running" {
+		t.Errorf("Pods = %+v", snap.Pods)
+	}
+	if len(snap.Components) != 2 || !snap.Components[0].Ready || snap.Components[1].Ready {
+		t.Errorf("Components = %+v", snap.Components)
+	}
+	if len(snap.Services) != 1 || snap.Services[0].Endpoints != 1 || snap.Services[0].Ports != "8080" {
+		t.Errorf("Services = %+v", snap.Services)
+	}
+	if len(snap.Labs) != 1 || snap.Labs[0] != (k8s.LabSnapshot{Name: "linux-basico", Title: "Linux Básico"}) {
+		t.Errorf("Labs = %+v", snap.Labs)
+	}
+	want := k8s.NodeSnapshot{
+		Name: "girus-control-plane", Ready: true,
+		CPURequested: 750, CPUAllocatable: 4000,
+		MemoryRequested: 256 << 20, MemoryAllocatable: 8 << 30,
+	}
+	if !snap.NodeAccess || len(snap.Nodes) != 1 || snap.Nodes[0] != want {
+		t.Errorf("Nodes = %+v, esperado %+v", snap.Nodes, want)
+	}
+	if len(snap.Events) != 1 || snap.Events[0].Object != "Pod/girus-frontend-1" || snap.Events[0].Message != "Back-off restarting failed container" {
+		t.Errorf("Events = %+v", snap.Events)
+	}
+
+	// Drena o sinal da carga inicial do cache
+	select {
+	case <-watcher.Changes():
+	default:
+	}
+
+	frontend := componentPod("girus-frontend", true)
+	if _, err := clientset.CoreV1().Pods("girus").Create(ctx, frontend, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		select {
+		case <-watcher.Changes():
+		case <-ctx.Done():
+			t.Fatal("o watcher não observou o novo pod")
+		}
+		if snap := watcher.Snapshot(); len(snap.Pods) == 2 && snap.Components[1].Ready {
+			return
+		}
+	}
+}
+
+func TestStatusWatcherWithoutNodeAccess(t *testing.T) {
+	client, clientset := newFakeClient(componentPod("girus-backend", true))
+	// Sem list de nós os pods de todos os namespaces também não são observados
+	k8sfake.AllowAccessReviews(clientset, "list/nodes")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	watcher, err := client.WatchStatus(ctx, "girus")
+	if err != nil {
+		t.Fatalf("WatchStatus: %v", err)
+	}
+	if err := watcher.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	snap := watcher.Snapshot()
+	if snap.NodeAccess || len(snap.Nodes) != 0 {
+		t.Errorf("NodeAccess = %v, Nodes = %+v, esperado sem os nós", snap.NodeAccess, snap.Nodes)
+	}
+	if len(snap.Pods) != 1 {
+		t.Errorf("Pods = %+v", snap.Pods)
+	}
+
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "watch" && (action.GetResource().Resource == "nodes" || action.GetNamespace() == "") {
+			t.Errorf("watch fora do namespace: %s %s", action.GetResource().Resource, action.GetNamespace())
+		}
+	}
+}
+
+func TestStatusWatcherPermissionDenied(t *testing.T) {
+	client, clientset := newFakeClient()
+	k8sfake.AllowAccessReviews(clientset, "watch/events")
+
+	_, err := client.WatchStatus(context.Background(), "girus")
+	var permErr *k8s.PermissionError
+	if !errors.As(err, &permErr) {
+		t.Fatalf("erro = %v, esperado *PermissionError", err)
+	}
+	if len(permErr.Denied) != 1 || permErr.Denied[0] != "watch events (namespace girus)" {
+		t.Errorf("Denied = %v", permErr.Denied)
+	}
+}
@@ -0,0 +1,126 @@
+package lab
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/badtuxx/girus-cli/internal/k8s"
+)
+
+// InstalledRecord é a procedência de um laboratório instalado em um cluster
+type InstalledRecord struct {
+	// Cluster é o contexto do kubeconfig do cluster
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	ConfigMap string `json:"configMap"`
+	k8s.LabProvenance
+}
+
+// InstalledState espelha em ~/.girus/installed-labs.json as anotações de
+// procedência dos laboratórios instalados, para consulta sem acesso ao
+// cluster. As anotações nos ConfigMaps continuam sendo a fonte da verdade.
+type InstalledState struct {
+	path string
+	Labs []InstalledRecord `json:"labs"`
+}
+
+// LoadInstalledState lê o estado local dos laboratórios instalados
+func LoadInstalledState() (*InstalledState, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter diretório home: %v", err)
+	}
+	s := &InstalledState{path: filepath.Join(home, ".girus", "installed-labs.json"), Labs: []InstalledRecord{}}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler %s: %v", s.path, err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar %s: %v", s.path, err)
+	}
+	return s, nil
+}
+
+// Record registra (ou substitui) a procedência de um ConfigMap
+func (s *InstalledState) Record(cluster, namespace, configMap string, provenance k8s.LabProvenance) {
+	s.Remove(cluster, namespace, configMap)
+	s.Labs = append(s.Labs, InstalledRecord{Cluster: cluster, Namespace: namespace, ConfigMap: configMap, LabProvenance: provenance})
+}
+
+// Remove apaga o registro de um ConfigMap
+func (s *InstalledState) Remove(cluster, namespace, configMap string) {
+	kept := s.Labs[:0]
+	for _, r := range s.Labs {
+		if r.Cluster != cluster || r.Namespace != namespace || r.ConfigMap != configMap {
+			kept = append(kept, r)
+		}
+	}
+	s.Labs = kept
+}
+
+// Sync substitui os registros do cluster e namespace pelos laboratórios com
+// procedência encontrados no cluster
+func (s *InstalledState) Sync(cluster, namespace string, labs []k8s.InstalledLab) {
+	kept := s.Labs[:0]
+	for _, r := range s.Labs {
+		if r.Cluster != cluster || r.Namespace != namespace {
+			kept = append(kept, r)
+		}
+	}
+	s.Labs = kept
+	for _, l := range labs {
+		if l.Provenance != nil {
+			s.Labs = append(s.Labs, InstalledRecord{Cluster: cluster, Namespace: namespace, ConfigMap: l.ConfigMap, LabProvenance: *l.Provenance})
+		}
+	}
+}
+
+// Find retorna os registros do cluster e namespace, ordenados pelo ConfigMap
+func (s *InstalledState) Find(cluster, namespace string) []InstalledRecord {
+	records := []InstalledRecord{}
+	for _, r := range s.Labs {
+		if r.Cluster == cluster && r.Namespace == namespace {
+			records = append(records, r)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ConfigMap < records[j].ConfigMap })
+	return records
+}
+
+// Save grava o estado local
+func (s *InstalledState) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de configuração: %v", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao codificar o estado dos laboratórios: %v", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("erro ao salvar %s: %v", s.path, err)
+	}
+	return nil
+}
+
+// RecordInstalled registra no estado local a procedência dos ConfigMaps
+// aplicados no cluster atual
+func RecordInstalled(provenance *k8s.LabProvenance, results []k8s.ApplyResult) error {
+	state, err := LoadInstalledState()
+	if err != nil {
+		return err
+	}
+	cluster := k8s.CurrentClientOptions().ContextName()
+	for _, r := range results {
+		if r.Kind == "ConfigMap" {
+			state.Record(cluster, r.Namespace, r.Name, *provenance)
+		}
+	}
+	return state.Save()
+}
@@ -1,77 +1,105 @@
 package lab
 
 import (
-	"bufio"
-	"bytes"
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"runtime"
 	"strings"
 	"time"
 
+	"github.com/badtuxx/girus-cli/internal/common"
+	"github.com/badtuxx/girus-cli/internal/executil"
 	"github.com/badtuxx/girus-cli/internal/helpers"
 	"github.com/badtuxx/girus-cli/internal/k8s"
 	"github.com/schollz/progressbar/v3"
 )
 
-// addLabFromFile adiciona um novo template de laboratório a partir de um arquivo
-func AddLabFromFile(labFile string, verboseMode bool) {
+// ConfirmFunc pergunta ao usuário se a operação deve continuar. Retorna um erro
+// quando a pergunta não pode ser feita (sem terminal, por exemplo).
+type ConfirmFunc func(question string) (bool, error)
+
+// AddLabOptions são as dependências e opções de AddLabFromFile
+type AddLabOptions struct {
+	// Client aplica o laboratório e reinicia o backend
+	Client *k8s.KubernetesClient
+	// Runner executa o kubectl e o docker nas verificações do ambiente
+	Runner executil.Runner
+	// Provenance, se informada, é gravada nas anotações do ConfigMap e no
+	// estado local
+	Provenance *k8s.LabProvenance
+	Verbose    bool
+	// Confirm faz as perguntas ao usuário
+	Confirm ConfirmFunc
+	// StartupDelay é a espera pela inicialização do backend reiniciado
+	StartupDelay time.Duration
+	// PortForward refaz os port-forwards quando o backend e o frontend não
+	// respondem após o reinício; nil mantém os port-forwards como estão
+	PortForward func(namespace string) error
+}
+
+// AddLabFromFile adiciona um novo template de laboratório a partir de um
+// arquivo. Os erros de cluster ausente e backend parado carregam
+// common.ExitClusterNotFound e common.ExitBackendUnhealthy.
+func AddLabFromFile(ctx context.Context, labFile string, opts AddLabOptions) error {
+	client, provenance, verboseMode, confirm := opts.Client, opts.Provenance, opts.Verbose, opts.Confirm
+
 	// Verificar se o arquivo existe
 	if _, err := os.Stat(labFile); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "❌ Erro: arquivo '%s' não encontrado\n", labFile)
-		os.Exit(1)
+		return fmt.Errorf("arquivo '%s' não encontrado", labFile)
 	}
 
 	fmt.Println("🔍 Verificando ambiente Girus...")
 
 	// Verificar se há um cluster Girus ativo
-	checkCmd := exec.Command("kubectl", "get", "namespace", "girus", "--no-headers", "--ignore-not-found")
-	checkOutput, err := checkCmd.Output()
-	if err != nil || !strings.Contains(string(checkOutput), "girus") {
-		fmt.Fprintf(os.Stderr, "❌ Nenhum cluster Girus ativo encontrado\n")
+	checkOutput, err := opts.Runner.Output(ctx, "kubectl", k8s.KubectlArgs("get", "namespace", k8s.Namespace(), "--no-headers", "--ignore-not-found")...)
+	if err != nil || !strings.Contains(string(checkOutput), k8s.Namespace()) {
 		fmt.Println("   Use 'girus create cluster' para criar um cluster ou 'girus list clusters' para ver os disponíveis.")
-		os.Exit(1)
+		return common.WithExitCode(common.ExitClusterNotFound, fmt.Errorf("nenhum cluster Girus ativo encontrado"))
 	}
 
 	// Verificar o pod do backend (silenciosamente, só mostra mensagem em caso de erro)
-	backendCmd := exec.Command("kubectl", "get", "pods", "-n", "girus", "-l", "app=girus-backend", "-o", "jsonpath={.items[0].status.phase}")
-	backendOutput, err := backendCmd.Output()
+	backendOutput, err := opts.Runner.Output(ctx, "kubectl", k8s.KubectlArgs("get", "pods", "-n", k8s.Namespace(), "-l", "app=girus-backend", "-o", "jsonpath={.items[0].status.phase}")...)
 	if err != nil || string(backendOutput) != "Running" {
-		fmt.Fprintf(os.Stderr, "❌ O backend do Girus não está em execução\n")
 		fmt.Println("   Verifique o status dos pods com 'kubectl get pods -n girus'")
-		os.Exit(1)
+		return common.WithExitCode(common.ExitBackendUnhealthy, fmt.Errorf("o backend do Girus não está em execução"))
 	}
 
-	// Ler o arquivo para verificar se é um ConfigMap válido
-	content, err := os.ReadFile(labFile)
+	// Interpretar o arquivo para verificar se é um manifesto de laboratório válido
+	manifests, err := ParseFile(labFile)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Erro ao ler o arquivo '%s': %v\n", labFile, err)
-		os.Exit(1)
+		return fmt.Errorf("o arquivo não é um manifesto de laboratório válido: %w", err)
 	}
 
-	// Verificação simples se o arquivo parece ser um ConfigMap válido
-	fileContent := string(content)
-	if !strings.Contains(fileContent, "kind: ConfigMap") ||
-		!strings.Contains(fileContent, "app: girus-lab-template") {
-		fmt.Fprintf(os.Stderr, "❌ O arquivo não é um manifesto de laboratório válido\n")
+	templates := FindLabTemplates(manifests)
+	if len(templates) == 0 || templates[0].Metadata.Labels[TemplateLabelKey] != TemplateLabelValue {
 		fmt.Println("   O arquivo deve ser um ConfigMap com a label 'app: girus-lab-template'")
-		os.Exit(1)
+		return fmt.Errorf("o arquivo não é um manifesto de laboratório válido")
+	}
+	labTemplate := templates[0].Lab
+
+	// Laboratórios no formato nativo (kind: Lab) são convertidos para ConfigMap
+	var applyData []byte
+	if HasNativeLabs(manifests) {
+		applyData, err = RenderConfigMaps(manifests)
+		if err != nil {
+			return fmt.Errorf("erro ao converter o laboratório para ConfigMap: %w", err)
+		}
+	} else if applyData, err = os.ReadFile(labFile); err != nil {
+		return fmt.Errorf("erro ao ler o arquivo: %w", err)
 	}
 
 	// Verificar se está instalando o lab do Docker e se o Docker está disponível
-	if strings.Contains(fileContent, "docker-basics") {
+	if labTemplate.Name == "docker-basics" {
 		fmt.Println("🐳 Detectado laboratório de Docker, verificando dependências...")
 
 		// Verificar se o Docker está instalado
-		dockerCmd := exec.Command("docker", "--version")
-		dockerInstalled := dockerCmd.Run() == nil
+		dockerInstalled := opts.Runner.Run(ctx, nil, "docker", "--version") == nil
 
 		// Verificar se o serviço está rodando
 		dockerRunning := false
 		if dockerInstalled {
-			infoCmd := exec.Command("docker", "info")
-			dockerRunning = infoCmd.Run() == nil
+			dockerRunning = opts.Runner.Run(ctx, nil, "docker", "info") == nil
 		}
 
 		if !dockerInstalled || !dockerRunning {
@@ -93,14 +121,14 @@ func AddLabFromFile(labFile string, verboseMode bool) {
 				fmt.Println("\n   📦 Visite: https://www.docker.com/products/docker-desktop")
 			}
 
-			fmt.Println("\n   Você deseja continuar com a instalação do template? [s/N]")
-			reader := bufio.NewReader(os.Stdin)
-			response, _ := reader.ReadString('\n')
-			response = strings.ToLower(strings.TrimSpace(response))
-
-			if response != "s" && response != "sim" && response != "y" && response != "yes" {
+			fmt.Println()
+			ok, err := confirm("   Você deseja continuar com a instalação do template?")
+			if err != nil {
+				return err
+			}
+			if !ok {
 				fmt.Println("Instalação cancelada.")
-				os.Exit(0)
+				return nil
 			}
 
 			fmt.Println("Continuando com a instalação do template Docker...")
@@ -111,21 +139,23 @@ func AddLabFromFile(labFile string, verboseMode bool) {
 
 	fmt.Printf("📦 Processando laboratório: %s\n", labFile)
 
-	// Aplicar o ConfigMap no cluster usando kubectl apply
+	// Aplicar o ConfigMap no cluster usando server-side apply
 	if verboseMode {
 		fmt.Println("   Aplicando ConfigMap no cluster...")
 	}
 
 	// Aplicar o ConfigMap no cluster
+	var applied []k8s.ApplyResult
 	if verboseMode {
-		// Executar normalmente mostrando o output
-		applyCmd := exec.Command("kubectl", "apply", "-f", labFile)
-		applyCmd.Stdout = os.Stdout
-		applyCmd.Stderr = os.Stderr
-		if err := applyCmd.Run(); err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Erro ao aplicar o laboratório: %v\n", err)
-			os.Exit(1)
+		// Mostrar o resultado de cada objeto aplicado
+		results, err := client.ApplyLab(ctx, applyData, provenance)
+		for _, r := range results {
+			fmt.Println("   " + r.String())
 		}
+		if err != nil {
+			return fmt.Errorf("erro ao aplicar o laboratório: %w", err)
+		}
+		applied = results
 	} else {
 		// Usar barra de progresso
 		bar := progressbar.NewOptions(100,
@@ -139,19 +169,7 @@ func AddLabFromFile(labFile string, verboseMode bool) {
 			progressbar.OptionFullWidth(),
 		)
 
-		// Executar comando sem mostrar saída
-		applyCmd := exec.Command("kubectl", "apply", "-f", labFile)
-		var stderr bytes.Buffer
-		applyCmd.Stderr = &stderr
-
-		// Iniciar o comando
-		err := applyCmd.Start()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Erro ao iniciar o comando: %v\n", err)
-			os.Exit(1)
-		}
-
-		// Atualizar a barra de progresso enquanto o comando está em execução
+		// Atualizar a barra de progresso enquanto o laboratório é aplicado
 		done := make(chan struct{})
 		go func() {
 			for {
@@ -165,68 +183,34 @@ func AddLabFromFile(labFile string, verboseMode bool) {
 			}
 		}()
 
-		// Aguardar o final do comando
-		err = applyCmd.Wait()
+		// Aplicar sem mostrar saída
+		results, err := client.ApplyLab(ctx, applyData, provenance)
 		close(done)
 		bar.Finish()
 
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "\n❌ Erro ao aplicar o laboratório: %v\n", err)
-			if verboseMode {
-				fmt.Fprintf(os.Stderr, "   Detalhes: %s\n", stderr.String())
-			}
-			os.Exit(1)
+			fmt.Println()
+			return fmt.Errorf("erro ao aplicar o laboratório: %w", err)
 		}
+		applied = results
 	}
 
-	// Extrair o ID do lab (name) do arquivo YAML para mostrar na mensagem
-	var labID string
-	// Procurar pela linha 'name:' dentro do bloco lab.yaml:
-	labNameCmd := exec.Command("sh", "-c", fmt.Sprintf("grep -A10 'lab.yaml:' %s | grep 'name:' | head -1", labFile))
-	labNameOutput, err := labNameCmd.Output()
-	if err == nil {
-		nameLine := strings.TrimSpace(string(labNameOutput))
-		parts := strings.SplitN(nameLine, "name:", 2)
-		if len(parts) >= 2 {
-			labID = strings.TrimSpace(parts[1])
+	if provenance != nil {
+		if err := RecordInstalled(provenance, applied); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Aviso: não foi possível registrar o laboratório no estado local: %v\n", err)
 		}
 	}
 
-	// Extrair também o título para exibição
-	var labTitle string
-	labTitleCmd := exec.Command("sh", "-c", fmt.Sprintf("grep -A10 'lab.yaml:' %s | grep 'title:' | head -1", labFile))
-	labTitleOutput, err := labTitleCmd.Output()
-	if err == nil {
-		titleLine := strings.TrimSpace(string(labTitleOutput))
-		parts := strings.SplitN(titleLine, "title:", 2)
-		if len(parts) >= 2 {
-			labTitle = strings.TrimSpace(parts[1])
-			labTitle = strings.Trim(labTitle, "\"'")
-		}
-	}
+	// ID e título do laboratório para exibição
+	labID := labTemplate.Name
+	labTitle := labTemplate.Title
 
 	fmt.Println("\n🔄 Reiniciando backend para carregar o template...")
 
 	// O backend apenas carrega os templates na inicialização
 	if verboseMode {
-		// Mostrar o output da reinicialização
 		fmt.Println("   (O backend do Girus carrega os templates apenas na inicialização)")
-		restartCmd := exec.Command("kubectl", "rollout", "restart", "deployment/girus-backend", "-n", "girus")
-		restartCmd.Stdout = os.Stdout
-		restartCmd.Stderr = os.Stderr
-		if err := restartCmd.Run(); err != nil {
-			fmt.Fprintf(os.Stderr, "⚠️  Erro ao reiniciar o backend: %v\n", err)
-			fmt.Println("   O template foi aplicado, mas pode ser necessário reiniciar o backend manualmente:")
-			fmt.Println("   kubectl rollout restart deployment/girus-backend -n girus")
-		}
-
-		// Aguardar o reinício completar
 		fmt.Println("   Aguardando o reinício do backend completar...")
-		waitCmd := exec.Command("kubectl", "rollout", "status", "deployment/girus-backend", "-n", "girus", "--timeout=60s")
-		// Redirecionar saída para não exibir detalhes do rollout
-		var waitOutput bytes.Buffer
-		waitCmd.Stdout = &waitOutput
-		waitCmd.Stderr = &waitOutput
 
 		// Iniciar indicador de progresso simples
 		spinChars := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
@@ -245,10 +229,15 @@ func AddLabFromFile(labFile string, verboseMode bool) {
 			}
 		}()
 
-		// Executar e aguardar
-		waitCmd.Run()
+		err := client.RestartBackend(ctx)
 		close(done)
-		fmt.Println("\r   ✅ Backend reiniciado com sucesso!            ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\r⚠️  Erro ao reiniciar o backend: %v\n", err)
+			fmt.Println("   O template foi aplicado, mas pode ser necessário reiniciar o backend manualmente:")
+			fmt.Println("   kubectl rollout restart deployment/girus-backend -n girus")
+		} else {
+			fmt.Println("\r   ✅ Backend reiniciado com sucesso!            ")
+		}
 	} else {
 		// Usar barra de progresso
 		bar := progressbar.NewOptions(100,
@@ -262,71 +251,42 @@ func AddLabFromFile(labFile string, verboseMode bool) {
 			progressbar.OptionFullWidth(),
 		)
 
-		// Reiniciar o deployment do backend
-		restartCmd := exec.Command("kubectl", "rollout", "restart", "deployment/girus-backend", "-n", "girus")
-		var stderr bytes.Buffer
-		restartCmd.Stderr = &stderr
+		// Atualizar a barra de progresso enquanto o rollout acontece
+		done := make(chan struct{})
+		go func() {
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					bar.Add(1)
+					time.Sleep(100 * time.Millisecond)
+				}
+			}
+		}()
 
-		err := restartCmd.Run()
+		err := client.RestartBackend(ctx)
+		close(done)
+		bar.Finish()
 		if err != nil {
-			bar.Finish()
 			fmt.Fprintf(os.Stderr, "\n⚠️  Erro ao reiniciar o backend: %v\n", err)
-			if verboseMode {
-				fmt.Fprintf(os.Stderr, "   Detalhes: %s\n", stderr.String())
-			}
 			fmt.Println("   O template foi aplicado, mas pode ser necessário reiniciar o backend manualmente:")
 			fmt.Println("   kubectl rollout restart deployment/girus-backend -n girus")
 		} else {
-			// Aguardar o reinício completar
-			waitCmd := exec.Command("kubectl", "rollout", "status", "deployment/girus-backend", "-n", "girus", "--timeout=60s")
-
-			// Redirecionar saída para não exibir detalhes do rollout
-			var waitOutput bytes.Buffer
-			waitCmd.Stdout = &waitOutput
-			waitCmd.Stderr = &waitOutput
-
-			// Iniciar o comando
-			err = waitCmd.Start()
-			if err != nil {
-				bar.Finish()
-				fmt.Fprintf(os.Stderr, "\n⚠️  Erro ao verificar status do reinício: %v\n", err)
-			} else {
-				// Atualizar a barra de progresso enquanto o comando está em execução
-				done := make(chan struct{})
-				go func() {
-					for {
-						select {
-						case <-done:
-							return
-						default:
-							bar.Add(1)
-							time.Sleep(100 * time.Millisecond)
-						}
-					}
-				}()
-
-				// Aguardar o final do comando
-				waitCmd.Wait()
-				close(done)
-				fmt.Println("\r   ✅ Backend reiniciado com sucesso!            ")
-			}
-			bar.Finish()
+			fmt.Println("\r   ✅ Backend reiniciado com sucesso!            ")
 		}
 	}
 
 	// Aguardar mais alguns segundos para que o backend reinicie completamente
 	fmt.Println("   Aguardando inicialização completa...")
-	time.Sleep(3 * time.Second)
+	time.Sleep(opts.StartupDelay)
 
 	// Após reiniciar o backend, verificar se precisamos recriar o port-forward
-	portForwardStatus := helpers.CheckPortForwardNeeded()
-
-	// Se port-forward é necessário, configurá-lo corretamente
-	if portForwardStatus {
+	if opts.PortForward != nil && helpers.CheckPortForwardNeeded() {
 		fmt.Println("\n🔌 Reconfigurando port-forwards após reinício do backend...")
 
-		// Usar a função setupPortForward para garantir que ambos os serviços estejam acessíveis
-		err := k8s.SetupPortForward("girus")
+		// Garantir que ambos os serviços estejam acessíveis
+		err := opts.PortForward(k8s.Namespace())
 		if err != nil {
 			fmt.Println("⚠️ Aviso:", err)
 			fmt.Println("   Para configurar manualmente, execute:")
@@ -337,25 +297,6 @@ func AddLabFromFile(labFile string, verboseMode bool) {
 			fmt.Println("   🔹 Backend: http://localhost:8080")
 			fmt.Println("   🔹 Frontend: http://localhost:8000")
 		}
-	} else {
-		// Verificar conexão com o frontend mesmo que o port-forward não seja necessário
-		checkCmd := exec.Command("curl", "-s", "--max-time", "1", "-o", "/dev/null", "-w", "%{http_code}", "http://localhost:8000")
-		var out bytes.Buffer
-		checkCmd.Stdout = &out
-
-		if checkCmd.Run() != nil || !strings.Contains(strings.TrimSpace(out.String()), "200") {
-			fmt.Println("\n⚠️ Detectado problema na conexão com o frontend.")
-			fmt.Println("   Reconfigurando port-forwards para garantir acesso...")
-
-			// Forçar reconfiguração de port-forwards
-			err := k8s.SetupPortForward("girus")
-			if err != nil {
-				fmt.Println("   ⚠️", err)
-				fmt.Println("   Configure manualmente: kubectl port-forward -n girus svc/girus-frontend 8000:80 --address 0.0.0.0")
-			} else {
-				fmt.Println("   ✅ Port-forwards reconfigurados com sucesso!")
-			}
-		}
 	}
 
 	// Desenhar uma linha separadora
@@ -388,4 +329,5 @@ func AddLabFromFile(labFile string, verboseMode bool) {
 
 	// Linha final
 	fmt.Println(strings.Repeat("─", 60))
+	return nil
 }
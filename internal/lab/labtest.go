@@ -0,0 +1,280 @@
+package lab
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/executil"
+)
+
+// ErrTimeout indica que um comando excedeu o tempo limite do teste
+var ErrTimeout = errors.New("tempo esgotado")
+
+// Executor executa comandos dentro do ambiente de um laboratório
+type Executor interface {
+	Exec(ctx context.Context, command string) (string, error)
+}
+
+// CommandResult é o resultado de um comando da solução
+type CommandResult struct {
+	Command string
+	Output  string
+	Err     error
+}
+
+// ValidationResult é o resultado de uma validação de tarefa
+type ValidationResult struct {
+	Validation Validation
+	Output     string
+	Passed     bool
+	// Reason explica a falha (vazio quando a validação passou)
+	Reason string
+}
+
+// TaskResult agrupa o resultado da solução e das validações de uma tarefa
+type TaskResult struct {
+	Task        string
+	Solution    []CommandResult
+	Validations []ValidationResult
+}
+
+// Passed informa se todas as validações da tarefa passaram
+func (r TaskResult) Passed() bool {
+	for _, v := range r.Validations {
+		if !v.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// TestOptions controla a execução de RunTests
+type TestOptions struct {
+	// SkipSolution executa apenas as validações, sem o script de solução
+	SkipSolution bool
+	// Timeout limita a duração de cada comando (zero = sem limite)
+	Timeout time.Duration
+	// OnCommand é chamado após cada comando da solução (opcional)
+	OnCommand func(task string, r CommandResult)
+}
+
+// RunTests executa, para cada tarefa, os comandos da solução e em seguida as
+// validações, na ordem em que aparecem no laboratório
+func RunTests(ctx context.Context, l *Lab, ex Executor, opts TestOptions) []TaskResult {
+	results := make([]TaskResult, 0, len(l.Tasks))
+
+	run := func(command string) (string, error) {
+		cctx := ctx
+		if opts.Timeout > 0 {
+			var cancel context.CancelFunc
+			cctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+			defer cancel()
+		}
+		out, err := ex.Exec(cctx, command)
+		if cctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("%w após %s", ErrTimeout, opts.Timeout)
+		}
+		return out, err
+	}
+
+	for _, task := range l.Tasks {
+		result := TaskResult{Task: task.Name}
+
+		if !opts.SkipSolution {
+			for _, command := range SolutionCommands(task) {
+				out, err := run(command)
+				r := CommandResult{Command: command, Output: out, Err: err}
+				result.Solution = append(result.Solution, r)
+				if opts.OnCommand != nil {
+					opts.OnCommand(task.Name, r)
+				}
+			}
+		}
+
+		for _, v := range task.Validation {
+			// O resultado depende apenas da saída, como no backend; o código de
+			// saída só é considerado quando o comando não chegou a terminar
+			out, err := run(v.Command)
+			vr := ValidationResult{Validation: v, Output: out}
+			if errors.Is(err, ErrTimeout) || err != nil && ctx.Err() != nil {
+				vr.Reason = err.Error()
+			} else {
+				vr.Passed, vr.Reason = CheckValidation(v, out)
+			}
+			result.Validations = append(result.Validations, vr)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// Blocos de código em um único passo: ```bash\n...\n```
+var fencedBlockRe = regexp.MustCompile("(?s)^```[a-zA-Z]*\\s*\\n(.*?)\\n?```$")
+
+// Passo composto apenas por um comando: `comando`
+var inlineCommandRe = regexp.MustCompile("^`([^`]+)`$")
+
+// SolutionCommands extrai o "script de solução" de uma tarefa: os comandos dos
+// passos estruturados, os passos compostos apenas por um comando entre crases e
+// os blocos de código completos contidos em um único passo. Trechos entre crases
+// no meio de um texto explicativo são ignorados.
+func SolutionCommands(task Task) []string {
+	var commands []string
+	for _, step := range task.Steps {
+		if !step.IsText() {
+			if c := strings.TrimSpace(step.Command); c != "" {
+				commands = append(commands, c)
+			}
+			continue
+		}
+
+		text := strings.TrimSpace(step.Text)
+		if m := fencedBlockRe.FindStringSubmatch(text); m != nil {
+			if c := strings.TrimSpace(m[1]); c != "" {
+				commands = append(commands, c)
+			}
+			continue
+		}
+		if m := inlineCommandRe.FindStringSubmatch(text); m != nil {
+			commands = append(commands, strings.TrimSpace(m[1]))
+		}
+	}
+	return commands
+}
+
+// Expressões aceitas em expectedExpression: "~ texto" ou comparação numérica
+var expressionRe = regexp.MustCompile(`^(~|==|!=|>=|<=|>|<)\s*(.+)$`)
+
+// CheckValidation verifica a saída de um comando de validação. expectedOutput
+// deve estar contido na saída; expectedExpression aceita "~ texto" (contém) ou
+// uma comparação numérica como ">= 2".
+func CheckValidation(v Validation, output string) (bool, string) {
+	output = strings.TrimSpace(output)
+
+	if expr := strings.TrimSpace(v.ExpectedExpression); expr != "" {
+		m := expressionRe.FindStringSubmatch(expr)
+		if m == nil {
+			return false, fmt.Sprintf("expressão '%s' inválida", expr)
+		}
+		op, operand := m[1], strings.TrimSpace(m[2])
+		if op == "~" {
+			if strings.Contains(output, operand) {
+				return true, ""
+			}
+			return false, fmt.Sprintf("saída não contém '%s'", operand)
+		}
+
+		got, err := strconv.ParseFloat(output, 64)
+		if err != nil {
+			return false, fmt.Sprintf("saída '%s' não é numérica", output)
+		}
+		want, err := strconv.ParseFloat(operand, 64)
+		if err != nil {
+			return false, fmt.Sprintf("expressão '%s' inválida", expr)
+		}
+		ok := map[string]bool{
+			"==": got == want,
+			"!=": got != want,
+			">=": got >= want,
+			"<=": got <= want,
+			">":  got > want,
+			"<":  got < want,
+		}[op]
+		if !ok {
+			return false, fmt.Sprintf("saída %s não satisfaz '%s'", output, expr)
+		}
+		return true, ""
+	}
+
+	// Sem saída esperada qualquer saída passaria; o lint trata o caso como erro
+	expected := strings.TrimSpace(v.ExpectedOutput)
+	if expected == "" {
+		return false, "expectedOutput vazio"
+	}
+	if !strings.Contains(output, expected) {
+		return false, fmt.Sprintf("esperado '%s', obtido '%s'", expected, output)
+	}
+	return true, ""
+}
+
+// Container é um ambiente de laboratório executado na engine de container local
+type Container struct {
+	Engine string
+	Name   string
+	shell  string
+	runner executil.Runner
+}
+
+// StartContainer inicia a imagem do laboratório em segundo plano usando a engine
+// informada (docker ou podman), executada pelo runner. O container é mantido em
+// execução até Close.
+func StartContainer(ctx context.Context, runner executil.Runner, engine string, l *Lab) (*Container, error) {
+	if l.Image == "" {
+		return nil, fmt.Errorf("o laboratório '%s' não define uma imagem", l.Name)
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("erro ao gerar nome do container: %w", err)
+	}
+	if runner == nil {
+		runner = executil.ExecRunner{}
+	}
+	c := &Container{Engine: engine, Name: fmt.Sprintf("girus-test-%s-%s", l.Name, hex.EncodeToString(suffix)), runner: runner}
+
+	// Mantém o container vivo mesmo que o entrypoint do laboratório termine
+	script := "while true; do sleep 3600; done"
+	if l.Entrypoint != "" {
+		script = l.Entrypoint + " & " + script
+	}
+
+	args := []string{"run", "-d", "--name", c.Name, "--entrypoint", "sh"}
+	if l.Privileged {
+		args = append(args, "--privileged")
+	}
+	args = append(args, l.Image, "-c", script)
+
+	var out bytes.Buffer
+	if err := runner.Run(ctx, &out, engine, args...); err != nil {
+		return nil, fmt.Errorf("erro ao iniciar a imagem '%s' com %s: %v\n%s", l.Image, engine, err, strings.TrimSpace(out.String()))
+	}
+
+	// Usa bash quando disponível, pois muitos comandos dos laboratórios dependem dele
+	c.shell = "sh"
+	if _, err := c.exec(ctx, "sh", "command -v bash"); err == nil {
+		c.shell = "bash"
+	}
+
+	return c, nil
+}
+
+// Exec executa um comando no container e retorna a saída combinada
+func (c *Container) Exec(ctx context.Context, command string) (string, error) {
+	return c.exec(ctx, c.shell, command)
+}
+
+func (c *Container) exec(ctx context.Context, shell, command string) (string, error) {
+	var out bytes.Buffer
+	err := c.runner.Run(ctx, &out, c.Engine, "exec", c.Name, shell, "-c", command)
+	return out.String(), err
+}
+
+// Close remove o container. A remoção não usa o contexto dos testes, para que
+// o container seja removido também após uma interrupção.
+func (c *Container) Close() error {
+	var out bytes.Buffer
+	if err := c.runner.Run(context.Background(), &out, c.Engine, "rm", "-f", c.Name); err != nil {
+		return fmt.Errorf("erro ao remover o container '%s': %v\n%s", c.Name, err, strings.TrimSpace(out.String()))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package lab_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/lab"
+)
+
+// fakeExecutor simula o container guardando os comandos executados
+type fakeExecutor struct {
+	commands []string
+	outputs  map[string]string
+}
+
+func (f *fakeExecutor) Exec(_ context.Context, command string) (string, error) {
+	f.commands = append(f.commands, command)
+	return f.outputs[command], nil
+}
+
+func TestSolutionCommands(t *testing.T) {
+	task := lab.Task{Steps: []lab.Step{
+		{Text: "Use o comando `pwd` para ver o diretório atual:"},
+		{Text: "`pwd`"},
+		{Text: "```bash\nmkdir -p /tmp/lab\ncd /tmp/lab\n```"},
+		{Text: "```"},
+		{Description: "Liste os arquivos", Command: "ls -la"},
+	}}
+
+	got := lab.SolutionCommands(task)
+	want := []string{"pwd", "mkdir -p /tmp/lab\ncd /tmp/lab", "ls -la"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("comandos esperados %q, obtidos %q", want, got)
+	}
+}
+
+func TestCheckValidation(t *testing.T) {
+	tests := []struct {
+		v      lab.Validation
+		output string
+		passed bool
+	}{
+		{lab.Validation{ExpectedOutput: "success"}, "success\n", true},
+		{lab.Validation{ExpectedOutput: "Apply complete!"}, "Apply complete! Resources: 1 added.", true},
+		{lab.Validation{ExpectedOutput: "ok"}, "", false},
+		{lab.Validation{}, "qualquer saída", false},
+		{lab.Validation{ExpectedOutput: "  "}, "", false},
+		{lab.Validation{ExpectedExpression: "~ deployment"}, "deployment.apps/nginx", true},
+		{lab.Validation{ExpectedExpression: ">= 2"}, "3", true},
+		{lab.Validation{ExpectedExpression: "> 3"}, "3", false},
+		{lab.Validation{ExpectedExpression: "> 0"}, "abc", false},
+	}
+	for _, tt := range tests {
+		passed, reason := lab.CheckValidation(tt.v, tt.output)
+		if passed != tt.passed {
+			t.Errorf("%+v com saída %q: esperado %v, obtido %v (%s)", tt.v, tt.output, tt.passed, passed, reason)
+		}
+	}
+}
+
+func TestRunTests(t *testing.T) {
+	l := &lab.Lab{Tasks: []lab.Task{
+		{
+			Name:       "Criar arquivo",
+			Steps:      []lab.Step{{Text: "`touch a.txt`"}},
+			Validation: []lab.Validation{{Command: "test -f a.txt && echo ok", ExpectedOutput: "ok"}},
+		},
+		{
+			Name:       "Contar arquivos",
+			Steps:      []lab.Step{{Text: "Sem comandos"}},
+			Validation: []lab.Validation{{Command: "ls | wc -l", ExpectedExpression: ">= 2"}},
+		},
+	}}
+	ex := &fakeExecutor{outputs: map[string]string{
+		"test -f a.txt && echo ok": "ok\n",
+		"ls | wc -l":               "1\n",
+	}}
+
+	results := lab.RunTests(context.Background(), l, ex, lab.TestOptions{})
+
+	if got := strings.Join(ex.commands, ";"); got != "touch a.txt;test -f a.txt && echo ok;ls | wc -l" {
+		t.Errorf("ordem de execução inesperada: %s", got)
+	}
+	if len(results) != 2 || !results[0].Passed() || results[1].Passed() {
+		t.Errorf("resultados inesperados: %+v", results)
+	}
+
+	ex.commands = nil
+	lab.RunTests(context.Background(), l, ex, lab.TestOptions{SkipSolution: true})
+	if len(ex.commands) != 2 {
+		t.Errorf("com SkipSolution apenas as validações deveriam ser executadas: %q", ex.commands)
+	}
+}
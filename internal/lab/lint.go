@@ -0,0 +1,355 @@
+package lab
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Severity indica a gravidade de um problema encontrado pelo lint
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Regras verificadas pelo lint
+const (
+	RuleParse         = "parse-error"
+	RuleWrapper       = "configmap-wrapper"
+	RuleRequired      = "required-field"
+	RuleDuration      = "duration-format"
+	RuleTipType       = "tip-type"
+	RuleValidation    = "validation"
+	RuleDuplicateTask = "duplicate-task"
+	RuleDuplicateLab  = "duplicate-lab"
+	RuleNoTemplate    = "no-lab-template"
+)
+
+// RuleDescriptions descreve cada regra do lint (usado também na saída SARIF)
+var RuleDescriptions = map[string]string{
+	RuleParse:         "O arquivo deve ser um YAML válido",
+	RuleWrapper:       "O template deve ser um ConfigMap no namespace 'girus', com a label 'app=girus-lab-template' e a chave 'lab.yaml'",
+	RuleRequired:      "Campos obrigatórios do laboratório e das tarefas devem estar preenchidos",
+	RuleDuration:      "Durações devem usar o formato do Go (ex.: 30m, 1h30m)",
+	RuleTipType:       "O tipo das dicas deve ser info, tip ou warning",
+	RuleValidation:    "Validações devem ter um comando e uma saída esperada",
+	RuleDuplicateTask: "Nomes de tarefas não podem se repetir no mesmo laboratório",
+	RuleDuplicateLab:  "Nomes de laboratórios não podem se repetir no mesmo diretório",
+	RuleNoTemplate:    "O arquivo deve conter ao menos um template de laboratório",
+}
+
+// KnownTipTypes são os tipos de dica suportados pelo frontend
+var KnownTipTypes = []string{"info", "tip", "warning"}
+
+// Finding é um problema encontrado pelo lint
+type Finding struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`
+	Lab      string   `json:"lab,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// LintReport agrupa o resultado do lint de um ou mais arquivos
+type LintReport struct {
+	Files    []string  `json:"files"`
+	Findings []Finding `json:"findings"`
+}
+
+// Errors retorna a quantidade de problemas com severidade de erro
+func (r *LintReport) Errors() int {
+	return r.count(SeverityError)
+}
+
+// Warnings retorna a quantidade de avisos
+func (r *LintReport) Warnings() int {
+	return r.count(SeverityWarning)
+}
+
+func (r *LintReport) count(sev Severity) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity == sev {
+			n++
+		}
+	}
+	return n
+}
+
+// LintPaths executa o lint sobre arquivos e diretórios. Diretórios são
+// percorridos recursivamente e nomes de laboratórios repetidos dentro de um
+// mesmo diretório são reportados.
+func LintPaths(paths ...string) (*LintReport, error) {
+	report := &LintReport{Findings: []Finding{}}
+
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao acessar '%s': %w", p, err)
+		}
+
+		var files []string
+		if info.IsDir() {
+			err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				ext := filepath.Ext(path)
+				if !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+					files = append(files, path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("erro ao percorrer '%s': %w", p, err)
+			}
+		} else {
+			files = []string{p}
+		}
+
+		// Nome do laboratório -> primeira ocorrência (por diretório)
+		seen := make(map[string]map[string]Finding)
+		for _, file := range files {
+			report.Files = append(report.Files, file)
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao ler '%s': %w", file, err)
+			}
+
+			manifests, findings := LintData(data, file)
+			report.Findings = append(report.Findings, findings...)
+
+			dir := filepath.Dir(file)
+			if info.IsDir() {
+				dir = p
+			}
+			if seen[dir] == nil {
+				seen[dir] = make(map[string]Finding)
+			}
+			for _, m := range FindLabTemplates(manifests) {
+				if m.Lab.Name == "" {
+					continue
+				}
+				if first, ok := seen[dir][m.Lab.Name]; ok {
+					report.Findings = append(report.Findings, Finding{
+						File:     file,
+						Line:     m.Lab.Line,
+						Severity: SeverityError,
+						Rule:     RuleDuplicateLab,
+						Lab:      m.Lab.Name,
+						Message:  fmt.Sprintf("laboratório '%s' já definido em %s:%d", m.Lab.Name, first.File, first.Line),
+					})
+					continue
+				}
+				seen[dir][m.Lab.Name] = Finding{File: file, Line: m.Lab.Line}
+			}
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+
+	return report, nil
+}
+
+// LintData executa o lint sobre o conteúdo de um arquivo e retorna também os
+// documentos interpretados, para verificações entre arquivos
+func LintData(data []byte, file string) ([]*Manifest, []Finding) {
+	manifests, err := Parse(data, file)
+	if err != nil {
+		return nil, parseFindings(err, file)
+	}
+
+	var findings []Finding
+	candidates := 0
+	for _, m := range manifests {
+		if !isTemplateCandidate(m) {
+			continue
+		}
+		candidates++
+		findings = append(findings, lintManifest(m, file)...)
+	}
+
+	if candidates == 0 {
+		findings = append(findings, Finding{
+			File:     file,
+			Severity: SeverityWarning,
+			Rule:     RuleNoTemplate,
+			Message:  "nenhum template de laboratório encontrado no arquivo",
+		})
+	}
+
+	return manifests, findings
+}
+
+// isTemplateCandidate identifica documentos que pretendem ser templates de laboratório
+func isTemplateCandidate(m *Manifest) bool {
+	if m.Kind != "ConfigMap" {
+		return false
+	}
+	if m.Lab != nil {
+		return true
+	}
+	return m.Metadata.Labels[TemplateLabelKey] == TemplateLabelValue
+}
+
+func lintManifest(m *Manifest, file string) []Finding {
+	var findings []Finding
+	add := func(line int, sev Severity, rule, format string, args ...interface{}) {
+		f := Finding{File: file, Line: line, Severity: sev, Rule: rule, Message: fmt.Sprintf(format, args...)}
+		if m.Lab != nil {
+			f.Lab = m.Lab.Name
+		}
+		findings = append(findings, f)
+	}
+
+	// Verificações do ConfigMap
+	if m.Metadata.Name == "" {
+		add(m.Line, SeverityError, RuleWrapper, "metadata.name é obrigatório")
+	}
+	if m.Metadata.Namespace != TemplateNamespace {
+		add(m.Line, SeverityError, RuleWrapper, "metadata.namespace deve ser '%s' (encontrado '%s')", TemplateNamespace, m.Metadata.Namespace)
+	}
+	if m.Metadata.Labels[TemplateLabelKey] != TemplateLabelValue {
+		add(m.Line, SeverityError, RuleWrapper, "a label '%s=%s' é obrigatória", TemplateLabelKey, TemplateLabelValue)
+	}
+	if m.Lab == nil {
+		add(m.Line, SeverityError, RuleWrapper, "a chave 'data.%s' é obrigatória", TemplateDataKey)
+		return findings
+	}
+
+	l := m.Lab
+
+	// Campos obrigatórios do laboratório
+	required := []struct{ field, value string }{
+		{"name", l.Name},
+		{"title", l.Title},
+		{"description", l.Description},
+		{"duration", l.Duration},
+	}
+	for _, r := range required {
+		if strings.TrimSpace(r.value) == "" {
+			add(l.Line, SeverityError, RuleRequired, "o campo '%s' é obrigatório", r.field)
+		}
+	}
+	if strings.TrimSpace(l.Image) == "" {
+		add(l.Line, SeverityWarning, RuleRequired, "o campo 'image' não foi definido; a imagem padrão do backend será usada")
+	}
+	if len(l.Tasks) == 0 {
+		add(l.Line, SeverityError, RuleRequired, "o laboratório deve ter ao menos uma tarefa")
+	}
+
+	// Formato das durações
+	if l.Duration != "" {
+		if err := checkDuration(l.Duration); err != nil {
+			add(l.Line, SeverityError, RuleDuration, "duration '%s' inválida: %v", l.Duration, err)
+		}
+	}
+	if l.MaxDuration != "" {
+		if err := checkDuration(l.MaxDuration); err != nil {
+			add(l.Line, SeverityError, RuleDuration, "maxDuration '%s' inválida: %v", l.MaxDuration, err)
+		}
+	}
+
+	// Tarefas
+	taskNames := make(map[string]int)
+	for i, task := range l.Tasks {
+		label := fmt.Sprintf("tarefa %d", i+1)
+		if task.Name == "" {
+			add(task.Line, SeverityError, RuleRequired, "%s: o campo 'name' é obrigatório", label)
+		} else {
+			label = fmt.Sprintf("tarefa '%s'", task.Name)
+			if firstLine, ok := taskNames[task.Name]; ok {
+				add(task.Line, SeverityError, RuleDuplicateTask, "%s repetida (primeira ocorrência na linha %d)", label, firstLine)
+			} else {
+				taskNames[task.Name] = task.Line
+			}
+		}
+		if task.Description == "" {
+			add(task.Line, SeverityWarning, RuleRequired, "%s: o campo 'description' está vazio", label)
+		}
+		if len(task.Steps) == 0 {
+			add(task.Line, SeverityError, RuleRequired, "%s: a tarefa deve ter ao menos um passo", label)
+		}
+
+		for j, tip := range task.Tips {
+			if !isKnownTipType(tip.Type) {
+				add(tip.Line, SeverityError, RuleTipType, "%s, dica %d: tipo '%s' desconhecido (use %s)", label, j+1, tip.Type, strings.Join(KnownTipTypes, ", "))
+			}
+		}
+
+		for j, v := range task.Validation {
+			if strings.TrimSpace(v.Command) == "" {
+				add(v.Line, SeverityError, RuleValidation, "%s, validação %d: o campo 'command' está vazio", label, j+1)
+			}
+			if strings.TrimSpace(v.ExpectedOutput) == "" && strings.TrimSpace(v.ExpectedExpression) == "" {
+				add(v.Line, SeverityError, RuleValidation, "%s, validação %d: 'expectedOutput' (ou 'expectedExpression') está vazio", label, j+1)
+			}
+			if strings.TrimSpace(v.ErrorMessage) == "" {
+				add(v.Line, SeverityWarning, RuleValidation, "%s, validação %d: 'errorMessage' está vazio", label, j+1)
+			}
+		}
+	}
+
+	return findings
+}
+
+// checkDuration valida durações no formato aceito pelo backend (ex.: 30m, 1h30m)
+func checkDuration(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("use o formato 30m, 1h ou 1h30m")
+	}
+	if d <= 0 {
+		return fmt.Errorf("a duração deve ser positiva")
+	}
+	return nil
+}
+
+func isKnownTipType(t string) bool {
+	for _, known := range KnownTipTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFindings converte erros de parsing em problemas do lint
+func parseFindings(err error, file string) []Finding {
+	var errs ParseErrors
+	var single *ParseError
+	switch {
+	case errors.As(err, &errs):
+	case errors.As(err, &single):
+		errs = ParseErrors{single}
+	default:
+		errs = ParseErrors{{File: file, Msg: err.Error()}}
+	}
+
+	findings := make([]Finding, 0, len(errs))
+	for _, e := range errs {
+		findings = append(findings, Finding{
+			File:     file,
+			Line:     e.Line,
+			Severity: SeverityError,
+			Rule:     RuleParse,
+			Message:  e.Msg,
+		})
+	}
+	return findings
+}
@@ -0,0 +1,113 @@
+package lab_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/lab"
+)
+
+func TestLintRepositoryLabs(t *testing.T) {
+	for _, dir := range []string{"../templates/manifests", "../templates/manifests_es", "../../labs"} {
+		t.Run(dir, func(t *testing.T) {
+			report, err := lab.LintPaths(dir)
+			if err != nil {
+				t.Fatalf("erro ao executar lint: %v", err)
+			}
+			if len(report.Files) == 0 {
+				t.Fatalf("nenhum arquivo encontrado em %s", dir)
+			}
+			for _, f := range report.Findings {
+				if f.Severity == lab.SeverityError {
+					t.Errorf("%s:%d: [%s] %s", f.File, f.Line, f.Rule, f.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestLintFindings(t *testing.T) {
+	data := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ruim-lab
+  namespace: default
+data:
+  lab.yaml: |
+    name: ruim
+    title: "Lab ruim"
+    duration: 30 minutos
+    tasks:
+      - name: "Tarefa"
+        description: "Primeira"
+        steps:
+          - "passo"
+        tips:
+          - type: "nota"
+            title: "Dica"
+            content: "Conteúdo"
+        validation:
+          - command: ""
+            errorMessage: "erro"
+      - name: "Tarefa"
+        description: "Repetida"
+        steps:
+          - "passo"
+`)
+
+	_, findings := lab.LintData(data, "ruim.yaml")
+
+	want := map[string]int{
+		lab.RuleWrapper:       2, // namespace e label
+		lab.RuleRequired:      2, // description e image (aviso)
+		lab.RuleDuration:      1,
+		lab.RuleTipType:       1,
+		lab.RuleValidation:    2, // command e expectedOutput
+		lab.RuleDuplicateTask: 1,
+	}
+	got := make(map[string]int)
+	for _, f := range findings {
+		got[f.Rule]++
+	}
+	for rule, n := range want {
+		if got[rule] != n {
+			t.Errorf("regra %s: esperados %d problemas, obtidos %d (%v)", rule, n, got[rule], findings)
+		}
+	}
+
+	for _, f := range findings {
+		if f.Rule == lab.RuleTipType && f.Line != 17 {
+			t.Errorf("linha da dica esperada 17, obtida %d", f.Line)
+		}
+	}
+}
+
+func TestLintSARIF(t *testing.T) {
+	report := &lab.LintReport{
+		Files: []string{"a.yaml"},
+		Findings: []lab.Finding{
+			{File: "a.yaml", Line: 3, Severity: lab.SeverityError, Rule: lab.RuleWrapper, Message: "teste"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteSARIF(&buf); err != nil {
+		t.Fatalf("erro ao gerar SARIF: %v", err)
+	}
+
+	var out struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("SARIF inválido: %v", err)
+	}
+	if out.Version != "2.1.0" || len(out.Runs) != 1 || len(out.Runs[0].Results) != 1 || out.Runs[0].Results[0].RuleID != lab.RuleWrapper {
+		t.Errorf("SARIF inesperado: %s", buf.String())
+	}
+}
@@ -0,0 +1,376 @@
+package lab
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Identificação do formato nativo de laboratório do GIRUS
+const (
+	LabAPIVersion = "girus.linuxtips.io/v1"
+	LabKind       = "Lab"
+
+	// DefaultLabDuration é usada quando um laboratório nativo não define spec.duration
+	DefaultLabDuration = "30m"
+)
+
+// Anotações usadas no ConfigMap para preservar os campos do formato nativo que o
+// backend não utiliza, permitindo a conversão nos dois sentidos sem perdas
+const (
+	annotationPrefix          = "girus.linuxtips.io/"
+	AnnotationVersion         = annotationPrefix + "version"
+	AnnotationAuthor          = annotationPrefix + "author"
+	AnnotationCreated         = annotationPrefix + "created"
+	AnnotationResources       = annotationPrefix + "resources"
+	AnnotationVolumes         = annotationPrefix + "volumes"
+	AnnotationValidationTasks = annotationPrefix + "validation-tasks"
+	AnnotationConfigMapName   = annotationPrefix + "configmap-name"
+)
+
+// LabDocument é um laboratório no formato nativo (apiVersion girus.linuxtips.io/v1, kind Lab)
+type LabDocument struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Metadata   LabMetadata `yaml:"metadata"`
+	Spec       LabSpec     `yaml:"spec"`
+}
+
+// LabMetadata contém os metadados de um laboratório nativo
+type LabMetadata struct {
+	Name        string            `yaml:"name"`
+	Title       string            `yaml:"title,omitempty"`
+	Version     string            `yaml:"version,omitempty"`
+	Description string            `yaml:"description,omitempty"`
+	Author      string            `yaml:"author,omitempty"`
+	Created     string            `yaml:"created,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// LabSpec descreve o ambiente, as tarefas e as validações finais do laboratório
+type LabSpec struct {
+	Duration     string            `yaml:"duration,omitempty"`
+	TimerEnabled bool              `yaml:"timerEnabled,omitempty"`
+	MaxDuration  string            `yaml:"maxDuration,omitempty"`
+	YoutubeVideo string            `yaml:"youtubeVideo,omitempty"`
+	Type         string            `yaml:"type,omitempty"`
+	Environment  Environment       `yaml:"environment"`
+	Tasks        []Task            `yaml:"tasks"`
+	Validation   []ValidationGroup `yaml:"validation,omitempty"`
+}
+
+// Environment descreve o container em que o laboratório é executado
+type Environment struct {
+	Image      string     `yaml:"image"`
+	Privileged bool       `yaml:"privileged,omitempty"`
+	Entrypoint string     `yaml:"entrypoint,omitempty"`
+	Resources  *Resources `yaml:"resources,omitempty" json:"resources,omitempty"`
+	Volumes    []Volume   `yaml:"volumes,omitempty"`
+}
+
+// Resources define os recursos reservados para o ambiente do laboratório
+type Resources struct {
+	CPU    string `yaml:"cpu,omitempty" json:"cpu,omitempty"`
+	Memory string `yaml:"memory,omitempty" json:"memory,omitempty"`
+}
+
+// Volume é um volume montado no ambiente do laboratório
+type Volume struct {
+	Name      string `yaml:"name" json:"name"`
+	MountPath string `yaml:"mountPath" json:"mountPath"`
+	Size      string `yaml:"size,omitempty" json:"size,omitempty"`
+}
+
+// ValidationGroup agrupa verificações executadas ao final do laboratório
+type ValidationGroup struct {
+	Name        string       `yaml:"name"`
+	Description string       `yaml:"description,omitempty"`
+	Checks      []Validation `yaml:"checks"`
+}
+
+// isNativeLab identifica documentos no formato nativo
+func isNativeLab(apiVersion, kind string) bool {
+	return kind == LabKind && strings.HasPrefix(apiVersion, "girus.linuxtips.io/")
+}
+
+// ToManifest converte o laboratório nativo no ConfigMap consumido pelo backend.
+// As validações finais viram tarefas adicionais e os campos sem equivalente no
+// lab.yaml são guardados em anotações.
+func (d *LabDocument) ToManifest() (*Manifest, error) {
+	meta := d.Metadata
+
+	l := &Lab{
+		Name:         meta.Name,
+		Title:        meta.Title,
+		Description:  meta.Description,
+		Duration:     d.Spec.Duration,
+		TimerEnabled: d.Spec.TimerEnabled,
+		MaxDuration:  d.Spec.MaxDuration,
+		Image:        d.Spec.Environment.Image,
+		YoutubeVideo: d.Spec.YoutubeVideo,
+		Privileged:   d.Spec.Environment.Privileged,
+		Type:         d.Spec.Type,
+		Entrypoint:   d.Spec.Environment.Entrypoint,
+		Tasks:        append([]Task(nil), d.Spec.Tasks...),
+	}
+	if l.Title == "" {
+		l.Title = meta.Name
+	}
+	if l.Duration == "" {
+		l.Duration = DefaultLabDuration
+	}
+
+	annotations := make(map[string]string)
+	for k, v := range meta.Annotations {
+		annotations[k] = v
+	}
+	setAnnotation := func(key, value string) {
+		if value != "" {
+			annotations[key] = value
+		}
+	}
+	setAnnotation(AnnotationVersion, meta.Version)
+	setAnnotation(AnnotationAuthor, meta.Author)
+	setAnnotation(AnnotationCreated, meta.Created)
+
+	if r := d.Spec.Environment.Resources; r != nil {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao converter os recursos do laboratório: %w", err)
+		}
+		annotations[AnnotationResources] = string(data)
+	}
+	if len(d.Spec.Environment.Volumes) > 0 {
+		data, err := json.Marshal(d.Spec.Environment.Volumes)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao converter os volumes do laboratório: %w", err)
+		}
+		annotations[AnnotationVolumes] = string(data)
+	}
+
+	// Cada grupo de validação final vira uma tarefa com as verificações do grupo
+	if len(d.Spec.Validation) > 0 {
+		names := make([]string, 0, len(d.Spec.Validation))
+		for _, group := range d.Spec.Validation {
+			names = append(names, group.Name)
+			l.Tasks = append(l.Tasks, Task{
+				Name:        group.Name,
+				Description: group.Description,
+				Steps:       []Step{{Text: group.Description}},
+				Validation:  group.Checks,
+			})
+		}
+		data, err := json.Marshal(names)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao converter as validações do laboratório: %w", err)
+		}
+		annotations[AnnotationValidationTasks] = string(data)
+	}
+
+	name := configMapName(meta.Name)
+	if custom, ok := annotations[AnnotationConfigMapName]; ok {
+		name = custom
+		delete(annotations, AnnotationConfigMapName)
+	}
+
+	labels := map[string]string{TemplateLabelKey: TemplateLabelValue}
+	for k, v := range meta.Labels {
+		labels[k] = v
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	data, err := marshalYAML(l)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar o lab.yaml: %w", err)
+	}
+
+	return &Manifest{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Metadata: ObjectMeta{
+			Name:        name,
+			Namespace:   TemplateNamespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Data:   map[string]string{TemplateDataKey: string(data)},
+		Lab:    l,
+		Native: d,
+	}, nil
+}
+
+// NewLabDocument converte um ConfigMap de template no formato nativo, desfazendo
+// o que ToManifest faz
+func NewLabDocument(m *Manifest) (*LabDocument, error) {
+	if !m.IsLabTemplate() {
+		return nil, fmt.Errorf("o documento '%s' não é um template de laboratório", m.Metadata.Name)
+	}
+	l := m.Lab
+
+	d := &LabDocument{
+		APIVersion: LabAPIVersion,
+		Kind:       LabKind,
+		Metadata: LabMetadata{
+			Name:        l.Name,
+			Description: l.Description,
+		},
+		Spec: LabSpec{
+			TimerEnabled: l.TimerEnabled,
+			MaxDuration:  l.MaxDuration,
+			YoutubeVideo: l.YoutubeVideo,
+			Type:         l.Type,
+			Environment: Environment{
+				Image:      l.Image,
+				Privileged: l.Privileged,
+				Entrypoint: l.Entrypoint,
+			},
+		},
+	}
+	if l.Title != l.Name {
+		d.Metadata.Title = l.Title
+	}
+	if l.Duration != DefaultLabDuration {
+		d.Spec.Duration = l.Duration
+	}
+
+	for k, v := range m.Metadata.Labels {
+		if k == TemplateLabelKey {
+			continue
+		}
+		if d.Metadata.Labels == nil {
+			d.Metadata.Labels = make(map[string]string)
+		}
+		d.Metadata.Labels[k] = v
+	}
+
+	var validationTasks []string
+	for k, v := range m.Metadata.Annotations {
+		var err error
+		switch k {
+		case AnnotationVersion:
+			d.Metadata.Version = v
+		case AnnotationAuthor:
+			d.Metadata.Author = v
+		case AnnotationCreated:
+			d.Metadata.Created = v
+		case AnnotationResources:
+			d.Spec.Environment.Resources = &Resources{}
+			err = json.Unmarshal([]byte(v), d.Spec.Environment.Resources)
+		case AnnotationVolumes:
+			err = json.Unmarshal([]byte(v), &d.Spec.Environment.Volumes)
+		case AnnotationValidationTasks:
+			err = json.Unmarshal([]byte(v), &validationTasks)
+		default:
+			if d.Metadata.Annotations == nil {
+				d.Metadata.Annotations = make(map[string]string)
+			}
+			d.Metadata.Annotations[k] = v
+		}
+		if err != nil {
+			return nil, fmt.Errorf("anotação '%s' inválida: %w", k, err)
+		}
+	}
+
+	if m.Metadata.Name != configMapName(l.Name) {
+		if d.Metadata.Annotations == nil {
+			d.Metadata.Annotations = make(map[string]string)
+		}
+		d.Metadata.Annotations[AnnotationConfigMapName] = m.Metadata.Name
+	}
+
+	isValidation := make(map[string]bool, len(validationTasks))
+	for _, name := range validationTasks {
+		isValidation[name] = true
+	}
+	for _, task := range l.Tasks {
+		if isValidation[task.Name] {
+			d.Spec.Validation = append(d.Spec.Validation, ValidationGroup{
+				Name:        task.Name,
+				Description: task.Description,
+				Checks:      task.Validation,
+			})
+			continue
+		}
+		d.Spec.Tasks = append(d.Spec.Tasks, task)
+	}
+
+	return d, nil
+}
+
+// Marshal gera o YAML do laboratório no formato nativo
+func (d *LabDocument) Marshal() ([]byte, error) {
+	return marshalYAML(d)
+}
+
+// MarshalConfigMap gera o YAML do ConfigMap de template a partir do laboratório
+func (m *Manifest) MarshalConfigMap() ([]byte, error) {
+	if m.Lab == nil {
+		return nil, fmt.Errorf("o documento '%s' não contém um laboratório", m.Metadata.Name)
+	}
+	data, err := marshalYAML(m.Lab)
+	if err != nil {
+		return nil, err
+	}
+
+	out := *m
+	out.APIVersion = "v1"
+	out.Kind = "ConfigMap"
+	out.Data = map[string]string{TemplateDataKey: string(data)}
+	return marshalYAML(&out)
+}
+
+// RenderConfigMaps gera um arquivo com os ConfigMaps de todos os templates,
+// pronto para ser aplicado no cluster
+func RenderConfigMaps(manifests []*Manifest) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, m := range FindLabTemplates(manifests) {
+		data, err := m.MarshalConfigMap()
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// HasNativeLabs informa se algum dos documentos foi escrito no formato nativo e
+// precisa ser convertido antes de ser aplicado no cluster
+func HasNativeLabs(manifests []*Manifest) bool {
+	for _, m := range manifests {
+		if m.Native != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// configMapName segue a convenção dos templates embutidos: <nome>-lab
+func configMapName(labName string) string {
+	if strings.HasSuffix(labName, "-lab") {
+		return labName
+	}
+	return labName + "-lab"
+}
+
+// marshalYAML gera YAML com indentação de dois espaços, como nos manifestos do repositório
+func marshalYAML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,112 @@
+package lab_test
+
+import (
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/lab"
+	"github.com/badtuxx/girus-cli/internal/templates"
+)
+
+// TestConvertRoundTripConfigMap converte cada template embutido para o formato
+// nativo e de volta, verificando que nada se perde
+func TestConvertRoundTripConfigMap(t *testing.T) {
+	files, err := fs.Glob(templates.ManifestFS, "manifests*/lab_*.yaml")
+	if err != nil {
+		t.Fatalf("erro ao listar manifestos: %v", err)
+	}
+
+	for _, file := range files {
+		data, err := fs.ReadFile(templates.ManifestFS, file)
+		if err != nil {
+			t.Fatalf("erro ao ler %s: %v", file, err)
+		}
+		manifests, err := lab.Parse(data, file)
+		if err != nil {
+			t.Fatalf("erro ao interpretar %s: %v", file, err)
+		}
+		original := lab.FindLabTemplates(manifests)[0]
+
+		doc, err := lab.NewLabDocument(original)
+		if err != nil {
+			t.Fatalf("%s: erro ao converter para kind Lab: %v", file, err)
+		}
+		native, err := doc.Marshal()
+		if err != nil {
+			t.Fatalf("%s: erro ao gerar YAML: %v", file, err)
+		}
+
+		back, err := lab.Parse(native, file)
+		if err != nil {
+			t.Fatalf("%s: YAML nativo gerado inválido: %v\n%s", file, err, native)
+		}
+		converted := lab.FindLabTemplates(back)[0]
+
+		if converted.Metadata.Name != original.Metadata.Name {
+			t.Errorf("%s: nome do ConfigMap esperado %s, obtido %s", file, original.Metadata.Name, converted.Metadata.Name)
+		}
+		if !reflect.DeepEqual(stripLines(converted.Lab), stripLines(original.Lab)) {
+			t.Errorf("%s: laboratório diferente após a conversão", file)
+		}
+	}
+}
+
+// TestConvertRoundTripNative converte os exemplos no formato nativo para
+// ConfigMap e de volta
+func TestConvertRoundTripNative(t *testing.T) {
+	for _, file := range []string{"../../labs/exemplo-lab/lab.yaml", "../repo/example/linux-basics/lab.yaml"} {
+		manifests, err := lab.ParseFile(file)
+		if err != nil {
+			t.Fatalf("erro ao interpretar %s: %v", file, err)
+		}
+		if !lab.HasNativeLabs(manifests) {
+			t.Fatalf("%s deveria ser reconhecido como kind Lab", file)
+		}
+		m := lab.FindLabTemplates(manifests)[0]
+
+		configMap, err := m.MarshalConfigMap()
+		if err != nil {
+			t.Fatalf("%s: erro ao gerar ConfigMap: %v", file, err)
+		}
+		back, err := lab.Parse(configMap, filepath.Base(file))
+		if err != nil {
+			t.Fatalf("%s: ConfigMap gerado inválido: %v\n%s", file, err, configMap)
+		}
+		templates := lab.FindLabTemplates(back)
+		if len(templates) != 1 || templates[0].Metadata.Labels["app"] != "girus-lab-template" {
+			t.Fatalf("%s: ConfigMap gerado sem template de laboratório:\n%s", file, configMap)
+		}
+
+		doc, err := lab.NewLabDocument(templates[0])
+		if err != nil {
+			t.Fatalf("%s: erro ao converter de volta: %v", file, err)
+		}
+		got, _ := doc.Marshal()
+		want, _ := m.Native.Marshal()
+		if string(got) != string(want) {
+			t.Errorf("%s: conversão de ida e volta diferente:\n--- esperado\n%s\n--- obtido\n%s", file, want, got)
+		}
+	}
+}
+
+// stripLines zera as linhas de origem, que mudam com o formato do arquivo
+func stripLines(l *lab.Lab) *lab.Lab {
+	c := *l
+	c.Line = 0
+	c.Tasks = append([]lab.Task(nil), l.Tasks...)
+	for i := range c.Tasks {
+		task := &c.Tasks[i]
+		task.Line = 0
+		task.Tips = append([]lab.Tip(nil), task.Tips...)
+		for j := range task.Tips {
+			task.Tips[j].Line = 0
+		}
+		task.Validation = append([]lab.Validation(nil), task.Validation...)
+		for j := range task.Validation {
+			task.Validation[j].Line = 0
+		}
+	}
+	return &c
+}
@@ -0,0 +1,285 @@
+package lab
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseError descreve um erro encontrado ao interpretar um manifesto de laboratório
+type ParseError struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	switch {
+	case e.File != "" && e.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+	case e.File != "":
+		return fmt.Sprintf("%s: %s", e.File, e.Msg)
+	case e.Line > 0:
+		return fmt.Sprintf("linha %d: %s", e.Line, e.Msg)
+	default:
+		return e.Msg
+	}
+}
+
+// ParseErrors agrupa vários erros de parsing de um mesmo arquivo
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	var buf bytes.Buffer
+	for i, err := range e {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(err.Error())
+	}
+	return buf.String()
+}
+
+// yamlLineRe extrai o número da linha das mensagens de erro do yaml.v3
+var yamlLineRe = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// ParseFile lê e interpreta um arquivo de manifesto
+func ParseFile(path string) ([]*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o arquivo '%s': %w", path, err)
+	}
+	return Parse(data, path)
+}
+
+// Parse interpreta todos os documentos YAML de um manifesto. O nome do arquivo é
+// usado apenas nas mensagens de erro.
+func Parse(data []byte, file string) ([]*Manifest, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+
+	var manifests []*Manifest
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, yamlError(err, file, 0)
+		}
+
+		// Documentos vazios (ex.: "---" no final do arquivo) são ignorados
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+			if len(doc.Content) > 0 && doc.Content[0].Tag != "!!null" {
+				return nil, &ParseError{File: file, Line: doc.Content[0].Line, Msg: "o documento não é um objeto YAML"}
+			}
+			continue
+		}
+		root := doc.Content[0]
+
+		m := &Manifest{Line: root.Line}
+		if err := root.Decode(m); err != nil {
+			return nil, yamlError(err, file, 0)
+		}
+
+		if isNativeLab(m.APIVersion, m.Kind) {
+			native, err := parseNativeLab(root, file)
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, native)
+			continue
+		}
+
+		if m.Kind == "ConfigMap" {
+			if node := lookup(root, "data", TemplateDataKey); node != nil {
+				l, err := parseLabNode(node, file)
+				if err != nil {
+					return nil, err
+				}
+				m.Lab = l
+				m.LabLine = l.Line
+			}
+		}
+
+		manifests = append(manifests, m)
+	}
+
+	return manifests, nil
+}
+
+// ParseLab interpreta um lab.yaml isolado (sem o ConfigMap em volta)
+func ParseLab(data []byte, file string) (*Lab, error) {
+	return parseLab(data, file, 0)
+}
+
+// FindLabTemplates retorna apenas os documentos que são templates de laboratório
+func FindLabTemplates(manifests []*Manifest) []*Manifest {
+	var labs []*Manifest
+	for _, m := range manifests {
+		if m.IsLabTemplate() {
+			labs = append(labs, m)
+		}
+	}
+	return labs
+}
+
+// parseLabNode decodifica o valor da chave lab.yaml, ajustando as linhas dos erros
+// para que apontem para o arquivo de origem e não para o bloco embutido
+func parseLabNode(node *yaml.Node, file string) (*Lab, error) {
+	if node.Kind != yaml.ScalarNode {
+		return nil, &ParseError{File: file, Line: node.Line, Msg: "o valor de 'lab.yaml' deve ser um texto YAML"}
+	}
+
+	// Em blocos literais (|) o conteúdo começa na linha seguinte ao indicador
+	offset := node.Line - 1
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		offset = node.Line
+	}
+
+	return parseLab([]byte(node.Value), file, offset)
+}
+
+func parseLab(data []byte, file string, offset int) (*Lab, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, yamlError(err, file, offset)
+	}
+	if len(doc.Content) == 0 {
+		return nil, &ParseError{File: file, Line: offset + 1, Msg: "lab.yaml está vazio"}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, &ParseError{File: file, Line: offset + root.Line, Msg: "lab.yaml deve ser um objeto YAML"}
+	}
+
+	var l Lab
+	if err := root.Decode(&l); err != nil {
+		return nil, yamlError(err, file, offset)
+	}
+
+	// Registrar as linhas de cada elemento para mensagens de erro posteriores
+	l.Line = offset + root.Line
+	setTaskLines(l.Tasks, lookup(root, "tasks"), offset)
+
+	return &l, nil
+}
+
+// parseNativeLab decodifica um laboratório no formato nativo e o converte no
+// ConfigMap equivalente
+func parseNativeLab(root *yaml.Node, file string) (*Manifest, error) {
+	var d LabDocument
+	if err := root.Decode(&d); err != nil {
+		return nil, yamlError(err, file, 0)
+	}
+
+	m, err := d.ToManifest()
+	if err != nil {
+		return nil, &ParseError{File: file, Line: root.Line, Msg: err.Error()}
+	}
+
+	m.Line = root.Line
+	m.Lab.Line = root.Line
+	if spec := lookup(root, "spec"); spec != nil {
+		m.LabLine = spec.Line
+	}
+	setTaskLines(m.Lab.Tasks, lookup(root, "spec", "tasks"), 0)
+
+	// As validações finais viram tarefas após as tarefas do laboratório
+	if groups := lookup(root, "spec", "validation"); groups != nil && groups.Kind == yaml.SequenceNode {
+		tasks := m.Lab.Tasks[len(d.Spec.Tasks):]
+		for i, groupNode := range groups.Content {
+			if i >= len(tasks) {
+				break
+			}
+			tasks[i].Line = groupNode.Line
+			if checks := lookup(groupNode, "checks"); checks != nil && checks.Kind == yaml.SequenceNode {
+				for j, checkNode := range checks.Content {
+					if j < len(tasks[i].Validation) {
+						tasks[i].Validation[j].Line = checkNode.Line
+					}
+				}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// setTaskLines registra a linha de cada tarefa, dica e validação
+func setTaskLines(tasks []Task, node *yaml.Node, offset int) {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, taskNode := range node.Content {
+		if i >= len(tasks) {
+			break
+		}
+		tasks[i].Line = offset + taskNode.Line
+		if tips := lookup(taskNode, "tips"); tips != nil && tips.Kind == yaml.SequenceNode {
+			for j, tipNode := range tips.Content {
+				if j < len(tasks[i].Tips) {
+					tasks[i].Tips[j].Line = offset + tipNode.Line
+				}
+			}
+		}
+		if validations := lookup(taskNode, "validation"); validations != nil && validations.Kind == yaml.SequenceNode {
+			for j, valNode := range validations.Content {
+				if j < len(tasks[i].Validation) {
+					tasks[i].Validation[j].Line = offset + valNode.Line
+				}
+			}
+		}
+	}
+}
+
+// lookup percorre mapas YAML aninhados e retorna o nó do valor da última chave
+func lookup(node *yaml.Node, keys ...string) *yaml.Node {
+	current := node
+	for _, key := range keys {
+		if current == nil || current.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(current.Content); i += 2 {
+			if current.Content[i].Value == key {
+				next = current.Content[i+1]
+				break
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// yamlError converte os erros do yaml.v3 em ParseError, somando o deslocamento de
+// linhas quando o YAML estava embutido em outro documento
+func yamlError(err error, file string, offset int) error {
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		errs := make(ParseErrors, 0, len(typeErr.Errors))
+		for _, msg := range typeErr.Errors {
+			errs = append(errs, lineError(msg, file, offset))
+		}
+		if len(errs) == 1 {
+			return errs[0]
+		}
+		return errs
+	}
+	return lineError(err.Error(), file, offset)
+}
+
+func lineError(msg, file string, offset int) *ParseError {
+	if match := yamlLineRe.FindStringSubmatch(msg); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return &ParseError{File: file, Line: offset + line, Msg: match[2]}
+	}
+	return &ParseError{File: file, Msg: msg}
+}
@@ -0,0 +1,126 @@
+package lab_test
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/lab"
+	"github.com/badtuxx/girus-cli/internal/templates"
+)
+
+func TestParseEmbeddedManifests(t *testing.T) {
+	for _, dir := range []string{"manifests", "manifests_es"} {
+		entries, err := fs.ReadDir(templates.ManifestFS, dir)
+		if err != nil {
+			t.Fatalf("erro ao ler diretório %s: %v", dir, err)
+		}
+
+		for _, entry := range entries {
+			name := path.Join(dir, entry.Name())
+			t.Run(name, func(t *testing.T) {
+				data, err := fs.ReadFile(templates.ManifestFS, name)
+				if err != nil {
+					t.Fatalf("erro ao ler %s: %v", name, err)
+				}
+
+				manifests, err := lab.Parse(data, name)
+				if err != nil {
+					t.Fatalf("erro ao interpretar %s: %v", name, err)
+				}
+
+				if !strings.HasPrefix(entry.Name(), "lab_") {
+					return
+				}
+
+				labs := lab.FindLabTemplates(manifests)
+				if len(labs) != 1 {
+					t.Fatalf("esperado 1 template de laboratório, obtidos %d", len(labs))
+				}
+				l := labs[0].Lab
+				if l.Name == "" || l.Title == "" {
+					t.Errorf("name/title vazios: %q/%q", l.Name, l.Title)
+				}
+				if len(l.Tasks) == 0 {
+					t.Errorf("nenhuma tarefa encontrada")
+				}
+				if l.Line != labs[0].LabLine || l.Line <= labs[0].Line {
+					t.Errorf("linha do lab.yaml inconsistente: %d", l.Line)
+				}
+			})
+		}
+	}
+}
+
+func TestParseKeyOrderAndMultiDocument(t *testing.T) {
+	data := []byte(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: girus
+---
+kind: ConfigMap
+apiVersion: v1
+data:
+  lab.yaml: |
+    title: "Título antes do nome"
+    tasks:
+      - name: "Tarefa"
+        steps:
+          - "` + "`ls`" + `"
+    name: meu-lab
+metadata:
+  name: meu-lab
+  namespace: girus
+  labels:
+    app: girus-lab-template
+`)
+
+	manifests, err := lab.Parse(data, "multi.yaml")
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("esperados 2 documentos, obtidos %d", len(manifests))
+	}
+
+	labs := lab.FindLabTemplates(manifests)
+	if len(labs) != 1 {
+		t.Fatalf("esperado 1 template, obtidos %d", len(labs))
+	}
+	if labs[0].Lab.Name != "meu-lab" || labs[0].Lab.Title != "Título antes do nome" {
+		t.Errorf("name/title incorretos: %q/%q", labs[0].Lab.Name, labs[0].Lab.Title)
+	}
+	if got := labs[0].Lab.Tasks[0].Line; got != 12 {
+		t.Errorf("linha da tarefa esperada 12, obtida %d", got)
+	}
+}
+
+func TestParseErrorLineNumbers(t *testing.T) {
+	data := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: quebrado
+data:
+  lab.yaml: |
+    name: quebrado
+    privileged: talvez
+`)
+
+	_, err := lab.Parse(data, "quebrado.yaml")
+	if err == nil {
+		t.Fatal("esperado erro de parsing")
+	}
+
+	var parseErr *lab.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("esperado *lab.ParseError, obtido %T: %v", err, err)
+	}
+	if parseErr.Line != 8 {
+		t.Errorf("linha esperada 8, obtida %d (%v)", parseErr.Line, err)
+	}
+	if !strings.HasPrefix(err.Error(), "quebrado.yaml:8:") {
+		t.Errorf("mensagem sem posição: %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package lab
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Categorias de laboratório suportadas pelo gerador, com o ambiente padrão de cada uma
+var Categories = map[string]Category{
+	"linux":      {Image: "linuxtips/girus-devops:0.1"},
+	"docker":     {Image: "linuxtips/girus-devops:0.1", Privileged: true},
+	"kubernetes": {Image: "linuxtips/girus-devops:0.1", Privileged: true},
+	"terraform":  {Image: "linuxtips/girus-devops:0.1", Privileged: true},
+	"aws":        {Image: "linuxtips/girus-localstack:0.1", Privileged: true, Type: "aws", Entrypoint: "/entrypoint.sh"},
+}
+
+// Category descreve o ambiente padrão de uma categoria de laboratório
+type Category struct {
+	Image      string
+	Privileged bool
+	Type       string
+	Entrypoint string
+}
+
+// ScaffoldOptions define o laboratório a ser gerado por Scaffold
+type ScaffoldOptions struct {
+	ID          string
+	Category    string
+	Lang        string
+	Title       string
+	Description string
+	Duration    string
+}
+
+// ScaffoldFile é um arquivo gerado por Scaffold
+type ScaffoldFile struct {
+	// Name é o nome do arquivo dentro do diretório do laboratório (lab.yaml, lab_es.yaml)
+	Name    string
+	Content []byte
+	// Lab é o laboratório gerado, já interpretado
+	Lab *Lab
+}
+
+var labIDRe = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// LabName retorna o nome do laboratório: <categoria>-<id>, com o sufixo -es para espanhol
+func (o ScaffoldOptions) LabName() string {
+	name := o.ID
+	if !strings.HasPrefix(name, o.Category+"-") {
+		name = o.Category + "-" + name
+	}
+	if o.Lang == "es" {
+		name += "-es"
+	}
+	return name
+}
+
+// DirName retorna o diretório do laboratório, seguindo a convenção <categoria>_<id>
+func (o ScaffoldOptions) DirName() string {
+	return o.Category + "_" + strings.TrimPrefix(o.ID, o.Category+"-")
+}
+
+// Scaffold gera o ConfigMap de um novo laboratório com uma tarefa de exemplo,
+// pronto para ser editado
+func Scaffold(opts ScaffoldOptions) (*ScaffoldFile, error) {
+	if !labIDRe.MatchString(opts.ID) {
+		return nil, fmt.Errorf("id '%s' inválido: use letras minúsculas, números e hífens", opts.ID)
+	}
+	category, ok := Categories[opts.Category]
+	if !ok {
+		return nil, fmt.Errorf("categoria '%s' desconhecida (use %s)", opts.Category, strings.Join(CategoryNames(), ", "))
+	}
+
+	text, ok := scaffoldText[opts.Lang]
+	if !ok {
+		return nil, fmt.Errorf("idioma '%s' não suportado (use pt ou es)", opts.Lang)
+	}
+	if opts.Title == "" {
+		opts.Title = text["title"]
+	}
+	if opts.Description == "" {
+		opts.Description = text["description"]
+	}
+	if opts.Duration == "" {
+		opts.Duration = DefaultLabDuration
+	}
+
+	cmName := configMapName(strings.TrimSuffix(opts.LabName(), "-es"))
+	file := "lab.yaml"
+	if opts.Lang == "es" {
+		cmName += "-es"
+		file = "lab_es.yaml"
+	}
+
+	var buf bytes.Buffer
+	err := scaffoldTemplate.Execute(&buf, map[string]interface{}{
+		"ConfigMap": cmName,
+		"Name":      opts.LabName(),
+		"Opts":      opts,
+		"Category":  category,
+		"Text":      text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar o laboratório: %w", err)
+	}
+
+	manifests, err := Parse(buf.Bytes(), file)
+	if err != nil {
+		return nil, fmt.Errorf("laboratório gerado inválido: %w", err)
+	}
+
+	return &ScaffoldFile{Name: file, Content: buf.Bytes(), Lab: manifests[0].Lab}, nil
+}
+
+// CategoryNames retorna as categorias suportadas em ordem alfabética
+func CategoryNames() []string {
+	names := make([]string, 0, len(Categories))
+	for name := range Categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Textos da tarefa de exemplo em cada idioma
+var scaffoldText = map[string]map[string]string{
+	"pt": {
+		"title":       "Título do laboratório",
+		"description": "Descreva o que será aprendido neste laboratório.",
+		"taskName":    "Primeira tarefa",
+		"taskDesc":    "Descreva o objetivo desta tarefa.",
+		"stepTitle":   "**Introdução**",
+		"stepText":    "Explique o conceito e mostre o comando que o aluno deve executar:",
+		"tipTitle":    "Dica",
+		"tipContent":  "Adicione aqui uma dica útil para o aluno.",
+		"errorMsg":    "O arquivo girus.txt não foi encontrado. Execute o comando da tarefa.",
+	},
+	"es": {
+		"title":       "Título del laboratorio",
+		"description": "Describa lo que se aprenderá en este laboratorio.",
+		"taskName":    "Primera tarea",
+		"taskDesc":    "Describa el objetivo de esta tarea.",
+		"stepTitle":   "**Introducción**",
+		"stepText":    "Explique el concepto y muestre el comando que el alumno debe ejecutar:",
+		"tipTitle":    "Consejo",
+		"tipContent":  "Agregue aquí un consejo útil para el alumno.",
+		"errorMsg":    "No se encontró el archivo girus.txt. Ejecute el comando de la tarea.",
+	},
+}
+
+var scaffoldTemplate = template.Must(template.New("lab").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+}).Parse(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{.ConfigMap}}
+  namespace: girus
+  labels:
+    app: girus-lab-template
+data:
+  lab.yaml: |
+    name: {{.Name}}
+    title: {{quote .Opts.Title}}
+    description: {{quote .Opts.Description}}
+    duration: {{.Opts.Duration}}
+{{- if eq .Category.Type "aws"}}
+    timerEnabled: true
+    maxDuration: {{.Opts.Duration}}
+{{- end}}
+    image: {{quote .Category.Image}}
+{{- if .Category.Privileged}}
+    privileged: true
+{{- end}}
+{{- if .Category.Type}}
+    type: {{quote .Category.Type}}
+{{- end}}
+{{- if .Category.Entrypoint}}
+    entrypoint: {{quote .Category.Entrypoint}}
+{{- end}}
+    tasks:
+      - name: {{quote .Text.taskName}}
+        description: {{quote .Text.taskDesc}}
+        steps:
+          - {{quote .Text.stepTitle}}
+          - {{quote .Text.stepText}}
+          - "` + "`echo 'GIRUS' > girus.txt`" + `"
+        tips:
+          - type: "info"
+            title: {{quote .Text.tipTitle}}
+            content: {{quote .Text.tipContent}}
+        validation:
+          - command: "test -f girus.txt && echo 'success' || echo 'error'"
+            expectedOutput: "success"
+            errorMessage: {{quote .Text.errorMsg}}
+`))
@@ -0,0 +1,41 @@
+package lab_test
+
+import (
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/lab"
+)
+
+func TestScaffold(t *testing.T) {
+	for _, category := range lab.CategoryNames() {
+		for _, lang := range []string{"pt", "es"} {
+			opts := lab.ScaffoldOptions{ID: "meu-lab", Category: category, Lang: lang, Title: "Meu \"lab\""}
+			file, err := lab.Scaffold(opts)
+			if err != nil {
+				t.Fatalf("%s/%s: erro ao gerar laboratório: %v", category, lang, err)
+			}
+
+			_, findings := lab.LintData(file.Content, file.Name)
+			for _, f := range findings {
+				t.Errorf("%s/%s: %s:%d: [%s] %s", category, lang, f.File, f.Line, f.Rule, f.Message)
+			}
+
+			wantName := category + "-meu-lab"
+			wantFile := "lab.yaml"
+			if lang == "es" {
+				wantName += "-es"
+				wantFile = "lab_es.yaml"
+			}
+			if file.Lab.Name != wantName || file.Name != wantFile || file.Lab.Title != opts.Title {
+				t.Errorf("%s/%s: laboratório inesperado: arquivo %s, nome %s, título %s", category, lang, file.Name, file.Lab.Name, file.Lab.Title)
+			}
+		}
+	}
+
+	if _, err := lab.Scaffold(lab.ScaffoldOptions{ID: "Meu Lab", Category: "linux", Lang: "pt"}); err == nil {
+		t.Error("id inválido deveria gerar erro")
+	}
+	if _, err := lab.Scaffold(lab.ScaffoldOptions{ID: "meu-lab", Category: "windows", Lang: "pt"}); err == nil {
+		t.Error("categoria desconhecida deveria gerar erro")
+	}
+}
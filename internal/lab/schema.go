@@ -0,0 +1,133 @@
+package lab
+
+import "gopkg.in/yaml.v3"
+
+// Valores esperados no ConfigMap que embrulha um template de laboratório
+const (
+	TemplateNamespace  = "girus"
+	TemplateLabelKey   = "app"
+	TemplateLabelValue = "girus-lab-template"
+	TemplateDataKey    = "lab.yaml"
+)
+
+// Manifest representa um documento de um arquivo de manifesto. Quando o documento
+// é um ConfigMap de template de laboratório, Lab contém o lab.yaml decodificado.
+// Laboratórios no formato nativo (kind Lab) são convertidos para o ConfigMap
+// equivalente e o documento original fica em Native.
+type Manifest struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   ObjectMeta        `yaml:"metadata"`
+	Data       map[string]string `yaml:"data,omitempty"`
+
+	// Lab é o conteúdo decodificado da chave lab.yaml (nil se ausente)
+	Lab *Lab `yaml:"-"`
+	// Native é o documento original quando escrito no formato nativo (nil se ConfigMap)
+	Native *LabDocument `yaml:"-"`
+	// Line é a linha em que o documento começa no arquivo de origem
+	Line int `yaml:"-"`
+	// LabLine é a linha em que o conteúdo de lab.yaml começa no arquivo de origem
+	LabLine int `yaml:"-"`
+}
+
+// ObjectMeta contém os metadados Kubernetes usados pelos manifestos do GIRUS
+type ObjectMeta struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// IsLabTemplate informa se o documento é um ConfigMap com um lab.yaml embutido
+func (m *Manifest) IsLabTemplate() bool {
+	return m.Kind == "ConfigMap" && m.Lab != nil
+}
+
+// Lab representa o conteúdo do lab.yaml consumido pelo backend do GIRUS
+type Lab struct {
+	Name         string `yaml:"name"`
+	Title        string `yaml:"title"`
+	Description  string `yaml:"description"`
+	Duration     string `yaml:"duration"`
+	TimerEnabled bool   `yaml:"timerEnabled,omitempty"`
+	MaxDuration  string `yaml:"maxDuration,omitempty"`
+	Image        string `yaml:"image"`
+	YoutubeVideo string `yaml:"youtubeVideo,omitempty"`
+	Privileged   bool   `yaml:"privileged,omitempty"`
+	Type         string `yaml:"type,omitempty"`
+	Entrypoint   string `yaml:"entrypoint,omitempty"`
+	Tasks        []Task `yaml:"tasks"`
+
+	// Line é a linha do lab.yaml no arquivo de origem
+	Line int `yaml:"-"`
+}
+
+// Task representa uma tarefa do laboratório
+type Task struct {
+	Name        string       `yaml:"name"`
+	Description string       `yaml:"description"`
+	Steps       []Step       `yaml:"steps"`
+	Tips        []Tip        `yaml:"tips,omitempty"`
+	Validation  []Validation `yaml:"validation,omitempty"`
+
+	Line int `yaml:"-"`
+}
+
+// Step representa um passo de uma tarefa. A maioria dos laboratórios usa texto em
+// markdown, mas alguns usam passos estruturados com comando, saída esperada e dica.
+type Step struct {
+	Text           string `yaml:"-"`
+	Description    string `yaml:"description,omitempty"`
+	Command        string `yaml:"command,omitempty"`
+	ExpectedOutput string `yaml:"expectedOutput,omitempty"`
+	Hint           string `yaml:"hint,omitempty"`
+}
+
+// UnmarshalYAML aceita tanto passos em texto quanto passos estruturados
+func (s *Step) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = Step{Text: node.Value}
+		return nil
+	}
+
+	type plain Step
+	var p plain
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	*s = Step(p)
+	return nil
+}
+
+// MarshalYAML preserva o formato original do passo
+func (s Step) MarshalYAML() (interface{}, error) {
+	if s.IsText() {
+		return s.Text, nil
+	}
+	type plain Step
+	return plain(s), nil
+}
+
+// IsText informa se o passo é apenas texto
+func (s Step) IsText() bool {
+	return s.Description == "" && s.Command == "" && s.ExpectedOutput == "" && s.Hint == ""
+}
+
+// Tip representa uma dica exibida ao lado de uma tarefa
+type Tip struct {
+	Type    string `yaml:"type"`
+	Title   string `yaml:"title"`
+	Content string `yaml:"content"`
+
+	Line int `yaml:"-"`
+}
+
+// Validation representa uma verificação executada no ambiente do laboratório
+type Validation struct {
+	Command            string `yaml:"command"`
+	ExpectedOutput     string `yaml:"expectedOutput,omitempty"`
+	ExpectedExpression string `yaml:"expectedExpression,omitempty"`
+	ErrorMessage       string `yaml:"errorMessage"`
+
+	Line int `yaml:"-"`
+}
@@ -0,0 +1,293 @@
+package repo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/common"
+)
+
+// indexCacheTTL é o tempo em que um índice remoto em cache é usado sem
+// consultar o repositório
+const indexCacheTTL = time.Hour
+
+// Client resolve laboratórios nos repositórios configurados, lendo fontes
+// file:// e HTTP(S) da mesma forma e guardando índices e laboratórios
+// baixados em um único cache em ~/.girus/cache
+type Client struct {
+	repos     []Repository
+	cachePath string
+}
+
+// ResolvedLab é um laboratório encontrado no índice de um repositório
+type ResolvedLab struct {
+	Repository Repository
+	LabEntry
+}
+
+// NewClient cria o cliente dos repositórios configurados em
+// ~/.girus/repositories.json. Se url for informada (ou, na falta dela, a
+// variável GIRUS_REPO_URL), apenas o repositório dessa URL é consultado, com o
+// nome URLRepositoryName.
+func NewClient(url string) (*Client, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter diretório home: %v", err)
+	}
+	c := &Client{cachePath: filepath.Join(homeDir, ".girus", "cache")}
+
+	if url == "" {
+		url = os.Getenv("GIRUS_REPO_URL")
+	}
+	if url != "" {
+		c.repos = []Repository{{Name: URLRepositoryName, URL: url}}
+		return c, nil
+	}
+
+	rm, err := NewRepositoryManager()
+	if err != nil {
+		return nil, err
+	}
+	c.repos = rm.ListRepositories()
+	sort.Slice(c.repos, func(i, j int) bool { return c.repos[i].Name < c.repos[j].Name })
+	return c, nil
+}
+
+// Repositories retorna os repositórios consultados pelo cliente, ordenados
+// pelo nome
+func (c *Client) Repositories() []Repository {
+	return c.repos
+}
+
+// Repository retorna o repositório com o nome informado
+func (c *Client) Repository(name string) (Repository, error) {
+	for _, r := range c.repos {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+	return Repository{}, fmt.Errorf("%w: '%s'", ErrRepositoryNotFound, name)
+}
+
+// Index retorna o índice do repositório. Índices remotos ficam em cache por
+// indexCacheTTL e, se o repositório não responder, o índice em cache é usado
+// mesmo expirado. Repositórios file:// são sempre lidos do disco. Em
+// repositórios com chaves confiáveis a assinatura é verificada também ao ler
+// o cache, e uma assinatura inválida nunca recorre ao índice em cache.
+func (c *Client) Index(ctx context.Context, r Repository) (*Index, error) {
+	if strings.HasPrefix(r.URL, "file://") {
+		index, _, _, err := fetchVerifiedIndex(ctx, r)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao obter índice do repositório %s: %w", r.Name, err)
+		}
+		return index, nil
+	}
+
+	cacheFile := filepath.Join(c.repositoryCache(r), "index.yaml")
+	info, statErr := os.Stat(cacheFile)
+	if statErr == nil && time.Since(info.ModTime()) < indexCacheTTL {
+		if index, err := c.cachedIndex(cacheFile, r); err == nil {
+			return index, nil
+		}
+	}
+
+	index, data, signature, err := fetchVerifiedIndex(ctx, r)
+	if err != nil {
+		if statErr == nil && !errors.Is(err, ErrVerification) {
+			if cached, cacheErr := c.cachedIndex(cacheFile, r); cacheErr == nil {
+				return cached, nil
+			}
+		}
+		return nil, fmt.Errorf("erro ao obter índice do repositório %s: %w", r.Name, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório de cache: %v", err)
+	}
+	if err := os.WriteFile(cacheFile, data, 0644); err != nil {
+		return nil, fmt.Errorf("erro ao salvar índice em cache: %v", err)
+	}
+	if signature == nil {
+		os.Remove(cacheFile + SignatureSuffix)
+	} else if err := os.WriteFile(cacheFile+SignatureSuffix, signature, 0644); err != nil {
+		return nil, fmt.Errorf("erro ao salvar assinatura em cache: %v", err)
+	}
+	return index, nil
+}
+
+// ListLabs lista os laboratórios do idioma atual em todos os repositórios,
+// agrupados pelo nome do repositório. Quando o índice traz várias versões de
+// um laboratório, apenas a versão padrão (a estável mais alta) é listada.
+// Repositórios cujo índice não pôde ser obtido ficam fora do resultado e seus
+// erros são retornados juntos, com os laboratórios dos demais.
+func (c *Client) ListLabs(ctx context.Context) (map[string][]LabEntry, error) {
+	lang := common.Lang()
+	allLabs := make(map[string][]LabEntry)
+	var failures []error
+	for _, r := range c.repos {
+		index, err := c.Index(ctx, r)
+		if err != nil {
+			failures = append(failures, err)
+			continue
+		}
+
+		var ids []string
+		versions := map[string][]ResolvedLab{}
+		for _, entry := range index.Labs {
+			if !matchesLanguage(entry, lang) {
+				continue
+			}
+			if _, ok := versions[entry.ID]; !ok {
+				ids = append(ids, entry.ID)
+			}
+			versions[entry.ID] = append(versions[entry.ID], ResolvedLab{Repository: r, LabEntry: entry})
+		}
+
+		labs := []LabEntry{}
+		for _, id := range ids {
+			if lab, _ := selectVersion(versions[id], ""); lab != nil {
+				labs = append(labs, lab.LabEntry)
+			}
+		}
+		allLabs[r.Name] = labs
+	}
+	return allLabs, errors.Join(failures...)
+}
+
+// Versions retorna todas as versões publicadas do laboratório nos
+// repositórios, da mais alta para a mais baixa
+func (c *Client) Versions(ctx context.Context, id string) ([]ResolvedLab, error) {
+	labs, failures := c.entries(ctx, c.repos, id)
+	if len(labs) == 0 {
+		return nil, errors.Join(append([]error{fmt.Errorf("%w: ID '%s' não existe nos repositórios configurados", ErrLabNotFound, id)}, failures...)...)
+	}
+	sortVersions(labs)
+	return labs, nil
+}
+
+// FindLab procura o laboratório pelo ID no repositório repoName ou, se ele for
+// vazio, em todos os repositórios. constraint é uma restrição semântica de
+// versão (por exemplo "1.2.0", "^1.2" ou ">=1.0, <2.0"); sem ela é escolhida a
+// versão estável mais alta. Versões iguais em repositórios diferentes são
+// resolvidas pela ordem dos nomes dos repositórios.
+func (c *Client) FindLab(ctx context.Context, repoName, id, constraint string) (*ResolvedLab, error) {
+	repos := c.repos
+	if repoName != "" {
+		r, err := c.Repository(repoName)
+		if err != nil {
+			return nil, err
+		}
+		repos = []Repository{r}
+	}
+
+	candidates, failures := c.entries(ctx, repos, id)
+	lab, err := selectVersion(candidates, constraint)
+	if err != nil {
+		return nil, err
+	}
+	if lab != nil {
+		return lab, nil
+	}
+
+	notFound := fmt.Errorf("%w: ID '%s' não existe nos repositórios configurados", ErrLabNotFound, id)
+	if repoName != "" {
+		notFound = fmt.Errorf("%w: '%s' no repositório '%s'", ErrLabNotFound, id, repoName)
+	}
+	if len(candidates) > 0 {
+		notFound = fmt.Errorf("%w: nenhuma versão de '%s' atende a '%s'", ErrLabNotFound, id, constraint)
+	}
+	return nil, errors.Join(append([]error{notFound}, failures...)...)
+}
+
+// entries retorna as entradas do laboratório nos repositórios e os erros dos
+// repositórios cujo índice não pôde ser obtido
+func (c *Client) entries(ctx context.Context, repos []Repository, id string) ([]ResolvedLab, []error) {
+	var labs []ResolvedLab
+	var failures []error
+	for _, r := range repos {
+		index, err := c.Index(ctx, r)
+		if err != nil {
+			failures = append(failures, err)
+			continue
+		}
+		for _, entry := range index.Labs {
+			if entry.ID == id {
+				labs = append(labs, ResolvedLab{Repository: r, LabEntry: entry})
+			}
+		}
+	}
+	return labs, failures
+}
+
+// Download baixa o manifesto do laboratório para o cache e retorna o caminho
+// do arquivo salvo. O conteúdo é conferido com o digest do índice, que é
+// obrigatório em repositórios com chaves confiáveis.
+func (c *Client) Download(ctx context.Context, lab *ResolvedLab) (string, error) {
+	data, err := fetch(ctx, lab.URL)
+	if err != nil {
+		return "", fmt.Errorf("erro ao baixar laboratório: %w", err)
+	}
+	if !insecureSkipVerify {
+		if lab.Digest != "" {
+			if err := verifyDigest(data, lab.Digest); err != nil {
+				return "", fmt.Errorf("laboratório %s %s: %w", lab.ID, lab.Version, err)
+			}
+		} else if len(lab.Repository.PublicKeys) > 0 {
+			return "", fmt.Errorf("%w: o laboratório %s não tem digest no índice assinado do repositório %s", ErrVerification, lab.ID, lab.Repository.Name)
+		}
+	}
+
+	version := lab.Version
+	if version == "" {
+		version = "latest"
+	}
+	labPath := filepath.Join(c.repositoryCache(lab.Repository), lab.ID, version)
+	if err := os.MkdirAll(labPath, 0755); err != nil {
+		return "", fmt.Errorf("erro ao criar diretório do laboratório: %v", err)
+	}
+	labFile := filepath.Join(labPath, "lab.yaml")
+	if err := os.WriteFile(labFile, data, 0644); err != nil {
+		return "", fmt.Errorf("erro ao salvar laboratório: %v", err)
+	}
+	return labFile, nil
+}
+
+// Digest retorna o sha256 do conteúdo no formato sha256:<hex>
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// repositoryCache retorna o diretório de cache do repositório. O hash da URL
+// no nome evita reaproveitar o cache quando o repositório muda de endereço.
+func (c *Client) repositoryCache(r Repository) string {
+	sum := sha256.Sum256([]byte(r.URL))
+	return filepath.Join(c.cachePath, r.Name+"-"+hex.EncodeToString(sum[:4]))
+}
+
+// cachedIndex lê o índice do repositório salvo no cache, verificando a
+// assinatura em cache quando o repositório tem chaves confiáveis
+func (c *Client) cachedIndex(cacheFile string, r Repository) (*Index, error) {
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return nil, err
+	}
+	if verifiesIndex(r) {
+		signature, err := os.ReadFile(cacheFile + SignatureSuffix)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifyIndexSignature(r, data, signature); err != nil {
+			return nil, err
+		}
+	}
+	return loadIndex(data, indexURL(r.URL))
+}
@@ -0,0 +1,313 @@
+package repo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/badtuxx/girus-cli/internal/repo"
+)
+
+// configureRepositories grava o repositories.json em um HOME temporário
+func configureRepositories(t *testing.T, repos ...repo.Repository) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GIRUS_REPO_URL", "")
+
+	config := map[string]repo.Repository{}
+	for _, r := range repos {
+		config[r.Name] = r
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".girus"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".girus", "repositories.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serveIndex publica o índice e os manifestos dos laboratórios, contando as
+// requisições ao índice
+func serveIndex(t *testing.T, index string, requests *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			if requests != nil {
+				atomic.AddInt32(requests, 1)
+			}
+			fmt.Fprint(w, index)
+		default:
+			fmt.Fprintf(w, "manifesto de %s\n", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClientFileRepository(t *testing.T) {
+	configureRepositories(t)
+	dir := t.TempDir()
+	index := `apiVersion: v1
+labs:
+- id: linux-basico
+  title: Linux Básico
+  version: 1.0.0
+  url: linux-basico/lab.yaml
+- id: linux-basico-es
+  title: Linux Básico
+  version: 1.0.0
+  url: linux-basico/lab_es.yaml
+`
+	if err := os.MkdirAll(filepath.Join(dir, "linux-basico"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.yaml"), []byte(index), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "linux-basico", "lab.yaml"), []byte("kind: Lab\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := repo.NewClient("file://" + dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	labs, err := client.ListLabs(context.Background())
+	if err != nil {
+		t.Fatalf("ListLabs: %v", err)
+	}
+	if got := labs[repo.URLRepositoryName]; len(got) != 1 || got[0].ID != "linux-basico" {
+		t.Fatalf("labs = %+v, esperado apenas linux-basico", labs)
+	}
+
+	lab, err := client.FindLab(context.Background(), "", "linux-basico", "")
+	if err != nil {
+		t.Fatalf("FindLab: %v", err)
+	}
+	if want := "file://" + filepath.Join(dir, "linux-basico", "lab.yaml"); lab.URL != want {
+		t.Errorf("URL = %s, esperado %s", lab.URL, want)
+	}
+	path, err := client.Download(context.Background(), lab)
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "kind: Lab\n" {
+		t.Errorf("manifesto baixado = %q, %v", data, err)
+	}
+}
+
+func TestClientFindLabAcrossRepositories(t *testing.T) {
+	first := serveIndex(t, "labs:\n- id: docker\n  version: 1.0.0\n  url: docker/lab.yaml\n", nil)
+	second := serveIndex(t, "labs:\n- id: kubernetes\n  version: 2.0.0\n  url: kubernetes/lab.yaml\n", nil)
+	configureRepositories(t,
+		repo.Repository{Name: "a", URL: first.URL},
+		repo.Repository{Name: "b", URL: second.URL + "/index.yaml"},
+	)
+
+	client, err := repo.NewClient("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lab, err := client.FindLab(context.Background(), "", "kubernetes", "")
+	if err != nil {
+		t.Fatalf("FindLab: %v", err)
+	}
+	if lab.Repository.Name != "b" || lab.URL != second.URL+"/kubernetes/lab.yaml" {
+		t.Errorf("lab = %+v", lab)
+	}
+
+	if _, err := client.FindLab(context.Background(), "a", "kubernetes", ""); !errors.Is(err, repo.ErrLabNotFound) {
+		t.Errorf("erro = %v, esperado ErrLabNotFound", err)
+	}
+	if _, err := client.FindLab(context.Background(), "", "docker", "9.9.9"); !errors.Is(err, repo.ErrLabNotFound) {
+		t.Errorf("erro = %v, esperado ErrLabNotFound", err)
+	}
+	if _, err := client.FindLab(context.Background(), "c", "docker", ""); !errors.Is(err, repo.ErrRepositoryNotFound) {
+		t.Errorf("erro = %v, esperado ErrRepositoryNotFound", err)
+	}
+}
+
+func TestClientListLabsUnreachableRepository(t *testing.T) {
+	server := serveIndex(t, "labs:\n- id: docker\n  version: 1.0.0\n  url: docker/lab.yaml\n", nil)
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+	configureRepositories(t,
+		repo.Repository{Name: "a", URL: server.URL},
+		repo.Repository{Name: "b", URL: dead.URL},
+	)
+
+	client, err := repo.NewClient("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	labs, err := client.ListLabs(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "repositório b") {
+		t.Errorf("erro = %v, esperado a falha do repositório b", err)
+	}
+	if len(labs["a"]) != 1 || labs["a"][0].ID != "docker" {
+		t.Errorf("labs = %+v, esperado os laboratórios do repositório a", labs)
+	}
+	if _, ok := labs["b"]; ok {
+		t.Errorf("labs = %+v, o repositório b não respondeu", labs)
+	}
+}
+
+func TestClientIndexCache(t *testing.T) {
+	var requests int32
+	server := serveIndex(t, "labs:\n- id: docker\n  url: docker/lab.yaml\n", &requests)
+	configureRepositories(t, repo.Repository{Name: "teste", URL: server.URL})
+
+	client, err := repo.NewClient("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ := client.Repository("teste")
+	for i := 0; i < 2; i++ {
+		if _, err := client.Index(context.Background(), r); err != nil {
+			t.Fatalf("Index: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("requisições = %d, esperado 1 com o índice em cache", requests)
+	}
+
+	// Com o cache expirado e o repositório fora do ar, o índice em cache é usado
+	cached, _ := filepath.Glob(filepath.Join(os.Getenv("HOME"), ".girus", "cache", "teste-*", "index.yaml"))
+	if len(cached) != 1 {
+		t.Fatalf("índice em cache não encontrado: %v", cached)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(cached[0], old, old); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+	index, err := client.Index(context.Background(), r)
+	if err != nil {
+		t.Fatalf("Index com o repositório fora do ar: %v", err)
+	}
+	if len(index.Labs) != 1 || index.Labs[0].URL != server.URL+"/docker/lab.yaml" {
+		t.Errorf("índice = %+v", index)
+	}
+}
+
+func TestClientEntriesIndex(t *testing.T) {
+	configureRepositories(t)
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := repo.NewClient("file://" + root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lab, err := client.FindLab(context.Background(), "", "docker_fundamentos", "")
+	if err != nil {
+		t.Fatalf("FindLab no index.yaml da raiz: %v", err)
+	}
+	if lab.Version == "" || lab.URL == "" || len(lab.Tags) == 0 {
+		t.Errorf("entrada incompleta: %+v", lab)
+	}
+}
+
+func TestClientVersionResolution(t *testing.T) {
+	first := serveIndex(t, `labs:
+- id: linux
+  version: 1.0.0
+  url: linux/1.0.0/lab.yaml
+- id: linux
+  version: 1.2.0
+  url: linux/1.2.0/lab.yaml
+- id: linux
+  version: 2.0.0
+  url: linux/2.0.0/lab.yaml
+- id: linux
+  version: 2.1.0-beta.1
+  url: linux/2.1.0-beta.1/lab.yaml
+- id: docker
+  version: 0.1.0-alpha
+  url: docker/lab.yaml
+`, nil)
+	second := serveIndex(t, "labs:\n- id: linux\n  version: 1.2.5\n  url: linux/lab.yaml\n", nil)
+	configureRepositories(t,
+		repo.Repository{Name: "a", URL: first.URL},
+		repo.Repository{Name: "b", URL: second.URL},
+	)
+	client, err := repo.NewClient("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id, constraint string
+		version, repo  string
+	}{
+		{"linux", "", "2.0.0", "a"},
+		{"linux", "^1.2", "1.2.5", "b"},
+		{"linux", "~1.2.0", "1.2.5", "b"},
+		{"linux", "1.0.0", "1.0.0", "a"},
+		{"linux", ">=2.1.0-0", "2.1.0-beta.1", "a"},
+		{"docker", "", "0.1.0-alpha", "a"},
+	}
+	for _, tt := range tests {
+		lab, err := client.FindLab(context.Background(), "", tt.id, tt.constraint)
+		if err != nil {
+			t.Errorf("FindLab(%s@%s): %v", tt.id, tt.constraint, err)
+			continue
+		}
+		if lab.Version != tt.version || lab.Repository.Name != tt.repo {
+			t.Errorf("FindLab(%s@%s) = %s de %s, esperado %s de %s", tt.id, tt.constraint, lab.Version, lab.Repository.Name, tt.version, tt.repo)
+		}
+	}
+
+	if _, err := client.FindLab(context.Background(), "", "linux", ">=3"); !errors.Is(err, repo.ErrLabNotFound) {
+		t.Errorf("erro = %v, esperado ErrLabNotFound", err)
+	}
+	if _, err := client.FindLab(context.Background(), "", "linux", "abc"); !errors.Is(err, repo.ErrInvalidConstraint) {
+		t.Errorf("erro = %v, esperado ErrInvalidConstraint", err)
+	}
+
+	versions, err := client.Versions(context.Background(), "linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range versions {
+		got = append(got, v.Version)
+	}
+	if want := "2.1.0-beta.1 2.0.0 1.2.5 1.2.0 1.0.0"; strings.Join(got, " ") != want {
+		t.Errorf("versões = %v, esperado %s", got, want)
+	}
+
+	labs, err := client.ListLabs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(labs["a"]) != 2 || labs["a"][0].Version != "2.0.0" {
+		t.Errorf("ListLabs = %+v, esperado uma entrada por laboratório com a versão padrão", labs["a"])
+	}
+}
+
+func TestParseLabRef(t *testing.T) {
+	if id, constraint := repo.ParseLabRef("linux@^1.2"); id != "linux" || constraint != "^1.2" {
+		t.Errorf("ParseLabRef = %s, %s", id, constraint)
+	}
+	if id, constraint := repo.ParseLabRef("linux"); id != "linux" || constraint != "" {
+		t.Errorf("ParseLabRef = %s, %s", id, constraint)
+	}
+}
@@ -0,0 +1,180 @@
+package repo
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/badtuxx/girus-cli/internal/lab"
+)
+
+// AnnotationTags é a anotação opcional com as tags do laboratório, separadas
+// por vírgula, publicadas no índice gerado
+const AnnotationTags = "girus.linuxtips.io/tags"
+
+// DefaultLabVersion é a versão usada quando o manifesto não informa uma
+const DefaultLabVersion = "1.0.0"
+
+// labFiles são os nomes dos arquivos de laboratório procurados na geração
+var labFiles = map[string]bool{"lab.yaml": true, "lab_es.yaml": true}
+
+// IndexOptions configura a geração do índice
+type IndexOptions struct {
+	// BaseURL é o prefixo das URLs dos laboratórios. Vazia, as URLs ficam
+	// relativas ao index.yaml.
+	BaseURL string
+	// Merge é um índice existente cujas versões que não estão no diretório são
+	// mantidas. Versões presentes nos dois são substituídas pelas geradas.
+	Merge *Index
+}
+
+// GenerateIndex gera o índice dos laboratórios encontrados em dir, lendo cada
+// lab.yaml e lab_es.yaml. A categoria do diretório (<categoria>_<id>) vira a
+// primeira tag do laboratório.
+func GenerateIndex(dir string, opts IndexOptions) (*Index, error) {
+	index := &Index{APIVersion: "v1", Generated: time.Now().UTC().Format(time.RFC3339), Labs: []LabEntry{}}
+	seen := map[string]string{}
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !labFiles[d.Name()] {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		entries, err := indexEntries(p, filepath.ToSlash(rel), opts.BaseURL)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			key := entry.ID + "@" + entry.Version
+			if other, ok := seen[key]; ok {
+				return fmt.Errorf("laboratório %s versão %s repetido em %s e %s", entry.ID, entry.Version, other, rel)
+			}
+			seen[key] = rel
+			index.Labs = append(index.Labs, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Merge != nil {
+		for _, entry := range opts.Merge.Labs {
+			if _, ok := seen[entry.ID+"@"+entry.Version]; !ok {
+				index.Labs = append(index.Labs, entry)
+			}
+		}
+	}
+
+	sortIndex(index.Labs)
+	return index, nil
+}
+
+// ReadIndexFile lê um index.yaml local sem resolver as URLs relativas, para
+// ser combinado com um índice gerado
+func ReadIndexFile(file string) (*Index, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o índice %s: %w", file, err)
+	}
+	return parseIndex(data)
+}
+
+// indexEntries retorna as entradas dos templates de laboratório do arquivo
+func indexEntries(file, rel, baseURL string) ([]LabEntry, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler %s: %w", file, err)
+	}
+	manifests, err := lab.Parse(data, file)
+	if err != nil {
+		return nil, err
+	}
+	templates := lab.FindLabTemplates(manifests)
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("%s não contém um template de laboratório", file)
+	}
+
+	labURL := rel
+	if baseURL != "" {
+		labURL = strings.TrimSuffix(baseURL, "/") + "/" + rel
+	}
+	category := ""
+	if dirName := path.Base(path.Dir(rel)); strings.Contains(dirName, "_") {
+		category, _, _ = strings.Cut(dirName, "_")
+	}
+
+	var entries []LabEntry
+	for _, m := range templates {
+		// No formato nativo metadata.version também vira a anotação de versão
+		annotations := m.Metadata.Annotations
+		version := annotations[lab.AnnotationVersion]
+		if version == "" {
+			version = DefaultLabVersion
+		}
+
+		tags := []string{}
+		for _, tag := range append([]string{category}, strings.Split(annotations[AnnotationTags], ",")...) {
+			if tag = strings.TrimSpace(tag); tag != "" && !containsTag(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+
+		entries = append(entries, LabEntry{
+			ID:          m.Lab.Name,
+			Title:       m.Lab.Title,
+			Description: m.Lab.Description,
+			Version:     version,
+			Duration:    m.Lab.Duration,
+			Tags:        tags,
+			URL:         labURL,
+			Digest:      Digest(data),
+		})
+	}
+	return entries, nil
+}
+
+// containsTag indica se a tag já está na lista
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// sortIndex ordena as entradas pelo ID e, em cada laboratório, da versão mais
+// alta para a mais baixa
+func sortIndex(labs []LabEntry) {
+	sort.SliceStable(labs, func(i, j int) bool {
+		if labs[i].ID != labs[j].ID {
+			return labs[i].ID < labs[j].ID
+		}
+		vi, erri := semver.NewVersion(labs[i].Version)
+		vj, errj := semver.NewVersion(labs[j].Version)
+		if erri != nil || errj != nil {
+			return erri == nil && errj != nil
+		}
+		return vi.GreaterThan(vj)
+	})
+}
@@ -0,0 +1,147 @@
+package repo_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/repo"
+)
+
+// labDir monta um diretório de laboratórios com um laboratório em ConfigMap
+// (com a versão em espanhol) e um no formato nativo
+func labDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	copyFile(t, "../../labs/docker_fundamentos/lab.yaml", filepath.Join(dir, "docker_fundamentos", "lab.yaml"))
+	copyFile(t, "../../labs/docker_fundamentos/lab_es.yaml", filepath.Join(dir, "docker_fundamentos", "lab_es.yaml"))
+	copyFile(t, "example/linux-basics/lab.yaml", filepath.Join(dir, "linux-basics", "lab.yaml"))
+	// Diretórios ocultos são ignorados
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".git", "lab.yaml"), []byte("não é um laboratório"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGenerateIndex(t *testing.T) {
+	dir := labDir(t)
+	index, err := repo.GenerateIndex(dir, repo.IndexOptions{BaseURL: "https://exemplo.com/labs/"})
+	if err != nil {
+		t.Fatalf("GenerateIndex: %v", err)
+	}
+	if index.APIVersion != "v1" || index.Generated == "" {
+		t.Errorf("apiVersion = %q, generated = %q", index.APIVersion, index.Generated)
+	}
+
+	var ids []string
+	for _, entry := range index.Labs {
+		ids = append(ids, entry.ID)
+	}
+	if got := strings.Join(ids, ","); got != "docker-fundamentos,docker-fundamentos-es,linux-basics" {
+		t.Fatalf("IDs = %s", got)
+	}
+
+	docker := index.Labs[0]
+	data, err := os.ReadFile(filepath.Join(dir, "docker_fundamentos", "lab.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if docker.Title != "Introdução ao Docker" || docker.Duration != "25m" || docker.Version != repo.DefaultLabVersion {
+		t.Errorf("entrada docker-fundamentos = %+v", docker)
+	}
+	if docker.URL != "https://exemplo.com/labs/docker_fundamentos/lab.yaml" || docker.Digest != repo.Digest(data) {
+		t.Errorf("url = %s, digest = %s", docker.URL, docker.Digest)
+	}
+	if strings.Join(docker.Tags, ",") != "docker" {
+		t.Errorf("tags = %v, esperado a categoria do diretório", docker.Tags)
+	}
+	if !strings.HasSuffix(index.Labs[1].URL, "/docker_fundamentos/lab_es.yaml") {
+		t.Errorf("url em espanhol = %s", index.Labs[1].URL)
+	}
+
+	linux := index.Labs[2]
+	if linux.Version != "1.0.0" || linux.Description != "Introdução aos comandos básicos do Linux" || len(linux.Tags) != 0 {
+		t.Errorf("entrada linux-basics = %+v", linux)
+	}
+}
+
+func TestGenerateIndexRelativeURLs(t *testing.T) {
+	index, err := repo.GenerateIndex(labDir(t), repo.IndexOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := index.Labs[2].URL; got != "linux-basics/lab.yaml" {
+		t.Errorf("url = %s, esperado relativa ao índice", got)
+	}
+}
+
+func TestGenerateIndexMerge(t *testing.T) {
+	dir := labDir(t)
+	existing := filepath.Join(t.TempDir(), "index.yaml")
+	old := `apiVersion: v1
+generated: "2024-03-20T10:00:00Z"
+entries:
+  linux-basics:
+    - name: linux-basics
+      version: "0.9.0"
+      url: "https://exemplo.com/labs/linux-basics/v0.9.0/lab.yaml"
+      digest: "sha256:antigo"
+    - name: linux-basics
+      version: "1.0.0"
+      url: "https://exemplo.com/labs/linux-basics/lab.yaml"
+      digest: "sha256:substituido"
+`
+	if err := os.WriteFile(existing, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	merge, err := repo.ReadIndexFile(existing)
+	if err != nil {
+		t.Fatalf("ReadIndexFile: %v", err)
+	}
+
+	index, err := repo.GenerateIndex(dir, repo.IndexOptions{BaseURL: "https://exemplo.com/labs", Merge: merge})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []string
+	for _, entry := range index.Labs {
+		if entry.ID != "linux-basics" {
+			continue
+		}
+		versions = append(versions, entry.Version)
+		if entry.Digest == "sha256:substituido" {
+			t.Error("a versão presente no diretório deveria substituir a do índice existente")
+		}
+	}
+	if got := strings.Join(versions, ","); got != "1.0.0,0.9.0" {
+		t.Errorf("versões = %s, esperado 1.0.0,0.9.0", got)
+	}
+}
+
+func TestGenerateIndexInvalidLab(t *testing.T) {
+	dir := labDir(t)
+	if err := os.WriteFile(filepath.Join(dir, "linux-basics", "lab_es.yaml"), []byte("kind: ConfigMap\nmetadata:\n  name: vazio\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GenerateIndex(dir, repo.IndexOptions{}); err == nil {
+		t.Error("esperado erro para arquivo sem template de laboratório")
+	}
+}
@@ -1,261 +1,158 @@
 package repo
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"gopkg.in/yaml.v3"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
-)
-
-// Repository representa um repositório de laboratórios
-type Repository struct {
-	Name        string `yaml:"name"`
-	URL         string `yaml:"url"`
-	Description string `yaml:"description"`
-	Version     string `yaml:"version"`
-}
-
-// Index representa o arquivo de índice de um repositório
-type Index struct {
-	APIVersion string     `yaml:"apiVersion"`
-	Generated  string     `yaml:"generated"`
-	Labs       []LabEntry `yaml:"labs"`
-}
-
-// LabEntry representa um laboratório no índice
-type LabEntry struct {
-	ID          string   `yaml:"id"`
-	Title       string   `yaml:"title"`
-	Description string   `yaml:"description"`
-	Version     string   `yaml:"version"`
-	Duration    string   `yaml:"duration"`
-	Tags        []string `yaml:"tags"`
-	URL         string   `yaml:"url"`
-}
-
-// RepositoryManager gerencia os repositórios de laboratórios
-type RepositoryManager struct {
-	configPath string
-	repos      map[string]Repository
-}
-
-// NewRepositoryManager cria uma nova instância do gerenciador de repositórios
-func NewRepositoryManager() (*RepositoryManager, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("erro ao obter diretório home: %v", err)
-	}
+	"time"
 
-	configPath := filepath.Join(homeDir, ".girus", "repositories.json")
-	rm := &RepositoryManager{
-		configPath: configPath,
-		repos:      make(map[string]Repository),
-	}
+	"sigs.k8s.io/yaml"
+)
 
-	// Carrega repositórios existentes
-	if err := rm.loadRepositories(); err != nil {
-		return nil, err
-	}
+// fetchTimeout é o tempo limite das requisições HTTP aos repositórios
+const fetchTimeout = 30 * time.Second
 
-	// Se não houver repositórios configurados, adiciona o repositório oficial
-	if len(rm.repos) == 0 {
-		defaultRepo := Repository{
-			Name:        "girus-labs",
-			URL:         "https://raw.githubusercontent.com/badtuxx/girus-labs/main",
-			Description: "Repositório oficial de labs do GIRUS",
-			Version:     "v1",
-		}
-		rm.repos[defaultRepo.Name] = defaultRepo
-		if err := rm.saveRepositories(); err != nil {
-			return nil, fmt.Errorf("erro ao salvar repositório padrão: %v", err)
+// fetch lê o conteúdo de uma URL file:// ou HTTP(S)
+func fetch(ctx context.Context, location string) ([]byte, error) {
+	if strings.HasPrefix(location, "file://") {
+		data, err := os.ReadFile(strings.TrimPrefix(location, "file://"))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler arquivo local: %w", err)
 		}
+		return data, nil
 	}
 
-	return rm, nil
-}
-
-// AddRepository adiciona um novo repositório
-func (rm *RepositoryManager) AddRepository(name, url, description string) error {
-	// Verifica se o repositório já existe
-	if _, exists := rm.repos[name]; exists {
-		return fmt.Errorf("repositório '%s' já existe", name)
-	}
-
-	// Valida o repositório
-	if err := rm.validateRepository(url); err != nil {
-		return fmt.Errorf("repositório inválido: %v", err)
-	}
-
-	// Adiciona o repositório
-	rm.repos[name] = Repository{
-		Name:        name,
-		URL:         url,
-		Description: description,
-		Version:     "v1",
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("URL inválida %s: %w", location, err)
 	}
-
-	// Salva as alterações
-	return rm.saveRepositories()
-}
-
-// RemoveRepository remove um repositório
-func (rm *RepositoryManager) RemoveRepository(name string) error {
-	if _, exists := rm.repos[name]; !exists {
-		return fmt.Errorf("repositório '%s' não encontrado", name)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao acessar %s: %w", location, err)
 	}
+	defer resp.Body.Close()
 
-	delete(rm.repos, name)
-	return rm.saveRepositories()
-}
-
-// ListRepositories lista todos os repositórios
-func (rm *RepositoryManager) ListRepositories() []Repository {
-	repos := make([]Repository, 0, len(rm.repos))
-	for _, repo := range rm.repos {
-		repos = append(repos, repo)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("erro HTTP %d ao acessar %s", resp.StatusCode, location)
 	}
-	return repos
-}
-
-// GetRepository obtém um repositório específico
-func (rm *RepositoryManager) GetRepository(name string) (Repository, error) {
-	repo, exists := rm.repos[name]
-	if !exists {
-		return Repository{}, fmt.Errorf("repositório '%s' não encontrado", name)
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler %s: %w", location, err)
 	}
-	return repo, nil
+	return data, nil
 }
 
-// UpdateRepository atualiza um repositório existente
-func (rm *RepositoryManager) UpdateRepository(name, url, description string) error {
-	if _, exists := rm.repos[name]; !exists {
-		return fmt.Errorf("repositório '%s' não encontrado", name)
-	}
-
-	// Valida o repositório
-	if err := rm.validateRepository(url); err != nil {
-		return fmt.Errorf("repositório inválido: %v", err)
+// fetchIndex baixa e interpreta o índice do repositório, retornando também o
+// conteúdo original para o cache
+func fetchIndex(ctx context.Context, repoURL string) (*Index, []byte, error) {
+	location := indexURL(repoURL)
+	data, err := fetch(ctx, location)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	rm.repos[name] = Repository{
-		Name:        name,
-		URL:         url,
-		Description: description,
-		Version:     "v1",
+	index, err := loadIndex(data, location)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	return rm.saveRepositories()
+	return index, data, nil
 }
 
-// loadRepositories carrega os repositórios do arquivo de configuração
-func (rm *RepositoryManager) loadRepositories() error {
-	// Cria o diretório se não existir
-	dir := filepath.Dir(rm.configPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("erro ao criar diretório de configuração: %v", err)
+// fetchVerifiedIndex baixa o índice do repositório e, se ele tiver chaves
+// confiáveis, a assinatura destacada, que é verificada e retornada para o cache
+func fetchVerifiedIndex(ctx context.Context, r Repository) (*Index, []byte, []byte, error) {
+	index, data, err := fetchIndex(ctx, r.URL)
+	if err != nil {
+		return nil, nil, nil, err
 	}
-
-	// Verifica se o arquivo existe
-	if _, err := os.Stat(rm.configPath); os.IsNotExist(err) {
-		return nil
+	if !verifiesIndex(r) {
+		return index, data, nil, nil
 	}
-
-	// Lê o arquivo
-	data, err := os.ReadFile(rm.configPath)
+	signature, err := fetch(ctx, indexURL(r.URL)+SignatureSuffix)
 	if err != nil {
-		return fmt.Errorf("erro ao ler arquivo de configuração: %v", err)
+		return nil, nil, nil, fmt.Errorf("%w: assinatura do índice do repositório %s indisponível: %v", ErrVerification, r.Name, err)
 	}
-
-	// Decodifica o JSON
-	if err := json.Unmarshal(data, &rm.repos); err != nil {
-		return fmt.Errorf("erro ao decodificar arquivo de configuração: %v", err)
+	if err := verifyIndexSignature(r, data, signature); err != nil {
+		return nil, nil, nil, err
 	}
-
-	return nil
+	return index, data, signature, nil
 }
 
-// saveRepositories salva os repositórios no arquivo de configuração
-func (rm *RepositoryManager) saveRepositories() error {
-	// Codifica para JSON
-	data, err := json.MarshalIndent(rm.repos, "", "  ")
+// loadIndex interpreta o índice lido de location e resolve as URLs relativas
+// dos laboratórios a partir dele
+func loadIndex(data []byte, location string) (*Index, error) {
+	index, err := parseIndex(data)
 	if err != nil {
-		return fmt.Errorf("erro ao codificar configuração: %v", err)
-	}
-
-	// Salva no arquivo
-	if err := os.WriteFile(rm.configPath, data, 0644); err != nil {
-		return fmt.Errorf("erro ao salvar arquivo de configuração: %v", err)
+		return nil, err
 	}
-
-	return nil
-}
-
-// validateRepository valida se um repositório é acessível e válido
-func (rm *RepositoryManager) validateRepository(url string) error {
-	_, err := fetchAndParseIndex(url)
+	base, err := url.Parse(location)
 	if err != nil {
-		return fmt.Errorf("falha ao validar repositório: %v", err)
+		return index, nil
 	}
-	return nil
-}
-
-// fetchAndParseIndex baixa e parseia o arquivo index.yaml de um repositório
-func fetchAndParseIndex(url string) (*Index, error) {
-	// Se a URL usa o protocolo file://
-	if strings.HasPrefix(url, "file://") {
-		filePath := strings.TrimPrefix(url, "file://")
-		data, err := os.ReadFile(filePath)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao ler arquivo local: %v", err)
+	for i, entry := range index.Labs {
+		if entry.URL == "" || strings.Contains(entry.URL, "://") {
+			continue
 		}
-
-		var index Index
-		if err := yaml.Unmarshal(data, &index); err != nil {
-			return nil, fmt.Errorf("erro ao decodificar arquivo local: %v", err)
+		if ref, err := url.Parse(entry.URL); err == nil {
+			index.Labs[i].URL = base.ResolveReference(ref).String()
 		}
-		return &index, nil
-	}
-
-	// Para URLs HTTP/HTTPS
-	resp, err := http.Get(fmt.Sprintf("%s/index.yaml", url))
-	if err != nil {
-		return nil, fmt.Errorf("erro ao acessar repositório: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("erro ao acessar repositório (status: %d)", resp.StatusCode)
 	}
+	return index, nil
+}
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao ler conteúdo do repositório: %v", err)
+// indexURL retorna o endereço do index.yaml de um repositório. A URL do
+// repositório pode apontar para o diretório ou diretamente para o índice.
+func indexURL(repoURL string) string {
+	if strings.HasSuffix(repoURL, ".yaml") || strings.HasSuffix(repoURL, ".yml") {
+		return repoURL
 	}
+	return strings.TrimSuffix(repoURL, "/") + "/index.yaml"
+}
 
-	var index Index
-	if err := yaml.Unmarshal(data, &index); err != nil {
+// parseIndex interpreta o index.yaml. Além da lista labs, aceita o formato
+// entries (um mapa de ID para as versões publicadas), usado pelo index.yaml da
+// raiz do girus-cli.
+func parseIndex(data []byte) (*Index, error) {
+	var raw struct {
+		Index
+		Entries map[string][]struct {
+			Name        string   `json:"name"`
+			Title       string   `json:"title"`
+			Description string   `json:"description"`
+			Version     string   `json:"version"`
+			Duration    string   `json:"duration"`
+			Keywords    []string `json:"keywords"`
+			URL         string   `json:"url"`
+			Digest      string   `json:"digest"`
+		} `json:"entries"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
 		return nil, fmt.Errorf("erro ao decodificar índice do repositório: %v", err)
 	}
 
-	return &index, nil
-}
-
-// ListLabs lista todos os laboratórios disponíveis em todos os repositórios
-func (lm *LabManager) ListLabs() (map[string][]LabEntry, error) {
-	allLabs := make(map[string][]LabEntry)
-
-	for _, repo := range lm.repoManager.ListRepositories() {
-		index, err := lm.getIndex(repo)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao obter índice do repositório %s: %v", repo.Name, err)
+	index := raw.Index
+	ids := make([]string, 0, len(raw.Entries))
+	for id := range raw.Entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		for _, e := range raw.Entries[id] {
+			entry := LabEntry{ID: e.Name, Title: e.Title, Description: e.Description, Version: e.Version, Duration: e.Duration, Tags: e.Keywords, URL: e.URL, Digest: e.Digest}
+			if entry.ID == "" {
+				entry.ID = id
+			}
+			if entry.Title == "" {
+				entry.Title = entry.ID
+			}
+			index.Labs = append(index.Labs, entry)
 		}
-
-		allLabs[repo.Name] = index.Labs
 	}
-
-	return allLabs, nil
+	return &index, nil
 }
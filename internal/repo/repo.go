@@ -1,187 +1,61 @@
 package repo
 
 import (
-	"fmt"
-	"io"
-	"net/http"
-	"os"
+	"errors"
 	"strings"
-	"time"
-
-	"github.com/badtuxx/girus-cli/internal/common"
-	"sigs.k8s.io/yaml"
 )
 
-// Estrutura para o arquivo index.yaml
-type IndexFile struct {
-	Labs []Lab `yaml:"labs"`
-}
-
-// Estrutura para cada laboratório no index.yaml
-type Lab struct {
-	ID          string   `yaml:"id"`
-	Title       string   `yaml:"title"`
-	Description string   `yaml:"description"`
-	Version     string   `yaml:"version"`
-	Duration    string   `yaml:"duration"`
-	Tags        []string `yaml:"tags,omitempty"`
-	URL         string   `yaml:"url"`
+// Repository representa um repositório de laboratórios
+type Repository struct {
+	Name        string `json:"name" yaml:"name"`
+	URL         string `json:"url" yaml:"url"`
+	Description string `json:"description" yaml:"description"`
+	Version     string `json:"version" yaml:"version"`
+	// PublicKeys são as chaves ed25519 confiáveis do repositório. Quando
+	// informadas, o índice precisa de uma assinatura destacada válida e todos
+	// os laboratórios precisam de digest.
+	PublicKeys []string `json:"publicKeys,omitempty" yaml:"publicKeys,omitempty"`
 }
 
-// URL padrão do index.yaml
-var DefaultIndexURL = "https://raw.githubusercontent.com/badtuxx/girus-labs/main/index.yaml"
-
-// GetIndexURL retorna a URL do index.yaml, considerando variáveis de ambiente
-func GetIndexURL() string {
-	// Verificar variável de ambiente
-	if url := os.Getenv("GIRUS_REPO_URL"); url != "" {
-		return url
-	}
-	return DefaultIndexURL
+// Index representa o arquivo index.yaml de um repositório
+type Index struct {
+	APIVersion string     `json:"apiVersion" yaml:"apiVersion"`
+	Generated  string     `json:"generated" yaml:"generated"`
+	Labs       []LabEntry `json:"labs" yaml:"labs"`
 }
 
-// GetLabsIndex baixa e parseia o index.yaml remoto
-func GetLabsIndex(indexURL string) (*IndexFile, error) {
-	// Se não for fornecida uma URL, usar a URL padrão
-	if indexURL == "" {
-		indexURL = GetIndexURL()
-	}
-
-	var data []byte
-	var err error
-
-	// Verificar se a URL usa o protocolo file://
-	if strings.HasPrefix(indexURL, "file://") {
-		// Extrair o caminho do arquivo da URL
-		filePath := strings.TrimPrefix(indexURL, "file://")
-		// Ler o arquivo local
-		data, err = os.ReadFile(filePath)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao ler o arquivo local %s: %w", filePath, err)
-		}
-	} else {
-		// Configurar cliente HTTP com timeout
-		client := &http.Client{
-			Timeout: 10 * time.Second,
-		}
-
-		// Fazer a requisição HTTP
-		resp, err := client.Get(indexURL)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao acessar o repositório remoto: %w", err)
-		}
-		defer resp.Body.Close()
-
-		// Verificar se a resposta foi bem-sucedida
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("erro HTTP %d ao acessar o repositório remoto", resp.StatusCode)
-		}
-
-		// Ler o conteúdo da resposta
-		data, err = io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao ler o conteúdo do repositório: %w", err)
-		}
-	}
-
-	// Parsear o YAML
-	var index IndexFile
-	if err := yaml.Unmarshal(data, &index); err != nil {
-		return nil, fmt.Errorf("erro ao parsear o arquivo index.yaml: %w", err)
-	}
-
-	// Filtrar labs de acordo com o idioma selecionado
-	lang := common.Lang()
-	filtered := make([]Lab, 0, len(index.Labs))
-	for _, lab := range index.Labs {
-		if lang == "es" {
-			if strings.HasSuffix(lab.ID, "-es") || strings.Contains(lab.URL, "_es.yaml") {
-				filtered = append(filtered, lab)
-			}
-		} else { // pt ou padrão
-			if !strings.HasSuffix(lab.ID, "-es") && !strings.Contains(lab.URL, "_es.yaml") {
-				filtered = append(filtered, lab)
-			}
-		}
-	}
-	index.Labs = filtered
-
-	return &index, nil
+// LabEntry representa um laboratório no índice
+type LabEntry struct {
+	ID          string   `json:"id" yaml:"id"`
+	Title       string   `json:"title" yaml:"title"`
+	Description string   `json:"description" yaml:"description"`
+	Version     string   `json:"version" yaml:"version"`
+	Duration    string   `json:"duration" yaml:"duration"`
+	Tags        []string `json:"tags" yaml:"tags"`
+	URL         string   `json:"url" yaml:"url"`
+	// Digest é o sha256 do manifesto, no formato sha256:<hex>, verificado após
+	// o download
+	Digest string `json:"digest,omitempty" yaml:"digest,omitempty"`
 }
 
-// FindLabByID busca um laboratório pelo ID no index.yaml
-func FindLabByID(id string, indexURL string) (*Lab, error) {
-	index, err := GetLabsIndex(indexURL)
-	if err != nil {
-		return nil, err
-	}
+// ErrLabNotFound indica que o laboratório não existe no índice do repositório
+var ErrLabNotFound = errors.New("laboratório não encontrado")
 
-	for _, lab := range index.Labs {
-		if lab.ID == id {
-			return &lab, nil
-		}
-	}
+// ErrRepositoryNotFound indica que o repositório não está configurado
+var ErrRepositoryNotFound = errors.New("repositório não encontrado")
 
-	return nil, fmt.Errorf("laboratório com ID '%s' não encontrado no repositório", id)
+// DefaultRepository é o repositório oficial, configurado quando o
+// repositories.json está vazio
+var DefaultRepository = Repository{
+	Name:        "girus-labs",
+	URL:         "https://raw.githubusercontent.com/badtuxx/girus-labs/main",
+	Description: "Repositório oficial de labs do GIRUS",
+	Version:     "v1",
 }
 
-// DownloadLabYAML baixa o arquivo lab.yaml para um arquivo temporário
-func DownloadLabYAML(url string) (string, error) {
-	// Criar arquivo temporário
-	tempFile, err := os.CreateTemp("", "girus-lab-*.yaml")
-	if err != nil {
-		return "", fmt.Errorf("erro ao criar arquivo temporário: %w", err)
-	}
-	defer tempFile.Close()
-
-	var data []byte
-
-	// Verificar se a URL usa o protocolo file://
-	if strings.HasPrefix(url, "file://") {
-		// Extrair o caminho do arquivo da URL
-		filePath := strings.TrimPrefix(url, "file://")
-		// Ler o arquivo local
-		data, err = os.ReadFile(filePath)
-		if err != nil {
-			os.Remove(tempFile.Name())
-			return "", fmt.Errorf("erro ao ler o arquivo local %s: %w", filePath, err)
-		}
-
-		// Escrever o conteúdo no arquivo temporário
-		if _, err := tempFile.Write(data); err != nil {
-			os.Remove(tempFile.Name())
-			return "", fmt.Errorf("erro ao salvar o arquivo lab.yaml: %w", err)
-		}
-	} else {
-		// Configurar cliente HTTP com timeout
-		client := &http.Client{
-			Timeout: 20 * time.Second,
-		}
-
-		// Fazer a requisição HTTP
-		resp, err := client.Get(url)
-		if err != nil {
-			os.Remove(tempFile.Name()) // Limpar o arquivo temporário em caso de erro
-			return "", fmt.Errorf("erro ao baixar o arquivo lab.yaml: %w", err)
-		}
-		defer resp.Body.Close()
-
-		// Verificar se a resposta foi bem-sucedida
-		if resp.StatusCode != http.StatusOK {
-			os.Remove(tempFile.Name())
-			return "", fmt.Errorf("erro HTTP %d ao baixar o arquivo lab.yaml", resp.StatusCode)
-		}
-
-		// Copiar o conteúdo para o arquivo temporário
-		_, err = io.Copy(tempFile, resp.Body)
-		if err != nil {
-			os.Remove(tempFile.Name())
-			return "", fmt.Errorf("erro ao salvar o arquivo lab.yaml: %w", err)
-		}
-	}
-
-	return tempFile.Name(), nil
-}
+// URLRepositoryName é o nome do repositório informado por GIRUS_REPO_URL ou
+// pela flag --url, que substitui os repositórios configurados
+const URLRepositoryName = "url"
 
 // FormatTags retorna as tags formatadas como string
 func FormatTags(tags []string) string {
@@ -190,3 +64,13 @@ func FormatTags(tags []string) string {
 	}
 	return strings.Join(tags, ", ")
 }
+
+// matchesLanguage indica se o laboratório pertence ao idioma: as versões em
+// espanhol têm o sufixo -es no ID ou o arquivo _es.yaml
+func matchesLanguage(entry LabEntry, lang string) bool {
+	spanish := strings.HasSuffix(entry.ID, "-es") || strings.Contains(entry.URL, "_es.yaml")
+	if lang == "es" {
+		return spanish
+	}
+	return !spanish
+}
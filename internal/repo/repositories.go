@@ -0,0 +1,186 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RepositoryManager gerencia os repositórios de laboratórios
+type RepositoryManager struct {
+	configPath string
+	repos      map[string]Repository
+}
+
+// NewRepositoryManager cria uma nova instância do gerenciador de repositórios
+func NewRepositoryManager() (*RepositoryManager, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter diretório home: %v", err)
+	}
+
+	configPath := filepath.Join(homeDir, ".girus", "repositories.json")
+	rm := &RepositoryManager{
+		configPath: configPath,
+		repos:      make(map[string]Repository),
+	}
+
+	// Carrega repositórios existentes
+	if err := rm.loadRepositories(); err != nil {
+		return nil, err
+	}
+
+	// Se não houver repositórios configurados, adiciona o repositório oficial
+	if len(rm.repos) == 0 {
+		rm.repos[DefaultRepository.Name] = DefaultRepository
+		if err := rm.saveRepositories(); err != nil {
+			return nil, fmt.Errorf("erro ao salvar repositório padrão: %v", err)
+		}
+	}
+
+	return rm, nil
+}
+
+// AddRepository adiciona um novo repositório. Com publicKeys, o índice do
+// repositório precisa estar assinado por uma das chaves.
+func (rm *RepositoryManager) AddRepository(name, url, description string, publicKeys []string) error {
+	// Verifica se o repositório já existe
+	if _, exists := rm.repos[name]; exists {
+		return fmt.Errorf("repositório '%s' já existe", name)
+	}
+
+	r := Repository{
+		Name:        name,
+		URL:         url,
+		Description: description,
+		Version:     "v1",
+		PublicKeys:  publicKeys,
+	}
+
+	// Valida o repositório
+	if err := rm.validateRepository(r); err != nil {
+		return fmt.Errorf("repositório inválido: %w", err)
+	}
+
+	// Adiciona o repositório
+	rm.repos[name] = r
+
+	// Salva as alterações
+	return rm.saveRepositories()
+}
+
+// RemoveRepository remove um repositório
+func (rm *RepositoryManager) RemoveRepository(name string) error {
+	if _, exists := rm.repos[name]; !exists {
+		return fmt.Errorf("repositório '%s' não encontrado", name)
+	}
+
+	delete(rm.repos, name)
+	return rm.saveRepositories()
+}
+
+// ListRepositories lista todos os repositórios
+func (rm *RepositoryManager) ListRepositories() []Repository {
+	repos := make([]Repository, 0, len(rm.repos))
+	for _, repo := range rm.repos {
+		repos = append(repos, repo)
+	}
+	return repos
+}
+
+// GetRepository obtém um repositório específico
+func (rm *RepositoryManager) GetRepository(name string) (Repository, error) {
+	repo, exists := rm.repos[name]
+	if !exists {
+		return Repository{}, fmt.Errorf("repositório '%s' não encontrado", name)
+	}
+	return repo, nil
+}
+
+// UpdateRepository atualiza um repositório existente. Com publicKeys nil as
+// chaves confiáveis atuais são mantidas.
+func (rm *RepositoryManager) UpdateRepository(name, url, description string, publicKeys []string) error {
+	current, exists := rm.repos[name]
+	if !exists {
+		return fmt.Errorf("repositório '%s' não encontrado", name)
+	}
+	if publicKeys == nil {
+		publicKeys = current.PublicKeys
+	}
+
+	r := Repository{
+		Name:        name,
+		URL:         url,
+		Description: description,
+		Version:     "v1",
+		PublicKeys:  publicKeys,
+	}
+
+	// Valida o repositório
+	if err := rm.validateRepository(r); err != nil {
+		return fmt.Errorf("repositório inválido: %w", err)
+	}
+
+	rm.repos[name] = r
+
+	return rm.saveRepositories()
+}
+
+// loadRepositories carrega os repositórios do arquivo de configuração
+func (rm *RepositoryManager) loadRepositories() error {
+	// Cria o diretório se não existir
+	dir := filepath.Dir(rm.configPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de configuração: %v", err)
+	}
+
+	// Verifica se o arquivo existe
+	if _, err := os.Stat(rm.configPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	// Lê o arquivo
+	data, err := os.ReadFile(rm.configPath)
+	if err != nil {
+		return fmt.Errorf("erro ao ler arquivo de configuração: %v", err)
+	}
+
+	// Decodifica o JSON
+	if err := json.Unmarshal(data, &rm.repos); err != nil {
+		return fmt.Errorf("erro ao decodificar arquivo de configuração: %v", err)
+	}
+
+	return nil
+}
+
+// saveRepositories salva os repositórios no arquivo de configuração
+func (rm *RepositoryManager) saveRepositories() error {
+	// Codifica para JSON
+	data, err := json.MarshalIndent(rm.repos, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao codificar configuração: %v", err)
+	}
+
+	// Salva no arquivo
+	if err := os.WriteFile(rm.configPath, data, 0644); err != nil {
+		return fmt.Errorf("erro ao salvar arquivo de configuração: %v", err)
+	}
+
+	return nil
+}
+
+// validateRepository valida se um repositório é acessível e válido e se as
+// chaves confiáveis verificam a assinatura do índice
+func (rm *RepositoryManager) validateRepository(r Repository) error {
+	for _, key := range r.PublicKeys {
+		if _, err := ParsePublicKey(key); err != nil {
+			return err
+		}
+	}
+	if _, _, _, err := fetchVerifiedIndex(context.Background(), r); err != nil {
+		return fmt.Errorf("falha ao validar repositório: %w", err)
+	}
+	return nil
+}
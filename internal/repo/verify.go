@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrVerification indica que o índice ou o manifesto baixado não passou na
+// verificação de integridade (digest ou assinatura)
+var ErrVerification = errors.New("falha na verificação de integridade")
+
+// SignatureSuffix é o sufixo da assinatura destacada do índice, publicada ao
+// lado do index.yaml (index.yaml.sig)
+const SignatureSuffix = ".sig"
+
+// insecureSkipVerify desativa a verificação de digests e assinaturas
+var insecureSkipVerify bool
+
+// SetInsecureSkipVerify desativa (ou reativa) a verificação de integridade dos
+// índices e laboratórios baixados
+func SetInsecureSkipVerify(skip bool) {
+	insecureSkipVerify = skip
+}
+
+// PublicKey é uma chave ed25519 confiável de um repositório
+type PublicKey struct {
+	key ed25519.PublicKey
+	// keyID é o identificador da chave no formato minisign; vazio para chaves
+	// ed25519 puras
+	keyID []byte
+}
+
+// ParsePublicKey interpreta uma chave pública ed25519 em um dos formatos:
+// a chave pública do minisign (com ou sem a linha "untrusted comment"), um PEM
+// "PUBLIC KEY" ou os 32 bytes da chave em base64
+func ParsePublicKey(key string) (*PublicKey, error) {
+	key = strings.TrimSpace(key)
+	if block, _ := pem.Decode([]byte(key)); block != nil {
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("chave pública PEM inválida: %w", err)
+		}
+		edKey, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("chave pública PEM não é ed25519")
+		}
+		return &PublicKey{key: edKey}, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(lastLine(key))
+	if err != nil {
+		return nil, fmt.Errorf("chave pública inválida: %w", err)
+	}
+	switch {
+	case len(data) == ed25519.PublicKeySize:
+		return &PublicKey{key: ed25519.PublicKey(data)}, nil
+	case len(data) == 2+8+ed25519.PublicKeySize && string(data[:2]) == "Ed":
+		return &PublicKey{key: ed25519.PublicKey(data[10:]), keyID: data[2:10]}, nil
+	}
+	return nil, fmt.Errorf("chave pública com formato desconhecido (use ed25519 em base64, PEM ou minisign)")
+}
+
+// verifyDigest confere o digest (sha256:<hex>) do conteúdo baixado
+func verifyDigest(data []byte, digest string) error {
+	if !strings.HasPrefix(digest, "sha256:") {
+		return fmt.Errorf("%w: algoritmo do digest '%s' não suportado (use sha256)", ErrVerification, digest)
+	}
+	if got := Digest(data); !strings.EqualFold(got, digest) {
+		return fmt.Errorf("%w: digest %s, esperado %s", ErrVerification, got, digest)
+	}
+	return nil
+}
+
+// verifiesIndex indica se o índice do repositório precisa de assinatura
+func verifiesIndex(r Repository) bool {
+	return len(r.PublicKeys) > 0 && !insecureSkipVerify
+}
+
+// verifyIndexSignature verifica a assinatura destacada do índice com as chaves
+// confiáveis do repositório. A assinatura pode estar no formato do minisign
+// (gerada com minisign -S -l) ou ser a assinatura ed25519 pura em base64.
+func verifyIndexSignature(r Repository, data, signature []byte) error {
+	var keys []*PublicKey
+	for _, k := range r.PublicKeys {
+		key, err := ParsePublicKey(k)
+		if err != nil {
+			return fmt.Errorf("%w: repositório %s: %v", ErrVerification, r.Name, err)
+		}
+		keys = append(keys, key)
+	}
+
+	text := strings.TrimSpace(string(signature))
+	if strings.HasPrefix(text, "untrusted comment:") {
+		return verifyMinisign(keys, data, text)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(text)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: assinatura do índice mal formada", ErrVerification)
+	}
+	for _, key := range keys {
+		if ed25519.Verify(key.key, data, sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: a assinatura do índice não confere com as chaves confiáveis do repositório %s", ErrVerification, r.Name)
+}
+
+// verifyMinisign verifica uma assinatura no formato do minisign: a assinatura
+// do índice e a assinatura global sobre o comentário confiável
+func verifyMinisign(keys []*PublicKey, data []byte, text string) error {
+	lines := strings.Split(text, "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return fmt.Errorf("%w: assinatura minisign mal formada", ErrVerification)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("%w: assinatura minisign mal formada", ErrVerification)
+	}
+	global, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: assinatura global minisign mal formada", ErrVerification)
+	}
+	if string(sig[:2]) != "Ed" {
+		return fmt.Errorf("%w: algoritmo de assinatura minisign '%s' não suportado (assine com minisign -S -l)", ErrVerification, sig[:2])
+	}
+
+	keyID, signature := sig[2:10], sig[10:]
+	trusted := strings.TrimSuffix(strings.TrimPrefix(lines[2], "trusted comment: "), "\r")
+	for _, key := range keys {
+		if key.keyID != nil && !bytes.Equal(key.keyID, keyID) {
+			continue
+		}
+		if ed25519.Verify(key.key, data, signature) && ed25519.Verify(key.key, append(append([]byte{}, signature...), trusted...), global) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: a assinatura minisign do índice não confere com as chaves confiáveis", ErrVerification)
+}
+
+// lastLine retorna a última linha não vazia do texto, ignorando a linha de
+// comentário das chaves do minisign
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,169 @@
+package repo_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/badtuxx/girus-cli/internal/repo"
+)
+
+const signedLab = "kind: Lab\n"
+
+// serveFiles publica os arquivos pelo caminho da URL
+func serveFiles(t *testing.T, files map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, content)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// signedIndex retorna um índice com o laboratório linux e o digest informado
+func signedIndex(digest string) string {
+	return fmt.Sprintf("labs:\n- id: linux\n  version: 1.0.0\n  url: linux/lab.yaml\n  digest: %s\n", digest)
+}
+
+// minisign retorna a chave pública e a assinatura do conteúdo no formato do
+// minisign (algoritmo Ed)
+func minisign(t *testing.T, private ed25519.PrivateKey, data string) (string, string) {
+	t.Helper()
+	keyID := []byte("girus-id")
+	public := append(append([]byte("Ed"), keyID...), private.Public().(ed25519.PublicKey)...)
+	signature := ed25519.Sign(private, []byte(data))
+	trusted := "timestamp:1700000000\tfile:index.yaml"
+	global := ed25519.Sign(private, append(append([]byte{}, signature...), trusted...))
+
+	key := "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(public) + "\n"
+	sig := "untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), signature...)) + "\n" +
+		"trusted comment: " + trusted + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n"
+	return key, sig
+}
+
+func TestClientVerifiesIntegrity(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawKey := base64.StdEncoding.EncodeToString(public)
+	index := signedIndex(repo.Digest([]byte(signedLab)))
+	minisignKey, minisignSig := minisign(t, private, index)
+
+	signed := serveFiles(t, map[string]string{
+		"/index.yaml":     index,
+		"/index.yaml.sig": base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(index))),
+		"/linux/lab.yaml": signedLab,
+	})
+	minisigned := serveFiles(t, map[string]string{
+		"/index.yaml":     index,
+		"/index.yaml.sig": minisignSig,
+		"/linux/lab.yaml": signedLab,
+	})
+	tamperedIndex := serveFiles(t, map[string]string{
+		"/index.yaml":     strings.Replace(index, "1.0.0", "9.0.0", 1),
+		"/index.yaml.sig": base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(index))),
+		"/linux/lab.yaml": signedLab,
+	})
+	tamperedLab := serveFiles(t, map[string]string{
+		"/index.yaml":     signedIndex(repo.Digest([]byte(signedLab))),
+		"/linux/lab.yaml": "kind: Lab\nmalicioso: true\n",
+	})
+	configureRepositories(t,
+		repo.Repository{Name: "assinado", URL: signed.URL, PublicKeys: []string{rawKey}},
+		repo.Repository{Name: "minisign", URL: minisigned.URL, PublicKeys: []string{minisignKey}},
+		repo.Repository{Name: "adulterado", URL: tamperedIndex.URL, PublicKeys: []string{rawKey}},
+		repo.Repository{Name: "sem-chave", URL: tamperedLab.URL},
+	)
+	client, err := repo.NewClient("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for _, name := range []string{"assinado", "minisign"} {
+		lab, err := client.FindLab(ctx, name, "linux", "")
+		if err != nil {
+			t.Fatalf("FindLab(%s): %v", name, err)
+		}
+		if _, err := client.Download(ctx, lab); err != nil {
+			t.Errorf("Download(%s): %v", name, err)
+		}
+	}
+
+	if _, err := client.FindLab(ctx, "adulterado", "linux", ""); !errors.Is(err, repo.ErrVerification) {
+		t.Errorf("índice adulterado: erro = %v, esperado ErrVerification", err)
+	}
+
+	lab, err := client.FindLab(ctx, "sem-chave", "linux", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Download(ctx, lab); !errors.Is(err, repo.ErrVerification) {
+		t.Errorf("manifesto adulterado: erro = %v, esperado ErrVerification", err)
+	}
+
+	repo.SetInsecureSkipVerify(true)
+	t.Cleanup(func() { repo.SetInsecureSkipVerify(false) })
+	if _, err := client.Download(ctx, lab); err != nil {
+		t.Errorf("Download com a verificação desativada: %v", err)
+	}
+	if _, err := client.FindLab(ctx, "adulterado", "linux", ""); err != nil {
+		t.Errorf("FindLab com a verificação desativada: %v", err)
+	}
+}
+
+func TestClientRequiresDigestInSignedIndex(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := "labs:\n- id: linux\n  version: 1.0.0\n  url: linux/lab.yaml\n"
+	server := serveFiles(t, map[string]string{
+		"/index.yaml":     index,
+		"/index.yaml.sig": base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(index))),
+		"/linux/lab.yaml": signedLab,
+	})
+	configureRepositories(t, repo.Repository{Name: "assinado", URL: server.URL, PublicKeys: []string{base64.StdEncoding.EncodeToString(public)}})
+
+	client, err := repo.NewClient("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lab, err := client.FindLab(context.Background(), "", "linux", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Download(context.Background(), lab); !errors.Is(err, repo.ErrVerification) {
+		t.Errorf("erro = %v, esperado ErrVerification para laboratório sem digest", err)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.ParsePublicKey(base64.StdEncoding.EncodeToString(public)); err != nil {
+		t.Errorf("chave base64: %v", err)
+	}
+	if _, err := repo.ParsePublicKey("não é uma chave"); err == nil {
+		t.Error("esperado erro para chave inválida")
+	}
+	if _, err := repo.ParsePublicKey(base64.StdEncoding.EncodeToString([]byte("curta"))); err == nil {
+		t.Error("esperado erro para chave com tamanho inválido")
+	}
+}
@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// ErrInvalidConstraint indica uma restrição de versão mal formada
+var ErrInvalidConstraint = errors.New("restrição de versão inválida")
+
+// ParseLabRef separa uma referência no formato "lab@restrição" (por exemplo,
+// "linux-basico@^1.2") no ID do laboratório e na restrição de versão
+func ParseLabRef(ref string) (id, constraint string) {
+	id, constraint, _ = strings.Cut(ref, "@")
+	return id, constraint
+}
+
+// selectVersion escolhe a versão mais alta que atende a restrição. Sem
+// restrição é escolhida a versão estável mais alta; as pré-lançamento só são
+// usadas quando não há versão estável, e as entradas sem versão semântica
+// apenas quando nenhuma outra existe. Em caso de empate vence a primeira
+// entrada, na ordem dos repositórios.
+func selectVersion(candidates []ResolvedLab, constraint string) (*ResolvedLab, error) {
+	var constraints *semver.Constraints
+	if constraint != "" {
+		c, err := semver.NewConstraint(constraint)
+		if err != nil {
+			return nil, fmt.Errorf("%w '%s': %v", ErrInvalidConstraint, constraint, err)
+		}
+		constraints = c
+	}
+
+	var stable, prerelease *ResolvedLab
+	var stableVersion, prereleaseVersion *semver.Version
+	for i := range candidates {
+		v, err := semver.NewVersion(candidates[i].Version)
+		if err != nil {
+			continue
+		}
+		if constraints != nil && !constraints.Check(v) {
+			continue
+		}
+		if constraints == nil && v.Prerelease() != "" {
+			if prereleaseVersion == nil || v.GreaterThan(prereleaseVersion) {
+				prerelease, prereleaseVersion = &candidates[i], v
+			}
+			continue
+		}
+		if stableVersion == nil || v.GreaterThan(stableVersion) {
+			stable, stableVersion = &candidates[i], v
+		}
+	}
+
+	switch {
+	case stable != nil:
+		return stable, nil
+	case constraints != nil:
+		return nil, nil
+	case prerelease != nil:
+		return prerelease, nil
+	case len(candidates) > 0:
+		return &candidates[0], nil
+	}
+	return nil, nil
+}
+
+// sortVersions ordena as entradas da versão mais alta para a mais baixa,
+// deixando as sem versão semântica no fim
+func sortVersions(labs []ResolvedLab) {
+	sort.SliceStable(labs, func(i, j int) bool {
+		vi, erri := semver.NewVersion(labs[i].Version)
+		vj, errj := semver.NewVersion(labs[j].Version)
+		if erri != nil || errj != nil {
+			return erri == nil && errj != nil
+		}
+		return vi.GreaterThan(vj)
+	})
+}
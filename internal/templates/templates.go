@@ -11,6 +11,9 @@ import (
 //go:embed manifests/*.yaml manifests_es/*.yaml
 var ManifestFS embed.FS
 
+//go:embed cluster/*.yaml cluster/*.tmpl
+var clusterFS embed.FS
+
 func GetManifest(name string) ([]byte, error) {
 	dir := "manifests"
 	if common.Lang() == "es" {
@@ -39,3 +42,9 @@ func ListManifests() ([]string, error) {
 
 	return manifests, nil
 }
+
+// GetClusterTemplate retorna um dos arquivos de configuração de cluster
+// embutidos (configuração do kind e serviços NodePort)
+func GetClusterTemplate(name string) ([]byte, error) {
+	return fs.ReadFile(clusterFS, "cluster/"+name)
+}
@@ -13,6 +13,6 @@ func main() {
 	common.SetLanguage(cfg.Language)
 	if err := cmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Erro ao executar o comando: %s\n", err)
-		os.Exit(1)
+		os.Exit(common.ExitCode(err))
 	}
 }